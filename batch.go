@@ -0,0 +1,117 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BatchError reports the parameters of a batch evaluation that failed.
+type BatchError struct {
+	// Errors are the errors of the parameters by their index, nil for successful evaluations.
+	Errors []error
+	// Failed is the number of failed evaluations.
+	Failed int
+}
+
+func (err *BatchError) Error() string {
+	for i, e := range err.Errors {
+		if e != nil {
+			return fmt.Sprintf("%d of %d evaluations failed, first at %d: %v", err.Failed, len(err.Errors), i, e)
+		}
+	}
+	return fmt.Sprintf("%d of %d evaluations failed", err.Failed, len(err.Errors))
+}
+
+// Unwrap returns the first error of the batch.
+func (err *BatchError) Unwrap() error {
+	for _, e := range err.Errors {
+		if e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+type batchWorkersKey struct{}
+
+// ContextWithBatchWorkers returns a copy of c in which EvalBatch evaluates the parameters with the given number of goroutines.
+func ContextWithBatchWorkers(c context.Context, workers int) context.Context {
+	return context.WithValue(c, batchWorkersKey{}, workers)
+}
+
+// EvalBatch evaluates e for each of the parameters and returns the results in their order.
+// The parameters are evaluated one after another unless c is given by ContextWithBatchWorkers.
+// The results of failing evaluations are nil and the error is a *BatchError holding the error of each parameter.
+// A cancelled context stops the batch with the error of the context.
+func (e Evaluable) EvalBatch(c context.Context, params []interface{}) ([]interface{}, error) {
+	if c == nil {
+		c = context.Background()
+	}
+	results := make([]interface{}, len(params))
+	var errs []error
+	failed := 0
+	var mutex sync.Mutex
+	evaluate := func(i int) {
+		v, err := e(c, params[i])
+		if err == nil {
+			results[i] = v
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		if errs == nil {
+			errs = make([]error, len(params))
+		}
+		errs[i] = err
+		failed++
+	}
+
+	workers, _ := c.Value(batchWorkersKey{}).(int)
+	if workers <= 1 {
+		for i := range params {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
+			evaluate(i)
+		}
+	} else {
+		indices := make(chan int)
+		wg := sync.WaitGroup{}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indices {
+					evaluate(i)
+				}
+			}()
+		}
+	send:
+		for i := range params {
+			select {
+			case indices <- i:
+			case <-c.Done():
+				break send
+			}
+		}
+		close(indices)
+		wg.Wait()
+	}
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	if failed > 0 {
+		return results, &BatchError{Errors: errs, Failed: failed}
+	}
+	return results, nil
+}
+
+// EvaluateBatch parses the expression once and evaluates it for each of the parameters like Evaluable.EvalBatch.
+func (l Language) EvaluateBatch(c context.Context, expression string, params []interface{}) ([]interface{}, error) {
+	eval, err := l.NewEvaluableWithContext(c, expression)
+	if err != nil {
+		return nil, err
+	}
+	return eval.EvalBatch(c, params)
+}
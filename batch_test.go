@@ -0,0 +1,57 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEvalBatch(t *testing.T) {
+	params := make([]interface{}, 1000)
+	want := make([]interface{}, len(params))
+	for i := range params {
+		params[i] = map[string]interface{}{"score": float64(i)}
+		want[i] = i >= 500
+	}
+	eval, err := Full().NewEvaluable("score >= 500")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{0, 1, 4} {
+		got, err := eval.EvalBatch(ContextWithBatchWorkers(context.Background(), workers), params)
+		if err != nil {
+			t.Fatalf("EvalBatch() with %d workers error = %v", workers, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("EvalBatch() with %d workers = %v, want %v", workers, got, want)
+		}
+	}
+
+	for _, c := range []context.Context{nil, ContextWithBatchWorkers(context.Background(), 2)} {
+		got, err := Full().EvaluateBatch(c, "a.b * 2", []interface{}{
+			map[string]interface{}{"a": map[string]interface{}{"b": 1.}},
+			map[string]interface{}{"a": 1.},
+			map[string]interface{}{"a": map[string]interface{}{"b": 3.}},
+		})
+		var batch *BatchError
+		if !errors.As(err, &batch) {
+			t.Fatalf("EvaluateBatch() error = %v, want BatchError", err)
+		}
+		if batch.Failed != 1 || batch.Errors[0] != nil || batch.Errors[1] == nil || batch.Errors[2] != nil {
+			t.Errorf("EvaluateBatch() errors = %v", batch.Errors)
+		}
+		if !reflect.DeepEqual(got, []interface{}{2., nil, 6.}) {
+			t.Errorf("EvaluateBatch() = %v", got)
+		}
+	}
+
+	if _, err := Full().EvaluateBatch(nil, "1 +", params); err == nil {
+		t.Error("EvaluateBatch(1 +) expected parsing error")
+	}
+	c, cancel := context.WithCancel(ContextWithBatchWorkers(context.Background(), 2))
+	cancel()
+	if _, err := eval.EvalBatch(c, params); !errors.Is(err, context.Canceled) {
+		t.Errorf("EvalBatch() with cancelled context error = %v, want context.Canceled", err)
+	}
+}
@@ -37,6 +37,15 @@ func BenchmarkGval(bench *testing.B) {
 				"requests_succeeded": 90.0,
 			},
 		},
+		{
+			// Chained arithmetic passes intermediate results unboxed
+			name:       "arithmetic",
+			expression: "(requests_made * requests_succeeded / 100 + 2 ** 3) * 1.5 - requests_made >= 90",
+			parameter: map[string]interface{}{
+				"requests_made":      99.0,
+				"requests_succeeded": 90.0,
+			},
+		},
 		{
 			// All major possibilities in one expression.
 			name: "complex",
@@ -136,15 +145,34 @@ func BenchmarkGval(bench *testing.B) {
 			bench.Fatal(err)
 		}
 		bench.Run(benchmark.name+"_evaluation", func(bench *testing.B) {
+			bench.ReportAllocs()
 			for i := 0; i < bench.N; i++ {
 				eval(context.Background(), benchmark.parameter)
 			}
 		})
+		program, err := Full().Compile(benchmark.expression)
+		if err != nil {
+			bench.Fatal(err)
+		}
+		bench.Run(benchmark.name+"_program", func(bench *testing.B) {
+			bench.ReportAllocs()
+			for i := 0; i < bench.N; i++ {
+				program.Evaluate(context.Background(), benchmark.parameter)
+			}
+		})
 		bench.Run(benchmark.name+"_parsing", func(bench *testing.B) {
+			bench.ReportAllocs()
 			for i := 0; i < bench.N; i++ {
 				Full().NewEvaluable(benchmark.expression)
 			}
 		})
+		bench.Run(benchmark.name+"_parsing_reused", func(bench *testing.B) {
+			reused := Full(ReuseParsers())
+			bench.ReportAllocs()
+			for i := 0; i < bench.N; i++ {
+				reused.NewEvaluable(benchmark.expression)
+			}
+		})
 
 	}
 }
@@ -0,0 +1,43 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+)
+
+// EvaluateBool evaluates given parameter with given expression in gval full language to a bool.
+// Unlike Evaluable.EvalBool it does not convert the result, so "true" or 1 fail instead of evaluating to true.
+func EvaluateBool(expression string, parameter interface{}, opts ...Language) (bool, error) {
+	l := full
+	if len(opts) > 0 {
+		l = NewLanguage(append([]Language{l}, opts...)...)
+	}
+	eval, err := l.BoolEvaluable(expression)
+	if err != nil {
+		return false, err
+	}
+	b, err := eval.EvalBool(context.Background(), parameter)
+	if err != nil {
+		return false, fmt.Errorf("can not evaluate %s: %w", expression, err)
+	}
+	return b, nil
+}
+
+// BoolEvaluable returns an Evaluable for given expression that fails if the result is not a bool.
+// Its EvalBool therefore never converts results like "true", 1 or nil.
+func (l Language) BoolEvaluable(expression string) (Evaluable, error) {
+	eval, err := l.NewEvaluable(expression)
+	if err != nil {
+		return nil, err
+	}
+	return func(c context.Context, parameter interface{}) (interface{}, error) {
+		v, err := eval(c, parameter)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("expected bool but got %v (%T)", v, v)
+		}
+		return v, nil
+	}, nil
+}
@@ -0,0 +1,54 @@
+package gval
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateBool(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		parameter  interface{}
+		want       bool
+		wantErr    string
+	}{
+		{name: "true", expression: "a > 1", parameter: map[string]interface{}{"a": 2}, want: true},
+		{name: "false", expression: "a && false", parameter: map[string]interface{}{"a": true}},
+		{name: "string", expression: `"true"`, wantErr: "expected bool but got true (string)"},
+		{name: "number", expression: "1", wantErr: "expected bool but got 1 (float64)"},
+		{name: "nil", expression: "a", parameter: map[string]interface{}{}, wantErr: "expected bool but got <nil> (<nil>)"},
+		{name: "parse error", expression: "a >", wantErr: "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateBool(tt.expression, tt.parameter)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("EvaluateBool() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EvaluateBool() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguage_BoolEvaluable(t *testing.T) {
+	eval, err := Full().BoolEvaluable("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := eval.EvalBool(context.Background(), map[string]interface{}{"a": true}); err != nil || !b {
+		t.Errorf("EvalBool() = %v, %v, want true", b, err)
+	}
+	if _, err := eval.EvalBool(context.Background(), map[string]interface{}{"a": "yes"}); err == nil {
+		t.Error("EvalBool() of a string should fail")
+	}
+}
@@ -0,0 +1,180 @@
+package gval
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NewConstant returns the syntax tree of the literal of value, which is nil, a bool, a string, a number
+// or an array or object of them. Numbers are float64 like parsed number literals.
+// It returns an error for values without literal.
+func NewConstant(value interface{}) (*Node, error) {
+	value = literalValue(value)
+	text, ok := literal(value)
+	if !ok {
+		return nil, fmt.Errorf("no literal of %T", value)
+	}
+	return &Node{
+		Kind:       ConstantNode,
+		Value:      value,
+		End:        len(text),
+		source:     text,
+		end:        len(text),
+		precedence: ^operatorPrecedence(0),
+		constant:   true,
+	}, nil
+}
+
+// NewVariable returns the syntax tree of the variable selecting path from the parameter like a.b["c d"][0].
+func NewVariable(path ...string) *Node {
+	b := &strings.Builder{}
+	writePath(b, path)
+	return &Node{
+		Kind:       VariableNode,
+		Path:       path,
+		End:        b.Len(),
+		source:     b.String(),
+		end:        b.Len(),
+		precedence: ^operatorPrecedence(0),
+	}
+}
+
+// NewInfix returns the syntax tree of the infix operation a operator b.
+// Operands are enclosed in parentheses by the precedence of the operators in Full,
+// operators not defined by Full are grouped like the lowest precedence.
+func NewInfix(operator string, a, b *Node) *Node {
+	var pre operatorPrecedence
+	right := false
+	if op, ok := full.operators[operator]; ok {
+		pre = op.precedence()
+		if op, ok := op.(*infix); ok {
+			right = op.rightAssociative
+		}
+	}
+	t := &nodeText{}
+	t.child(a, isOperation(a) && (a.precedence < pre || right && a.precedence == pre))
+	t.WriteString(" " + operator + " ")
+	t.child(b, isOperation(b) && (b.precedence < pre || !right && b.precedence == pre))
+	n := t.node(InfixNode)
+	n.Operator, n.precedence, n.rightAssociative = operator, pre, right
+	return n
+}
+
+// NewPrefix returns the syntax tree of the prefix operator applied to a like !a or -(a + b).
+func NewPrefix(operator string, a *Node) *Node {
+	t := &nodeText{}
+	t.WriteString(operator)
+	enclosed := isOperation(a)
+	if !enclosed && strings.IndexFunc(operator, unicode.IsLetter) >= 0 {
+		t.WriteString(" ")
+	}
+	t.child(a, enclosed)
+	return t.node(OperandNode)
+}
+
+// NewCall returns the syntax tree of the call of the function name with args like f(a, b).
+func NewCall(name string, args ...*Node) *Node {
+	t := &nodeText{}
+	t.WriteString(name + "(")
+	for i, arg := range args {
+		if i > 0 {
+			t.WriteString(", ")
+		}
+		t.child(arg, false)
+	}
+	t.WriteString(")")
+	return t.node(OperandNode)
+}
+
+// CompileNode returns the Evaluable of a syntax tree created by Parse or the builders like NewInfix.
+// The tree is compiled from its Format, so it is evaluated as if the formatted expression was parsed by the Language.
+func (l Language) CompileNode(node *Node) (Evaluable, error) {
+	if node == nil {
+		return nil, fmt.Errorf("no syntax tree to compile")
+	}
+	return l.NewEvaluable(Format(node))
+}
+
+// literalValue returns value with numbers converted to float64 like the values of literals.
+func literalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string:
+		return v
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, e := range v {
+			values[i] = literalValue(e)
+		}
+		return values
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			values[k] = literalValue(e)
+		}
+		return values
+	}
+	if f, ok := convertToFloat(value); ok {
+		return f
+	}
+	return value
+}
+
+func isOperation(n *Node) bool {
+	return !n.parenthesized && (n.Kind == InfixNode || n.Kind == PostfixNode)
+}
+
+// nodeText builds the source of a node whose children are copied into it.
+type nodeText struct {
+	strings.Builder
+	children []*Node
+}
+
+// child appends the text of n, enclosed in parentheses if enclosed is true.
+func (t *nodeText) child(n *Node, enclosed bool) {
+	pos := t.Len()
+	if enclosed {
+		t.WriteString("(")
+	}
+	c := n.shifted(t.Len() - n.Pos)
+	t.WriteString(n.Text())
+	if enclosed {
+		t.WriteString(")")
+		c.Pos, c.End, c.parenthesized = pos, t.Len(), true
+	}
+	t.children = append(t.children, c)
+}
+
+// node returns the node of kind spanning the text with the children appended.
+func (t *nodeText) node(kind NodeKind) *Node {
+	source := t.String()
+	for _, c := range t.children {
+		c.setSource(source)
+	}
+	return &Node{
+		Kind:       kind,
+		Children:   t.children,
+		End:        len(source),
+		source:     source,
+		end:        len(source),
+		precedence: ^operatorPrecedence(0),
+	}
+}
+
+// shifted returns a copy of the tree of n with all offsets moved by shift.
+func (n *Node) shifted(shift int) *Node {
+	c := *n
+	c.Pos, c.End, c.start, c.end = n.Pos+shift, n.End+shift, n.start+shift, n.end+shift
+	c.Children = make([]*Node, len(n.Children))
+	for i, child := range n.Children {
+		c.Children[i] = child.shifted(shift)
+	}
+	return &c
+}
+
+func (n *Node) setSource(source string) {
+	n.source = source
+	for _, child := range n.Children {
+		child.setSource(source)
+	}
+}
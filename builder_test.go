@@ -0,0 +1,68 @@
+package gval
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	parsed, err := Full().Parse(`b || c`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	constant := func(value interface{}) *Node {
+		n, err := NewConstant(value)
+		if err != nil {
+			t.Fatalf("NewConstant(%v) error = %v", value, err)
+		}
+		return n
+	}
+	for _, test := range []struct {
+		node *Node
+		want string
+		// value is the result for the parameter below
+		value interface{}
+	}{
+		{NewInfix("&&", NewVariable("a"), constant(5)), `a && 5`, true},
+		{NewInfix("*", NewInfix("+", NewVariable("x"), constant(1)), constant(2)), `(x + 1) * 2`, 8.},
+		{NewInfix("-", NewVariable("x"), NewInfix("-", constant(1), constant(2))), `x - (1 - 2)`, 4.},
+		{NewInfix("+", NewInfix("*", NewVariable("x"), constant(1)), constant(2)), `x * 1 + 2`, 5.},
+		{NewInfix("**", constant(2), NewInfix("**", constant(1), constant(2))), `2 ** (1 ** 2)`, 2.},
+		{NewInfix("&&", parsed, NewPrefix("!", NewVariable("user", "first name"))), `(b || c) && !user["first name"]`, true},
+		{NewPrefix("-", NewInfix("+", NewVariable("x"), NewVariable("items", "0"))), `-(x + items[0])`, -5.},
+		{NewCall("len", NewInfix("+", constant("a"), constant("\"b\""))), `len("a" + "\"b\"")`, 4.},
+		{NewInfix("in", constant("x"), constant([]interface{}{"x", 1, nil})), `"x" in ["x", 1, nil]`, true},
+		{NewInfix("==", constant(map[string]interface{}{"k": true}), constant(map[string]interface{}{"k": true})), `{"k":true} == {"k":true}`, true},
+	} {
+		if got := Format(test.node); got != test.want {
+			t.Errorf("Format() = %s, want %s", got, test.want)
+			continue
+		}
+		if got := test.node.Text(); got != test.want {
+			t.Errorf("Text() = %s, want %s", got, test.want)
+		}
+		eval, err := Full().CompileNode(test.node)
+		if err != nil {
+			t.Errorf("CompileNode(%s) error = %v", test.want, err)
+			continue
+		}
+		got, err := eval(nil, map[string]interface{}{
+			"a": true, "b": false, "c": true, "x": 3.,
+			"user":  map[string]interface{}{"first name": false},
+			"items": []interface{}{2.},
+		})
+		if err != nil || !reflect.DeepEqual(got, test.value) {
+			t.Errorf("CompileNode(%s)() = %v, %v, want %v", test.want, got, err, test.value)
+		}
+	}
+
+	if _, err := Full().CompileNode(nil); err == nil {
+		t.Error("CompileNode(nil) expected error")
+	}
+	if _, err := Full().CompileNode(NewInfix("unknown", constant(1), constant(2))); err == nil {
+		t.Error("CompileNode() of an unknown operator expected error")
+	}
+	if n, err := NewConstant(struct{}{}); err == nil {
+		t.Errorf("NewConstant(struct{}{}) = %v, expected error", Format(n))
+	}
+}
@@ -0,0 +1,105 @@
+package gval
+
+import (
+	"sort"
+)
+
+// Canonical returns the canonical text of the expression, which is the same for expressions that differ only by
+//
+//	the Format of their text, e.g. (a)+b*2 and a + b * 2
+//	constant sub-expressions, see Optimize, e.g. x > 2*3 and x > 6
+//	the order of the operands of &&, || and their chains, e.g. a && (b && c) and c && b && a
+//	the order of the operands of == and !=, e.g. a == 1 and 1 == a
+//	the direction of comparisons, e.g. a > b and b < a
+//
+// Reordered operands are sorted by their canonical text. Expressions with the same canonical text
+// evaluate to the same value, but may fail with different errors as their operands are evaluated in another order.
+func (l Language) Canonical(expression string) (string, error) {
+	optimized, err := l.Optimize(expression)
+	if err != nil {
+		return "", err
+	}
+	node, err := l.Parse(optimized)
+	if err != nil {
+		return "", err
+	}
+	return Format(canonicalNode(node)), nil
+}
+
+// Equal returns if the expressions a and b have the same Canonical text in lang,
+// e.g. to find duplicates like a > 1 && b and b && 1 < a.
+func Equal(a, b string, lang Language) (bool, error) {
+	return lang.Equal(a, b)
+}
+
+// Equal returns if the expressions a and b have the same Canonical text.
+func (l Language) Equal(a, b string) (bool, error) {
+	x, err := l.Canonical(a)
+	if err != nil {
+		return false, err
+	}
+	y, err := l.Canonical(b)
+	if err != nil {
+		return false, err
+	}
+	return x == y, nil
+}
+
+func canonicalNode(n *Node) *Node {
+	if n == nil {
+		return nil
+	}
+	switch n.Kind {
+	case InfixNode:
+		a, b := canonicalNode(n.Children[0]), canonicalNode(n.Children[1])
+		switch n.Operator {
+		case "&&", "||":
+			operands := sortedNodes(append(junctionOperands(a, n.Operator), junctionOperands(b, n.Operator)...))
+			c := operands[0]
+			for _, operand := range operands[1:] {
+				c = NewInfix(n.Operator, c, operand)
+			}
+			return c
+		case "==", "!=":
+			operands := sortedNodes([]*Node{a, b})
+			return NewInfix(n.Operator, operands[0], operands[1])
+		}
+		if n.Operator == ">" || n.Operator == ">=" {
+			return NewInfix(mirroredComparisons[n.Operator], b, a)
+		}
+		return NewInfix(n.Operator, a, b)
+	case OperandNode, PostfixNode:
+		if len(n.Children) == 0 {
+			return n
+		}
+		t := &nodeText{}
+		pos := n.start
+		for _, child := range n.Children {
+			t.WriteString(n.source[pos:child.Pos])
+			c := canonicalNode(child)
+			t.child(c, child.parenthesized && isOperation(c))
+			pos = child.End
+		}
+		t.WriteString(n.source[pos:n.end])
+		m := t.node(n.Kind)
+		m.Operator, m.precedence = n.Operator, n.precedence
+		return m
+	}
+	return n
+}
+
+// junctionOperands returns the operands of the chain of the junction operator n is part of, n otherwise.
+func junctionOperands(n *Node, operator string) []*Node {
+	if n.Kind != InfixNode || n.Operator != operator {
+		return []*Node{n}
+	}
+	return append(junctionOperands(n.Children[0], operator), junctionOperands(n.Children[1], operator)...)
+}
+
+// sortedNodes sorts nodes by their Format.
+func sortedNodes(nodes []*Node) []*Node {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return Format(nodes[i]) < Format(nodes[j])
+	})
+	return nodes
+}
@@ -0,0 +1,54 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	l := Full()
+	for _, test := range []struct {
+		a, b  string
+		equal bool
+	}{
+		{`(a)+b*2`, `a + b * 2`, true},
+		{`x > 2*3`, `x > 6`, true},
+		{`a && (b && c)`, `c && b && a`, true},
+		{`a || b && c`, `c && b || a`, true},
+		{`a == 1 && b != "x"`, `"x" != b && 1 == a`, true},
+		{`a > b`, `b < a`, true},
+		{`a >= 2 - 1`, `1 <= a`, true},
+		{`f(b && a, x > 1)`, `f(a && b, 1 < x)`, true},
+		{`!(b || a) ? y == 1 : z`, `!(a || b) ? 1 == y : z`, true},
+		{`a - b`, `b - a`, false},
+		{`a + "x"`, `"x" + a`, false},
+		{`a && b || c`, `a && (b || c)`, false},
+		{`a > b`, `a < b`, false},
+		{`(1 < n) < 4`, `1 < n && n < 4`, false},
+		{`(1 < n) < 4`, `1 < n < 4`, false},
+		{`(n > 1) > 0`, `n > 1 > 0`, false},
+		{`(x ? 1 : y) ? 2 : 3`, `x ? 1 : y ? 2 : 3`, false},
+		{`1 < n < 4`, `1 < n && n < 4`, true},
+	} {
+		got, err := l.Equal(test.a, test.b)
+		if err != nil {
+			t.Errorf("Equal(%s, %s) error = %v", test.a, test.b, err)
+		} else if got != test.equal {
+			x, _ := l.Canonical(test.a)
+			y, _ := l.Canonical(test.b)
+			t.Errorf("Equal(%s, %s) = %v, want %v with canonical texts %s and %s", test.a, test.b, got, test.equal, x, y)
+		}
+	}
+
+	if got, err := l.Canonical(`z || (b < a) && 1 == x`); err != nil || got != `1 == x && b < a || z` {
+		t.Errorf("Canonical() = %s, %v", got, err)
+	}
+	if _, err := l.Equal(`a +`, `a`); err == nil {
+		t.Error("Equal() of an invalid expression expected error")
+	}
+	if got, err := Equal(`x > 2*3 && ok`, `ok && 6 < x`, l); err != nil || !got {
+		t.Errorf("gval.Equal() = %v, %v, want true", got, err)
+	}
+	if _, err := Equal(`a +`, `a`, l); err == nil {
+		t.Error("gval.Equal() of an invalid expression expected error")
+	}
+}
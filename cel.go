@@ -0,0 +1,204 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/scanner"
+	"unicode/utf8"
+)
+
+// CEL returns a Language with the surface syntax of the Common Expression Language
+// on top of the Full language:
+//
+//	s.contains(t), s.startsWith(t), s.endsWith(t)   string methods
+//	s.matches(pattern), matches(s, pattern)          regular expression match
+//	x.size(), size(x)                                number of characters of strings and of elements of lists and maps
+//	has(a.b)                                         true iff a has the field or key b
+//	x in list, key in map                            membership of list elements and map keys
+//	null                                             nil
+//
+// Methods can be called on variables, literals, function results and parenthesized expressions,
+// e.g. "abc".size() or (a + b).startsWith("x"). A map key named like a method hides the method.
+//
+// CEL's type system is not implemented: numbers are float64 without separate int and uint types,
+// so 7 / 2 is 3.5, and && and || do not absorb errors of their operands.
+func CEL() Language {
+	return cel
+}
+
+var cel = NewLanguage(full, regexLanguage,
+	Constant("null", nil),
+	InfixContextOperator("in", celIn),
+	PostfixOperator(".", parseCELMember),
+	Precedence(".", 250),
+	Function("size", func(arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 1 {
+			return nil, fmt.Errorf("size() expects exactly one argument")
+		}
+		return celSize(arguments[0])
+	}),
+	celHas,
+	VariableSelector(celSelector),
+)
+
+// celMethod is a method of CEL called on receiver.
+type celMethod func(c context.Context, receiver interface{}, arguments ...interface{}) (interface{}, error)
+
+var celMethods = map[string]celMethod{
+	"size": func(c context.Context, receiver interface{}, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 0 {
+			return nil, fmt.Errorf("size() expects no arguments")
+		}
+		return celSize(receiver)
+	},
+	"contains":   celStringMethod("contains", strings.Contains),
+	"startsWith": celStringMethod("startsWith", strings.HasPrefix),
+	"endsWith":   celStringMethod("endsWith", strings.HasSuffix),
+	"matches": func(c context.Context, receiver interface{}, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 1 {
+			return nil, fmt.Errorf("matches() expects exactly one string argument")
+		}
+		s, re, err := regexArguments(c, "matches", receiver, arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(s), nil
+	},
+}
+
+func celStringMethod(name string, f func(s, t string) bool) celMethod {
+	return func(c context.Context, receiver interface{}, arguments ...interface{}) (interface{}, error) {
+		s, ok := receiver.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s() expects a string but got %v (%T)", name, receiver, receiver)
+		}
+		if len(arguments) != 1 {
+			return nil, fmt.Errorf("%s() expects exactly one string argument", name)
+		}
+		t, ok := arguments[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s() expects exactly one string argument", name)
+		}
+		return f(s, t), nil
+	}
+}
+
+// celSize returns the number of characters of a string or the length of a list or map.
+func celSize(x interface{}) (interface{}, error) {
+	if s, ok := x.(string); ok {
+		return float64(utf8.RuneCountInString(s)), nil
+	}
+	switch v := resolvePotentialPointer(reflect.ValueOf(x)); v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), nil
+	}
+	return nil, fmt.Errorf("size() expects a string, list or map but got %v (%T)", x, x)
+}
+
+// celSelector selects the last key of path as method of the selected value
+// if the key names a CEL method and the value has no such key.
+func celSelector(path Evaluables) Evaluable {
+	selectPath := variable(path)
+	last := path[len(path)-1]
+	if !last.IsConst() {
+		return selectPath
+	}
+	name, err := last.EvalString(nil, nil)
+	method, ok := celMethods[name]
+	if err != nil || !ok {
+		return selectPath
+	}
+	parent := func(c context.Context, v interface{}) (interface{}, error) { return v, nil }
+	if len(path) > 1 {
+		parent = variable(path[:len(path)-1])
+	}
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		receiver, err := parent(c, v)
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := mapValue(receiver, name); ok {
+			return value, nil
+		}
+		return func(c context.Context, arguments ...interface{}) (interface{}, error) {
+			return method(c, receiver, arguments...)
+		}, nil
+	}
+}
+
+// parseCELMember parses the selection of a field or the call of a method after a dot,
+// e.g. .size() in "abc".size().
+func parseCELMember(c context.Context, p *Parser, e Evaluable) (Evaluable, error) {
+	if p.Scan() != scanner.Ident {
+		return nil, p.Expected("member", scanner.Ident)
+	}
+	name := p.TokenText()
+	member := p.selectFrom(e, []Evaluable{p.Const(name)}, false)
+	if p.Scan() != '(' {
+		p.Camouflage("member", '(')
+		return member, nil
+	}
+	args, err := p.parseArguments(c)
+	if err != nil {
+		return nil, err
+	}
+	return p.callEvaluable(name, member, args...), nil
+}
+
+// celHas is the has macro testing the presence of the last field of a selection like a.b.
+var celHas = func() Language {
+	l := newLanguage()
+	l.prefixes[l.makePrefixKey("has")] = func(c context.Context, p *Parser) (Evaluable, error) {
+		if p.Scan() != '(' {
+			return nil, p.Expected("has", '(')
+		}
+		if p.Scan() != scanner.Ident {
+			return nil, p.Expected("has", scanner.Ident)
+		}
+		keys := []Evaluable{p.Const(p.TokenText())}
+		for scan := p.Scan(); scan != ')'; scan = p.Scan() {
+			if scan != '.' {
+				return nil, p.Expected("has", '.', ')')
+			}
+			if p.Scan() != scanner.Ident {
+				return nil, p.Expected("has field", scanner.Ident)
+			}
+			keys = append(keys, p.Const(p.TokenText()))
+		}
+		if len(keys) < 2 {
+			return nil, fmt.Errorf("has() expects a field selection like a.b")
+		}
+		parent, field := variable(keys[:len(keys)-1]), keys[len(keys)-1:]
+		vars := p.vars()
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			o, err := parent(c, v)
+			if err != nil {
+				return nil, err
+			}
+			if o == nil {
+				return false, nil
+			}
+			_, ok := selectPresent(c, vars, o, field)
+			return ok, nil
+		}, nil
+	}
+	return l
+}()
+
+// celIn tests whether a is an element of the list b or a key of the map b.
+func celIn(c context.Context, a, b interface{}) (interface{}, error) {
+	if isMap(b) {
+		key, ok := a.(string)
+		if !ok {
+			key = fmt.Sprint(a)
+		}
+		_, ok = mapValue(b, key)
+		return ok, nil
+	}
+	if s, ok := convertToSlice(b); ok {
+		return inArray(c, a, s)
+	}
+	return nil, fmt.Errorf("expected list or map for in operator but got %T", b)
+}
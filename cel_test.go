@@ -0,0 +1,121 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestCEL(t *testing.T) {
+	request := map[string]interface{}{
+		"auth": map[string]interface{}{"claims": map[string]interface{}{"email": "admin@example.com"}},
+		"path": "/api/v1/users",
+		"tags": []interface{}{"a", "b"},
+		"size": 3.0,
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "startsWith",
+				extension:  CEL(),
+				expression: `path.startsWith("/api")`,
+				parameter:  request,
+				want:       true,
+			},
+			{
+				name:       "nested receiver",
+				extension:  CEL(),
+				expression: `auth.claims.email.endsWith("@example.com")`,
+				parameter:  request,
+				want:       true,
+			},
+			{
+				name:       "method on literal",
+				extension:  CEL(),
+				expression: `"gval".contains("va") && "abc".size() == 3`,
+				want:       true,
+			},
+			{
+				name:       "method on parentheses",
+				extension:  CEL(),
+				expression: `(path + "/1").matches("[0-9]$")`,
+				parameter:  request,
+				want:       true,
+			},
+			{
+				name:       "method binds tighter than operators",
+				extension:  CEL(),
+				expression: `1 + tags.size() * 2`,
+				parameter:  request,
+				want:       5.0,
+			},
+			{
+				name:       "size function",
+				extension:  CEL(),
+				expression: `size(tags) + size({"a": 1}) + size("äö")`,
+				parameter:  request,
+				want:       5.0,
+			},
+			{
+				name:       "key hides method",
+				extension:  CEL(),
+				expression: `{"size": 2}.size + tags.size()`,
+				parameter:  request,
+				want:       4.0,
+			},
+			{
+				name:       "field after literal",
+				extension:  CEL(),
+				expression: `{"a": {"b": 2}}.a.b`,
+				want:       2.0,
+			},
+			{
+				name:       "has",
+				extension:  CEL(),
+				expression: `has(auth.claims.email) && !has(auth.claims.name)`,
+				parameter:  request,
+				want:       true,
+			},
+			{
+				name:       "has on missing parent",
+				extension:  CEL(),
+				expression: `has(user.name)`,
+				parameter:  request,
+				want:       false,
+			},
+			{
+				name:       "has without selection",
+				extension:  CEL(),
+				expression: `has(auth)`,
+				wantErr:    "has() expects a field selection",
+			},
+			{
+				name:       "in list",
+				extension:  CEL(),
+				expression: `"b" in tags`,
+				parameter:  request,
+				want:       true,
+			},
+			{
+				name:       "in map",
+				extension:  CEL(),
+				expression: `"claims" in auth && !("x" in auth)`,
+				parameter:  request,
+				want:       true,
+			},
+			{
+				name:       "null",
+				extension:  CEL(),
+				expression: `auth.missing == null`,
+				parameter:  request,
+				want:       true,
+			},
+			{
+				name:       "method of wrong type",
+				extension:  CEL(),
+				expression: `tags.startsWith("a")`,
+				parameter:  request,
+				wantErr:    "startsWith() expects a string",
+			},
+		},
+		t,
+	)
+}
@@ -0,0 +1,40 @@
+package gval
+
+import (
+	"context"
+)
+
+// ChainedComparisons returns a Language chaining the given comparison operators like Python does,
+// e.g. 1 <= x < 10 is evaluated as 1 <= x && x < 10 instead of comparing the boolean 1 <= x with 10.
+// The operand between two comparisons is evaluated for each of them.
+// Parenthesized comparisons like (1 <= x) < 10 are not chained.
+//
+// Full chains <, <=, >= and >. ChainedComparisons() without operators disables chaining.
+func ChainedComparisons(operators ...string) Language {
+	chained := make(map[string]struct{}, len(operators))
+	for _, op := range operators {
+		chained[op] = struct{}{}
+	}
+	l := newLanguage()
+	l.setOption(option{name: "chainedComparisons", value: chained})
+	return l
+}
+
+// chainsComparison returns if the comparison operator op is chained.
+func (l Language) chainsComparison(op string) bool {
+	chained, _ := l.optionValue("chainedComparisons").(map[string]struct{})
+	_, ok := chained[op]
+	return ok
+}
+
+// chainComparisons returns the conjunction of two chained comparisons.
+// The right comparison is only evaluated if the left one is true.
+func chainComparisons(left, right Evaluable) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		l, err := left(c, v)
+		if err != nil || l != true {
+			return l, err
+		}
+		return right(c, v)
+	}
+}
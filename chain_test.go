@@ -0,0 +1,85 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestChainedComparisons(t *testing.T) {
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "inside",
+				expression: `1 <= x < 10`,
+				parameter:  map[string]interface{}{"x": 5},
+				want:       true,
+			},
+			{
+				name:       "outside",
+				expression: `1 <= x < 10`,
+				parameter:  map[string]interface{}{"x": 10},
+				want:       false,
+			},
+			{
+				name:       "long chain",
+				expression: `0 < a < b * 2 <= 10 > c`,
+				parameter:  map[string]interface{}{"a": 1, "b": 5, "c": 3},
+				want:       true,
+			},
+			{
+				name:       "short circuit",
+				expression: `x > 10 > y.z`,
+				parameter:  map[string]interface{}{"x": 5},
+				want:       false,
+			},
+			{
+				name:       "within logic",
+				expression: `a && 1 < x < 3 || false`,
+				parameter:  map[string]interface{}{"a": true, "x": 2},
+				want:       true,
+			},
+			{
+				name:       "constant",
+				expression: `1 < 2 < 3`,
+				want:       true,
+			},
+			{
+				name:       "strings",
+				expression: `"a" < s <= "c"`,
+				parameter:  map[string]interface{}{"s": "b"},
+				want:       true,
+			},
+			{
+				name:       "parenthesized",
+				expression: `(1 < 2) < 3`,
+				want:       false,
+			},
+			{
+				name:       "disabled",
+				expression: `1 < 2 < 3`,
+				extension:  NewLanguage(Full(), ChainedComparisons()),
+				want:       false,
+			},
+			{
+				name:       "equality is not chained",
+				expression: `x > 1 == true`,
+				parameter:  map[string]interface{}{"x": 2},
+				want:       true,
+			},
+		},
+		t,
+	)
+}
+
+func TestChainedComparisonsSyntaxTree(t *testing.T) {
+	node, err := Full().Parse(`(1 <= x < 10) == ok`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := Format(node), `(1 <= x && x < 10) == ok`; got != want {
+		t.Errorf("Format() = %s, want %s", got, want)
+	}
+	chain := node.Children[0]
+	if chain.Operator != "&&" || chain.Children[1].Text() != "x < 10" || chain.Children[1].Children[0] != chain.Children[0].Children[1] {
+		t.Errorf("chained comparison node = %s with children %v", chain.Operator, chain.Children)
+	}
+}
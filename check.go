@@ -0,0 +1,53 @@
+package gval
+
+import (
+	"errors"
+	"strings"
+	"text/scanner"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Check parses the expression and returns all syntax errors in it or nil if the expression is valid.
+// The expression is not evaluated.
+//
+// After a syntax error Check skips the offending token and parses the rest of the expression,
+// skipping further tokens as long as they fail on their own, so a single mistake is reported once.
+// Syntax errors are returned as *SyntaxError with positions in the whole expression.
+// Other errors, e.g. of a constant operation, end the check.
+func (l Language) Check(expression string) []error {
+	var errs []error
+	start := 0
+	// recovering is true while tokens following a syntax error are skipped
+	recovering := false
+	for {
+		rest := expression[start:]
+		first := start + len(rest) - len(strings.TrimLeftFunc(rest, unicode.IsSpace))
+		if recovering && first == len(expression) {
+			return errs
+		}
+		_, err := l.NewEvaluable(rest)
+		if err == nil {
+			return errs
+		}
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) {
+			return append(errs, err)
+		}
+		offset := start + syntax.Offset
+		if !recovering || offset > first {
+			found := *syntax
+			found.Offset = offset
+			found.Line, found.Column = position(expression, offset)
+			errs = append(errs, &found)
+		}
+		if syntax.Got == scanner.EOF || offset >= len(expression) {
+			return errs
+		}
+		size := len(syntax.Token)
+		if size == 0 {
+			_, size = utf8.DecodeRuneInString(expression[offset:])
+		}
+		start, recovering = offset+size, true
+	}
+}
@@ -0,0 +1,73 @@
+package gval
+
+import (
+	"testing"
+	"text/scanner"
+)
+
+func TestLanguage_Check(t *testing.T) {
+	type found struct {
+		offset, line, column int
+		got                  rune
+		unit                 string
+	}
+	tests := []struct {
+		name       string
+		expression string
+		want       []found
+	}{
+		{
+			name:       "valid",
+			expression: `foo.bar > 1 && name == "gval"`,
+		},
+		{
+			name:       "single",
+			expression: `(1 + 2`,
+			want:       []found{{6, 1, 7, scanner.EOF, "parentheses"}},
+		},
+		{
+			name:       "multiple",
+			expression: "foo(1 2) + (3 +) &&\n bar(,)",
+			want: []found{
+				{6, 1, 7, scanner.Int, "arguments"},
+				{15, 1, 16, ')', "extensions"},
+				{25, 2, 6, ',', "extensions"},
+			},
+		},
+		{
+			name:       "trailing operand",
+			expression: `1 2 + 3 4`,
+			want: []found{
+				{2, 1, 3, scanner.Int, "operator"},
+				{8, 1, 9, scanner.Int, "operator"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Full().Check(tt.expression)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Check(%q) = %v, want %d errors", tt.expression, errs, len(tt.want))
+			}
+			for i, err := range errs {
+				got, ok := err.(*SyntaxError)
+				if !ok {
+					t.Fatalf("Check(%q)[%d] = %v, want *SyntaxError", tt.expression, i, err)
+				}
+				if w := tt.want[i]; got.Offset != w.offset || got.Line != w.line || got.Column != w.column || got.Got != w.got || got.Unit != w.unit {
+					t.Errorf("Check(%q)[%d] = %#v, want %v", tt.expression, i, *got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguage_CheckOtherError(t *testing.T) {
+	errs := Full().Check(`1 ** ) + ("a" - 1)`)
+	if len(errs) != 2 {
+		t.Fatalf("Check() = %v, want 2 errors", errs)
+	}
+	if _, ok := errs[1].(*SyntaxError); ok {
+		t.Errorf("Check()[1] = %v, want no *SyntaxError", errs[1])
+	}
+}
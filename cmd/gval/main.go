@@ -0,0 +1,307 @@
+// Command gval evaluates an expression with the languages of github.com/Nandagopi/gval.
+//
+//	gval 'a.b > 5' --params params.json --lang full --output json
+//	gval --repl --params params.json
+//
+// The parameters are read from a JSON file, - reads them from stdin.
+// With --batch the expression is evaluated for each line of an ndjson file and one result is printed per line.
+// With --repl expressions are read from stdin interactively, see package github.com/Nandagopi/gval/repl.
+//
+// Flags:
+//
+//	--params file    JSON parameters of the expression
+//	--batch file     ndjson parameters, one evaluation per line
+//	--lang names     comma separated languages, e.g. full,math,time (default full),
+//	                 extensions like math or strict are added to full unless a language like base or cel is given
+//	--decimal        use DecimalArithmetic and keep the precision of numbers in the parameters
+//	--output format  text or json (default text)
+//	--repl           start a read-eval-print loop with the parameters as variables
+//
+// gval exits with status 1 if the expression or one of the evaluations fails.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Nandagopi/gval"
+	"github.com/Nandagopi/gval/repl"
+)
+
+// languages are the complete languages to select with --lang.
+var languages = map[string]gval.Language{
+	"full":          gval.Full(),
+	"base":          gval.Base(),
+	"arithmetic":    gval.Arithmetic(),
+	"text":          gval.Text(),
+	"propositional": gval.PropositionalLogic(),
+	"cel":           gval.CEL(),
+	"tolerant":      gval.TolerantFull(),
+	"money":         gval.MoneyArithmetic(),
+	"jsonpath":      gval.JSONPath(),
+	"interpolation": gval.Interpolation(),
+}
+
+// extensions are the languages extending others like math. Without a language of languages they extend full.
+var extensions = map[string]gval.Language{
+	"bitmask":  gval.Bitmask(),
+	"json":     gval.JSON(),
+	"strict":   gval.Strict(),
+	"integers": gval.Integers(),
+	"math":     gval.Math(),
+	"time":     gval.Time(),
+	"sets":     gval.Sets(),
+	"regex":    gval.Regex(),
+	"network":  gval.Network(),
+	"semver":   gval.SemVer(),
+	"encoding": gval.Encoding(),
+	"pipeline": gval.Pipeline(),
+	"let":      gval.Let(),
+	"with":     gval.With(),
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gval:", err)
+		os.Exit(1)
+	}
+}
+
+// options are the flags of the command.
+type options struct {
+	params, batch, lang, output string
+	decimal, repl               bool
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	o, expression, err := parseArgs(args, stderr)
+	if err != nil {
+		return err
+	}
+	l, err := language(o.lang, o.decimal)
+	if err != nil {
+		return err
+	}
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output %s, expected text or json", o.output)
+	}
+	if o.repl {
+		return runREPL(o, l, stdin, stdout)
+	}
+	eval, err := l.NewEvaluable(expression)
+	if err != nil {
+		return err
+	}
+	c := context.Background()
+
+	if o.batch != "" {
+		data, err := readFile(o.batch, stdin)
+		if err != nil {
+			return err
+		}
+		params, err := decodeLines(data, o.decimal)
+		if err != nil {
+			return err
+		}
+		results, batchErr := eval.EvalBatch(c, params)
+		var failed *gval.BatchError
+		if batchErr != nil && !errors.As(batchErr, &failed) {
+			return batchErr
+		}
+		for i, r := range results {
+			var err error
+			if failed != nil {
+				err = failed.Errors[i]
+			}
+			if err := write(stdout, o.output, r, err); err != nil {
+				return err
+			}
+		}
+		return batchErr
+	}
+
+	var parameter interface{}
+	if o.params != "" {
+		data, err := readFile(o.params, stdin)
+		if err != nil {
+			return err
+		}
+		if parameter, err = decode(data, o.decimal); err != nil {
+			return fmt.Errorf("invalid parameters: %w", err)
+		}
+	}
+	r, err := eval(c, parameter)
+	if err != nil {
+		return err
+	}
+	return write(stdout, o.output, r, nil)
+}
+
+// runREPL starts a REPL with the parameters as variables.
+func runREPL(o options, l gval.Language, stdin io.Reader, stdout io.Writer) error {
+	variables := map[string]interface{}{}
+	if o.params != "" {
+		data, err := readFile(o.params, stdin)
+		if err != nil {
+			return err
+		}
+		parameter, err := decode(data, o.decimal)
+		if err != nil {
+			return fmt.Errorf("invalid parameters: %w", err)
+		}
+		var ok bool
+		if variables, ok = parameter.(map[string]interface{}); !ok {
+			return fmt.Errorf("expected an object of variables but got %T", parameter)
+		}
+	}
+	return repl.New(l, variables).Run(context.Background(), stdin, stdout)
+}
+
+// parseArgs parses the flags, which may precede or follow the expression.
+func parseArgs(args []string, stderr io.Writer) (options, string, error) {
+	var o options
+	flags := flag.NewFlagSet("gval", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&o.params, "params", "", "JSON `file` of the parameters, - for stdin")
+	flags.StringVar(&o.batch, "batch", "", "ndjson `file` of parameters to evaluate one by one, - for stdin")
+	flags.StringVar(&o.lang, "lang", "full", "comma separated `languages`: "+strings.Join(languageNames(), ", ")+
+		"\nextensions like math are added to full unless another language like base is given")
+	flags.BoolVar(&o.decimal, "decimal", false, "use decimal arithmetic")
+	flags.StringVar(&o.output, "output", "text", "output `format`: text or json")
+	flags.BoolVar(&o.repl, "repl", false, "start a read-eval-print loop")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gval expression [flags]")
+		fmt.Fprintln(stderr, "       gval --repl [flags]")
+		flags.PrintDefaults()
+	}
+
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return o, "", err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if o.repl && len(positional) == 0 {
+		return o, "", nil
+	}
+	if len(positional) != 1 {
+		flags.Usage()
+		return o, "", fmt.Errorf("expected one expression but got %d arguments", len(positional))
+	}
+	return o, positional[0], nil
+}
+
+// language returns the union of the comma separated languages.
+// Extensions are added to the other languages, to full if there are none.
+func language(names string, decimal bool) (gval.Language, error) {
+	var bases, exts []gval.Language
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if l, ok := languages[name]; ok {
+			bases = append(bases, l)
+		} else if l, ok := extensions[name]; ok {
+			exts = append(exts, l)
+		} else {
+			return gval.Language{}, fmt.Errorf("unknown language %s, expected one of %s", name, strings.Join(languageNames(), ", "))
+		}
+	}
+	if len(bases) == 0 {
+		bases = append(bases, gval.Full())
+	}
+	ls := append(bases, exts...)
+	if decimal {
+		ls = append(ls, gval.DecimalArithmetic())
+	}
+	return gval.NewLanguage(ls...), nil
+}
+
+// languageNames returns the sorted names of the languages and extensions.
+func languageNames() []string {
+	names := make([]string, 0, len(languages)+len(extensions))
+	for name := range languages {
+		names = append(names, name)
+	}
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(name string, stdin io.Reader) ([]byte, error) {
+	if name == "-" {
+		return ioutil.ReadAll(stdin)
+	}
+	return ioutil.ReadFile(name)
+}
+
+// decode decodes JSON. In decimal mode numbers are json.Number to keep their precision.
+func decode(data []byte, decimal bool) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	if decimal {
+		d.UseNumber()
+	}
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// decodeLines decodes the non-empty lines of ndjson.
+func decodeLines(data []byte, decimal bool) ([]interface{}, error) {
+	var params []interface{}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(nil, len(data)+1)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) == 0 {
+			continue
+		}
+		v, err := decode(s.Bytes(), decimal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameters in line %d: %w", line, err)
+		}
+		params = append(params, v)
+	}
+	return params, s.Err()
+}
+
+// write prints the result r or the error err of an evaluation.
+func write(w io.Writer, output string, r interface{}, err error) error {
+	if output == "text" {
+		if err != nil {
+			_, err = fmt.Fprintln(w, "error:", err)
+			return err
+		}
+		_, err = fmt.Fprintln(w, r)
+		return err
+	}
+	if err != nil {
+		r = map[string]string{"error": err.Error()}
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
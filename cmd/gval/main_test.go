@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gval")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	params := filepath.Join(dir, "params.json")
+	if err := ioutil.WriteFile(params, []byte(`{"a": {"b": 7}, "price": 0.1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		{name: "constant", args: []string{"1 + 2"}, want: "3\n"},
+		{name: "params", args: []string{"a.b > 5", "--params", params}, want: "true\n"},
+		{name: "flags first", args: []string{"--output", "json", "--params", params, "a"}, want: "{\"b\":7}\n"},
+		{name: "stdin", args: []string{"a.b * 2", "--params", "-"}, stdin: `{"a": {"b": 3}}`, want: "6\n"},
+		{name: "decimal", args: []string{"price + 0.2", "--params", params, "--decimal"}, want: "0.3\n"},
+		{name: "languages", args: []string{"floor(a.b / 2)", "--params", params, "--lang", "full, math"}, want: "3\n"},
+		{
+			name:  "batch",
+			args:  []string{"a * 2", "--batch", "-", "--output", "json"},
+			stdin: "{\"a\": 1}\n\n{\"a\": 3}\n",
+			want:  "2\n6\n",
+		},
+		{
+			name:    "batch error",
+			args:    []string{"a * 2", "--batch", "-"},
+			stdin:   "{\"a\": 1}\n{\"a\": \"x\"}\n",
+			want:    "2\nerror: evaluating a * 2 at 1:1: invalid operation (string) * (float64)\n",
+			wantErr: "1 of 2 evaluations failed",
+		},
+		{name: "invalid batch", args: []string{"a", "--batch", "-"}, stdin: "{\n", wantErr: "invalid parameters in line 1"},
+		{name: "extension", args: []string{"floor(a.b / 2)", "--params", params, "--lang", "math"}, want: "3\n"},
+		{name: "extensions", args: []string{"1 + 2 == 3 && round(2.4) == 2", "--lang", "strict,math"}, want: "true\n"},
+		{name: "strict extension", args: []string{`"1" + 2`, "--lang", "strict"}, wantErr: "type mismatch"},
+		{name: "extension of base", args: []string{"abs(-1)", "--lang", "arithmetic,math"}, want: "1\n"},
+		{name: "unknown language", args: []string{"a", "--lang", "cobol"}, wantErr: "unknown language cobol"},
+		{name: "unknown output", args: []string{"a", "--output", "xml"}, wantErr: "unknown output xml"},
+		{name: "parse error", args: []string{"a >"}, wantErr: "unexpected EOF"},
+		{name: "missing expression", args: []string{"--decimal"}, wantErr: "expected one expression but got 0 arguments"},
+		{name: "repl", args: []string{"--repl", "--params", params}, stdin: "let c = a.b + 1\nc * 2\n", want: "> c = 8\n> 16\n> \n"},
+		{name: "repl without object", args: []string{"--repl", "--params", "-"}, stdin: "[1]", wantErr: "expected an object of variables but got []interface {}"},
+		{name: "two expressions", args: []string{"a", "b"}, wantErr: "expected one expression but got 2 arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("run() error = %v, want %s", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("run() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package gval
+
+import (
+	"context"
+)
+
+// ConstEval returns an Evaluable of value, which is constant like the literals of parsed expressions.
+func ConstEval(value interface{}) Evaluable {
+	return constant(value)
+}
+
+// VarEval returns an Evaluable selecting path from the parameter like the variable path[0].path[1] of Full.
+func VarEval(path ...string) Evaluable {
+	keys := make(Evaluables, len(path))
+	for i, key := range path {
+		keys[i] = constant(key)
+	}
+	return variable(keys)
+}
+
+// And returns an Evaluable that is true if all evals are true like e1 && e2.
+// It stops at the first operand that is false, so later operands are not evaluated.
+// Operands are converted to bool like the operands of &&, And() is true.
+func And(evals ...Evaluable) Evaluable {
+	return junction(evals, false)
+}
+
+// Or returns an Evaluable that is true if any of evals is true like e1 || e2.
+// It stops at the first operand that is true, so later operands are not evaluated.
+// Operands are converted to bool like the operands of ||, Or() is false.
+func Or(evals ...Evaluable) Evaluable {
+	return junction(evals, true)
+}
+
+// junction returns the Evaluable of And and Or, which stops with stop if an operand is stop.
+func junction(evals []Evaluable, stop bool) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		for _, eval := range evals {
+			b, err := eval.EvalBool(c, v)
+			if err != nil {
+				return nil, err
+			}
+			if b == stop {
+				return stop, nil
+			}
+		}
+		return !stop, nil
+	}
+}
+
+// Not returns an Evaluable that is true if eval is false like !e.
+func Not(eval Evaluable) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		b, err := eval.EvalBool(c, v)
+		if err != nil {
+			return nil, err
+		}
+		return !b, nil
+	}
+}
+
+// Call returns an Evaluable calling fn with the results of args like a function given by Function.
+// The arguments are converted to the parameter types of fn and a context.Context parameter gets the context of the evaluation.
+func Call(fn interface{}, args ...Evaluable) Evaluable {
+	f := toFunc(fn)
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		if c == nil {
+			c = context.Background()
+		}
+		a := make([]interface{}, len(args))
+		for i, arg := range args {
+			x, err := arg(c, v)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = x
+		}
+		return f(c, a...)
+	}
+}
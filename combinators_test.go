@@ -0,0 +1,67 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCombinators(t *testing.T) {
+	parameter := map[string]interface{}{
+		"user":  map[string]interface{}{"age": 30., "name": "Ada", "admin": "false"},
+		"limit": 18.,
+	}
+	fail := Call(func() (bool, error) { return false, fmt.Errorf("evaluated") })
+	adult := Call(func(age, limit float64) bool { return age >= limit }, VarEval("user", "age"), VarEval("limit"))
+
+	for name, test := range map[string]struct {
+		eval Evaluable
+		want interface{}
+	}{
+		"const":              {ConstEval(5.), 5.},
+		"var":                {VarEval("user", "name"), "Ada"},
+		"missing var":        {VarEval("user", "missing"), nil},
+		"call":               {Call(strings.ToUpper, VarEval("user", "name")), "ADA"},
+		"call converts":      {Call(func(n int) int { return n * 2 }, VarEval("limit")), 36},
+		"call context":       {Call(func(c context.Context) bool { return c != nil }), true},
+		"and":                {And(adult, Not(VarEval("user", "admin"))), true},
+		"and short-circuits": {And(ConstEval(false), fail), false},
+		"or":                 {Or(ConstEval(false), VarEval("user", "admin"), adult), true},
+		"or short-circuits":  {Or(adult, fail), true},
+		"empty and":          {And(), true},
+		"empty or":           {Or(), false},
+	} {
+		got, err := test.eval(nil, parameter)
+		if err != nil {
+			t.Errorf("%s error = %v", name, err)
+		} else if got != test.want {
+			t.Errorf("%s = %v (%T), want %v", name, got, got, test.want)
+		}
+	}
+
+	if !ConstEval(1).IsConst() || VarEval("a").IsConst() {
+		t.Error("IsConst() of ConstEval and VarEval")
+	}
+	for name, eval := range map[string]Evaluable{
+		"and":    And(ConstEval(true), fail),
+		"not":    Not(ConstEval("x")),
+		"arity":  Call(strings.ToUpper),
+		"failed": Call(func() (interface{}, error) { return nil, fmt.Errorf("evaluated") }),
+	} {
+		if _, err := eval(context.Background(), parameter); err == nil {
+			t.Errorf("%s expected error", name)
+		}
+	}
+
+	parsed, err := Full().NewEvaluable(`user.age >= limit && !user.admin`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []interface{}{parameter, map[string]interface{}{"user": map[string]interface{}{"age": 10.}, "limit": 18.}} {
+		want, _ := parsed(nil, p)
+		if got, err := And(adult, Not(VarEval("user", "admin")))(nil, p); err != nil || got != want {
+			t.Errorf("And() = %v, %v, want %v like the parsed expression", got, err, want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"text/scanner"
+	"time"
+)
+
+// CompatV1 is the Full language of upstream github.com/PaesslerAG/gval v1 for rule sets written against upstream.
+//
+// It lacks the additions of this package to Full: the nil constant, the text operators sw, co, ew, mw,
+// eqi, swi, coi, ewi, like and ilike, the operators between, cfa, cfm, ?: and |>,
+// chained comparisons like 1 < x < 3 and the functions besides date.
+// As upstream, in requires an []interface{} and date parses in time.Local and fails while evaluating.
+// nil is a variable, which is nil unless it is given by the parameter, and
+// a ?? b returns b if a is nil or the zero value of its type like 0, "" or false.
+//
+// Variables are written like upstream as fields a.b and keys a[k] of parameters followed by an optional call.
+// Negative indices, slices a[1:2], wildcards a[*], recursive descents a..b, spreads [...a]
+// and selections from other operands like (a).b or [1, 2][0] fail.
+//
+// Unlike upstream, the arguments of calls are converted like the arguments of Function, e.g. 4 for an int parameter,
+// and values are selected from parameters like in Full, so json.RawMessage is decoded and struct fields
+// are also selected by their json tag.
+func CompatV1() Language {
+	return compatV1
+}
+
+// compatV1Additions are the operators, constants and functions of Full that upstream lacks.
+var compatV1Additions = []string{
+	"nil",
+	"sw", "co", "ew", "mw", "eqi", "swi", "coi", "ewi", "like", "ilike",
+	"between", "cfa", "cfm", "?:", "|>",
+	"exists", "get", "has", "jsonDecode", "jsonEncode", "keys", "len", "type", "values",
+	"in", "date",
+}
+
+var compatV1 = func() Language {
+	l := NewLanguage(
+		full.Without(compatV1Additions...),
+		ChainedComparisons(),
+		PrefixMetaPrefix(scanner.Ident, parseCompatV1Ident),
+		PrefixExtension('[', parseCompatV1Array),
+		VariableSelector(compatV1Variable),
+
+		InfixOperator("in", func(a, b interface{}) (interface{}, error) {
+			col, ok := b.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected type []interface{} for in operator but got %T", b)
+			}
+			for _, value := range col {
+				if reflect.DeepEqual(a, value) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}),
+		Precedence("in", 40),
+
+		Function("date", func(arguments ...interface{}) (interface{}, error) {
+			if len(arguments) != 1 {
+				return nil, fmt.Errorf("date() expects exactly one string argument")
+			}
+			s, ok := arguments[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("date() expects exactly one string argument")
+			}
+			return parseDate(s, time.Local)
+		}),
+	)
+	// fields and keys are only selected from variables
+	l.setOption(option{name: "selectors", value: false})
+	return l
+}()
+
+// parseCompatV1Ident parses a variable or a call like upstream: fields .a and keys [k] followed by an optional call.
+func parseCompatV1Ident(c context.Context, p *Parser) (call string, alternative func() (Evaluable, error), err error) {
+	token := p.TokenText()
+	return token, func() (Evaluable, error) {
+		fullname := token
+		keys := []Evaluable{p.Const(token)}
+		for {
+			switch p.Scan() {
+			case '.':
+				if p.Scan() != scanner.Ident {
+					return nil, p.Expected("field", scanner.Ident)
+				}
+				keys = append(keys, p.Const(p.TokenText()))
+			case '(':
+				args, err := p.parseArguments(c)
+				if err != nil {
+					return nil, err
+				}
+				return p.callEvaluable(fullname, p.Var(keys...), args...), nil
+			case '[':
+				key, err := p.ParseExpression(c)
+				if err != nil {
+					return nil, err
+				}
+				if p.Scan() != ']' {
+					return nil, p.Expected("array key", ']')
+				}
+				keys = append(keys, key)
+			default:
+				p.Camouflage("variable", '.', '(', '[')
+				p.nodes.variable(keys)
+				return p.Var(keys...), nil
+			}
+		}
+	}, nil
+}
+
+// parseCompatV1Array parses a json array like upstream, without spreads.
+func parseCompatV1Array(c context.Context, p *Parser) (Evaluable, error) {
+	evals := Evaluables{}
+	for {
+		switch p.Scan() {
+		default:
+			p.Camouflage("array", ',', ']')
+			eval, err := p.ParseExpression(c)
+			if err != nil {
+				return nil, err
+			}
+			evals = append(evals, eval)
+		case ',':
+		case ']':
+			return func(c context.Context, v interface{}) (interface{}, error) {
+				vs := make([]interface{}, len(evals))
+				for i, e := range evals {
+					if err := CheckContext(c, i); err != nil {
+						return nil, err
+					}
+					x, err := e(c, v)
+					if err != nil {
+						return nil, err
+					}
+					vs[i] = x
+				}
+				return vs, nil
+			}, nil
+		}
+	}
+}
+
+// compatV1Variable selects the path like Full, but negative indices of arrays are unknown like upstream.
+func compatV1Variable(path Evaluables) Evaluable {
+	eval := variable(path)
+	if keys, err := path.EvalStrings(nil, nil); err == nil && path.areConst() && !hasNegativeIndex(keys) {
+		return eval
+	}
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		keys, err := path.EvalStrings(c, v)
+		if err != nil {
+			return nil, err
+		}
+		for i := range keys {
+			if !hasNegativeIndex(keys[i : i+1]) {
+				continue
+			}
+			if a, err := selectKeys(c, v, keys[:i], false); err == nil {
+				if kind := reflect.Indirect(reflect.ValueOf(a)).Kind(); kind == reflect.Slice || kind == reflect.Array {
+					return nil, unknownParameterError{keys[:i+1]}
+				}
+			}
+		}
+		return eval(c, v)
+	}
+}
+
+// hasNegativeIndex returns if one of the keys is a negative integer.
+func hasNegativeIndex(keys []string) bool {
+	for _, k := range keys {
+		if i, err := strconv.Atoi(k); err == nil && i < 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package gval
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompatV1(t *testing.T) {
+	l := CompatV1()
+	d := l.Describe()
+	var operators, functions, constants []string
+	for _, o := range d.Operators {
+		operators = append(operators, o.Name)
+	}
+	for _, f := range d.Functions {
+		functions = append(functions, f.Name)
+	}
+	for _, c := range d.Constants {
+		constants = append(constants, c.Name)
+	}
+	wantOperators := []string{
+		"!=", "!~", "%", "&", "&&", "*", "**", "+", "-", "/", "<", "<<", "<=", "==", "=~", ">", ">=", ">>",
+		"?", "??", "^", "in", "|", "||",
+	}
+	if !reflect.DeepEqual(operators, wantOperators) {
+		t.Errorf("operators = %v, want %v", operators, wantOperators)
+	}
+	if !reflect.DeepEqual(functions, []string{"date"}) {
+		t.Errorf("functions = %v, want [date]", functions)
+	}
+	if !reflect.DeepEqual(constants, []string{"false", "true"}) {
+		t.Errorf("constants = %v, want [false true]", constants)
+	}
+	if !reflect.DeepEqual(d.Prefixes, []string{"!", "(", "-", "[", "{", "~"}) {
+		t.Errorf("prefixes = %v", d.Prefixes)
+	}
+
+	parameter := map[string]interface{}{
+		"a":     []interface{}{1., "x"},
+		"name":  "Zoë",
+		"empty": "",
+		"zero":  0.,
+		"m":     map[string]interface{}{"-1": "minus one", "b": map[string]interface{}{"c": 1.}},
+		"ints":  []int{1, 2, 3},
+	}
+	ch := make(chan interface{})
+	close(ch)
+	for _, test := range []struct {
+		expression string
+		parameter  interface{}
+		want       interface{}
+		wantErr    string
+	}{
+		{expression: `(1 + 2) * 3 > 8 && name =~ "^Z"`, parameter: parameter, want: true},
+		{expression: `1 in a && "x" in a && !(2 in a)`, parameter: parameter, want: true},
+		{expression: `1 in ch`, parameter: map[string]interface{}{"ch": ch}, wantErr: "expected type []interface{} for in operator but got chan interface {}"},
+		{expression: `empty ?? zero ?? "default"`, parameter: parameter, want: "default"},
+		{expression: `name ?? "default"`, parameter: parameter, want: "Zoë"},
+		{expression: `zero ? 1 : 2`, parameter: parameter, want: 2.},
+		{expression: `missing == nil`, parameter: parameter, want: true},
+		{expression: `nil`, parameter: struct{}{}, wantErr: "unknown parameter nil"},
+		{expression: `1 < 2 < 3`, want: false}, // "true" < "3" as chained comparisons are not supported
+		{expression: `name sw "Z"`, parameter: parameter, wantErr: "unexpected Ident"},
+		{expression: `a cfa ["x", "equal"]`, parameter: parameter, wantErr: "unexpected Ident"},
+		{expression: `name ?: "x"`, parameter: parameter, wantErr: "unexpected"},
+		{expression: `date("2024-01-02") == date("2024-01-02 00:00")`, want: true},
+		{expression: `date(1)`, wantErr: "can not evaluate date(1)"},
+		{expression: `len(a)`, parameter: parameter, wantErr: "could not call 'len'"},
+		{expression: `a[1] + m["-1"] + m.b["c"]`, parameter: parameter, want: "xminus one1"},
+		{expression: `a[-1]`, parameter: parameter, wantErr: "unknown parameter a.-1"},
+		{expression: `ints[-1]`, parameter: parameter, wantErr: "unknown parameter ints.-1"},
+		{expression: `a[0:1]`, parameter: parameter, wantErr: "unexpected"},
+		{expression: `a[*]`, parameter: parameter, wantErr: "unexpected"},
+		{expression: `m..c`, parameter: parameter, wantErr: "unexpected"},
+		{expression: `[...a]`, parameter: parameter, wantErr: "unexpected"},
+		{expression: `(m).b`, parameter: parameter, wantErr: "unexpected"},
+		{expression: `[1, 2][0]`, parameter: parameter, wantErr: "unexpected"},
+	} {
+		got, err := l.Evaluate(test.expression, test.parameter)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Evaluate(%s) = %v, %v, want error %s", test.expression, got, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Evaluate(%s) = %v, %v, want %v", test.expression, got, err, test.want)
+		}
+	}
+
+	got, err := l.Evaluate(`date("2024-01-02")`, nil)
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local); err != nil || got != want {
+		t.Errorf("Evaluate(date) = %v, %v, want %v", got, err, want)
+	}
+}
@@ -0,0 +1,25 @@
+package gval
+
+import (
+	"context"
+)
+
+// contextCheckInterval is the number of iterations between two checks of CheckContext.
+const contextCheckInterval = 256
+
+// CheckContext returns the error of c if c is done, but only checks c every 256th iteration i.
+// Operators and functions looping over large inputs call it to stop a cancelled evaluation
+// without paying for a check in every iteration:
+//
+//	for i, e := range elements {
+//		if err := gval.CheckContext(c, i); err != nil {
+//			return nil, err
+//		}
+//		...
+//	}
+func CheckContext(c context.Context, i int) error {
+	if c == nil || i%contextCheckInterval != 0 {
+		return nil
+	}
+	return c.Err()
+}
@@ -0,0 +1,87 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckContext(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := CheckContext(c, 1); err != nil {
+		t.Errorf("CheckContext(1) = %v, want no check", err)
+	}
+	if err := CheckContext(c, 512); !errors.Is(err, context.Canceled) {
+		t.Errorf("CheckContext(512) = %v, want context.Canceled", err)
+	}
+	if err := CheckContext(nil, 0); err != nil {
+		t.Errorf("CheckContext(nil) = %v", err)
+	}
+}
+
+func TestCancelledOperators(t *testing.T) {
+	large := make([]interface{}, 1000)
+	for i := range large {
+		large[i] = map[string]interface{}{"name": "x", "children": []interface{}{float64(i)}}
+	}
+	parameter := map[string]interface{}{"large": large, "names": []interface{}{"x"}}
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, expression := range []string{
+		`"y" in large`,
+		`large cfa ["y", "eq"]`,
+		`large cfm ["name", "eq", "y"]`,
+		`large union large`,
+		`distinct(large)`,
+		`containsAny(names, large)`,
+		`[...large, ...large]`,
+		`large[*].children`,
+		`large..name`,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			eval, err := Full(Sets()).NewEvaluable(expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := eval(c, parameter); !errors.Is(err, context.Canceled) {
+				t.Errorf("evaluation error = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
+
+func TestInfixContextOperator(t *testing.T) {
+	type key struct{}
+	l := Full(InfixContextOperator("with", func(c context.Context, a, b interface{}) (interface{}, error) {
+		return []interface{}{a, b, c.Value(key{})}, nil
+	}))
+	got, err := l.EvaluateWithContext(context.WithValue(context.Background(), key{}, "value"), `x with 1`, map[string]interface{}{"x": 0.})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{0., 1., "value"}; !equalSlices(got, want) {
+		t.Errorf("Evaluate() = %v, want %v", got, want)
+	}
+	got, err = l.Evaluate(`2 with 1`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{2., 1., nil}; !equalSlices(got, want) {
+		t.Errorf("Evaluate() = %v, want %v", got, want)
+	}
+}
+
+func equalSlices(got interface{}, want []interface{}) bool {
+	s, ok := got.([]interface{})
+	if !ok || len(s) != len(want) {
+		return false
+	}
+	for i := range s {
+		if s[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
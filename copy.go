@@ -0,0 +1,108 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+)
+
+// CopyResults returns a Language whose Evaluables return deep copies of the arrays and maps they result in,
+// e.g. of a selected a.b or of a constructed {"b": a.b}, instead of aliases into the parameter or into constants.
+// Callers can modify the results while the parameter is used concurrently.
+// Structs and pointers are not copied.
+func CopyResults() Language {
+	l := newLanguage()
+	l.setOption(option{
+		name: "copyResults",
+		wrap: func(eval Evaluable) Evaluable {
+			if eval.IsConst() {
+				if v, _ := eval(nil, nil); v == nil || !isContainer(reflect.ValueOf(v)) {
+					return eval
+				}
+			}
+			return func(c context.Context, parameter interface{}) (interface{}, error) {
+				v, err := eval(c, parameter)
+				if err != nil {
+					return nil, err
+				}
+				return deepCopy(v), nil
+			}
+		},
+	})
+	return l
+}
+
+func isContainer(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// deepCopy returns a copy of v with all slices, maps and arrays in it copied.
+func deepCopy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = deepCopy(e)
+		}
+		return c
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		c := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			c[k] = deepCopy(e)
+		}
+		return c
+	case nil, bool, string, float64:
+		return v
+	}
+	r := reflect.ValueOf(v)
+	if !isContainer(r) {
+		return v
+	}
+	return deepCopyValue(r).Interface()
+}
+
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopyValue(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, deepCopyValue(v.MapIndex(k)))
+		}
+		return c
+	}
+	return v
+}
@@ -0,0 +1,69 @@
+package gval
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCopyResults(t *testing.T) {
+	newParameter := func() map[string]interface{} {
+		return map[string]interface{}{
+			"order": map[string]interface{}{
+				"items": []interface{}{map[string]interface{}{"sku": "a"}},
+				"tags":  []string{"x"},
+				"ids":   map[int][]int{1: {2}},
+			},
+		}
+	}
+	l := Full(CopyResults())
+	for _, expression := range []string{
+		`order`,
+		`order.items`,
+		`{"items": order.items, "order": order}`,
+		`[order.items[0], order.tags, order.ids]`,
+	} {
+		parameter := newParameter()
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Fatalf("Evaluate(%s) error = %v", expression, err)
+		}
+		mutate(reflect.ValueOf(got))
+		if want := newParameter(); !reflect.DeepEqual(parameter, want) {
+			t.Errorf("Evaluate(%s) result aliases the parameter, parameter is %v after modifying the result", expression, parameter)
+		}
+	}
+
+	constant, err := l.NewEvaluable(`[1, {"a": [2]}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := constant(nil, nil)
+	first.([]interface{})[1].(map[string]interface{})["a"] = nil
+	if second, _ := constant(nil, nil); !reflect.DeepEqual(second, []interface{}{1., map[string]interface{}{"a": []interface{}{2.}}}) {
+		t.Errorf("constant result = %v after modifying the previous result", second)
+	}
+
+	if v, err := l.Evaluate(`1 + 1`, nil); err != nil || v != 2. {
+		t.Errorf("Evaluate(1 + 1) = %v, %v", v, err)
+	}
+}
+
+// mutate sets all elements of the slices and maps in v to their zero value.
+func mutate(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			mutate(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			mutate(v.Index(i))
+			v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			mutate(v.MapIndex(k))
+			v.SetMapIndex(k, reflect.Zero(v.Type().Elem()))
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Coverage counts the branches taken by evaluations of expressions,
+// e.g. to find dead branches of rules by evaluating them with a corpus of sample parameters.
+//
+// Branches are the then and else branches of the ternary operator ?:
+// and the short circuit and the evaluation of the right operand of short circuit operators like && and ||.
+//
+// A Coverage can be used concurrently.
+type Coverage struct {
+	language Language
+	mutex    sync.RWMutex
+	branches []*Branch
+	// conditions holds the branch points by their condition,
+	// constants holds the branch points with a constant condition by their node
+	conditions map[*Node]*branchPoint
+	constants  map[*Node]*branchPoint
+}
+
+// Branch is a branch of an expression.
+type Branch struct {
+	// Expression is the expression containing the branch.
+	Expression string
+	// Node is the node branching, a ternary postfix node or an infix node.
+	Node *Node
+	// Name is "then" or "else" for ternary operators and "short circuit" or "right operand" for infix operators.
+	Name string
+	// Count is the number of evaluations taking the branch.
+	Count int
+}
+
+func (b Branch) String() string {
+	return fmt.Sprintf("%s: %s taken %d times", b.Node.Text(), b.Name, b.Count)
+}
+
+// branchPoint is a node with two branches chosen by the value of its condition.
+type branchPoint struct {
+	branches [2]*Branch
+	// choose returns the index of the branch taken for the value of the condition
+	choose func(condition interface{}) int
+}
+
+// NewCoverage returns a Coverage of the expressions of given Language.
+func NewCoverage(lang Language) *Coverage {
+	return &Coverage{
+		language:   lang,
+		conditions: map[*Node]*branchPoint{},
+		constants:  map[*Node]*branchPoint{},
+	}
+}
+
+// NewEvaluable returns an Evaluable for given expression counting the branches it takes.
+func (cov *Coverage) NewEvaluable(expression string) (Evaluable, error) {
+	return cov.NewEvaluableWithContext(context.Background(), expression)
+}
+
+// NewEvaluableWithContext returns an Evaluable for given expression counting the branches it takes using context.
+func (cov *Coverage) NewEvaluableWithContext(c context.Context, expression string) (Evaluable, error) {
+	node, eval, err := cov.language.parseObserved(c, expression)
+	if err != nil {
+		return nil, err
+	}
+	cov.mutex.Lock()
+	cov.add(expression, node)
+	cov.mutex.Unlock()
+	return func(c context.Context, parameter interface{}) (interface{}, error) {
+		if c == nil {
+			c = context.Background()
+		}
+		return eval(ContextWithObserver(c, cov), parameter)
+	}, nil
+}
+
+// Branches returns all branches of the expressions in the order of their appearance.
+func (cov *Coverage) Branches() []Branch {
+	cov.mutex.RLock()
+	defer cov.mutex.RUnlock()
+	branches := make([]Branch, len(cov.branches))
+	for i, b := range cov.branches {
+		branches[i] = *b
+	}
+	return branches
+}
+
+// Uncovered returns the branches that were never taken.
+func (cov *Coverage) Uncovered() []Branch {
+	uncovered := []Branch{}
+	for _, b := range cov.Branches() {
+		if b.Count == 0 {
+			uncovered = append(uncovered, b)
+		}
+	}
+	return uncovered
+}
+
+// add adds the branches of n and its children.
+func (cov *Coverage) add(expression string, n *Node) {
+	if n == nil {
+		return
+	}
+	var names [2]string
+	var choose func(interface{}) int
+	switch n.Kind {
+	case PostfixNode:
+		if n.Operator == "?" {
+			names = [2]string{"then", "else"}
+			choose = func(condition interface{}) int {
+				if isTruthy(condition) {
+					return 0
+				}
+				return 1
+			}
+		}
+	case InfixNode:
+		if op, ok := cov.language.operators[n.Operator].(*infix); ok && op.shortCircuit != nil {
+			names = [2]string{"short circuit", "right operand"}
+			choose = func(condition interface{}) int {
+				if _, ok := op.shortCircuit(condition); ok {
+					return 0
+				}
+				return 1
+			}
+		}
+	}
+	if choose != nil {
+		bp := &branchPoint{choose: choose}
+		for i, name := range names {
+			bp.branches[i] = &Branch{Expression: expression, Node: n, Name: name}
+			cov.branches = append(cov.branches, bp.branches[i])
+		}
+		if condition := n.Children[0]; condition.constant {
+			cov.constants[n] = bp
+		} else {
+			cov.conditions[condition] = bp
+		}
+	}
+	for _, child := range n.Children {
+		cov.add(expression, child)
+	}
+}
+
+// OnEnterNode counts the branch of nodes with a constant condition.
+func (cov *Coverage) OnEnterNode(c context.Context, n *Node) {
+	cov.mutex.RLock()
+	bp, ok := cov.constants[n]
+	cov.mutex.RUnlock()
+	if ok {
+		cov.take(bp, n.Children[0].Value)
+	}
+}
+
+// OnExitNode counts the branch chosen by the value of a condition.
+func (cov *Coverage) OnExitNode(c context.Context, n *Node, value interface{}, err error) {
+	cov.mutex.RLock()
+	bp, ok := cov.conditions[n]
+	cov.mutex.RUnlock()
+	if ok && err == nil {
+		cov.take(bp, value)
+	}
+}
+
+// OnFunctionCall does nothing.
+func (cov *Coverage) OnFunctionCall(context.Context, string, []interface{}, interface{}, error) {}
+
+func (cov *Coverage) take(bp *branchPoint, condition interface{}) {
+	i := bp.choose(condition)
+	cov.mutex.Lock()
+	bp.branches[i].Count++
+	cov.mutex.Unlock()
+}
@@ -0,0 +1,77 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	cov := NewCoverage(Full())
+	rules := []string{
+		`country == "DE" && age >= 18 ? "adult" : "other"`,
+		`vip || true`,
+		`(discount ?? 0) > 10`,
+	}
+	var evals []Evaluable
+	for _, rule := range rules {
+		eval, err := cov.NewEvaluable(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		evals = append(evals, eval)
+	}
+	samples := []map[string]interface{}{
+		{"country": "DE", "age": 20, "vip": false, "discount": 20},
+		{"country": "AT", "age": 20, "vip": false},
+	}
+	for _, sample := range samples {
+		for _, eval := range evals {
+			if _, err := eval(context.Background(), sample); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	type branch struct {
+		node  string
+		name  string
+		count int
+	}
+	want := []branch{
+		{`country == "DE" && age >= 18 ? "adult" : "other"`, "then", 1},
+		{`country == "DE" && age >= 18 ? "adult" : "other"`, "else", 1},
+		{`country == "DE" && age >= 18`, "short circuit", 1},
+		{`country == "DE" && age >= 18`, "right operand", 1},
+		{`vip || true`, "short circuit", 0},
+		{`vip || true`, "right operand", 2},
+		{`(discount ?? 0)`, "short circuit", 1},
+		{`(discount ?? 0)`, "right operand", 1},
+	}
+	var got []branch
+	for _, b := range cov.Branches() {
+		got = append(got, branch{b.Node.Text(), b.Name, b.Count})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Branches() = %v, want %v", got, want)
+	}
+
+	uncovered := cov.Uncovered()
+	if len(uncovered) != 1 || uncovered[0].Expression != `vip || true` || uncovered[0].String() != "vip || true: short circuit taken 0 times" {
+		t.Errorf("Uncovered() = %v", uncovered)
+	}
+}
+
+func TestCoverageConstantCondition(t *testing.T) {
+	cov := NewCoverage(Full())
+	eval, err := cov.NewEvaluable(`true ? a : b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := eval(context.Background(), map[string]interface{}{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if uncovered := cov.Uncovered(); len(uncovered) != 1 || uncovered[0].Name != "else" {
+		t.Errorf("Uncovered() = %v, want else", uncovered)
+	}
+}
@@ -0,0 +1,160 @@
+package gval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// HitPolicy defines the result of a DecisionTable whose rows match.
+type HitPolicy string
+
+const (
+	// HitFirst decides the outcome of the first matching row or nil if no row matches.
+	HitFirst HitPolicy = "first"
+	// HitAll decides the outcomes of all matching rows by their name.
+	HitAll HitPolicy = "all"
+	// HitCollect decides the outcomes of all matching rows as array in the order of the rows.
+	HitCollect HitPolicy = "collect"
+)
+
+// DecisionRow is a row of a DecisionTable.
+// It matches if all its conditions are true.
+type DecisionRow struct {
+	// Name identifies the row, it defaults to "row <index>".
+	Name       string   `json:"name,omitempty"`
+	Conditions []string `json:"conditions"`
+	Outcome    string   `json:"outcome"`
+}
+
+// DecisionTable maps rows of condition expressions to outcome expressions.
+type DecisionTable struct {
+	// HitPolicy defaults to HitFirst.
+	HitPolicy HitPolicy     `json:"hitPolicy,omitempty"`
+	Rows      []DecisionRow `json:"rows"`
+}
+
+// Decision is a parsed DecisionTable. It can be evaluated concurrently.
+type Decision struct {
+	policy     HitPolicy
+	conditions *RuleSet
+	outcomes   map[string]Evaluable
+}
+
+// NewDecision parses the conditions and outcomes of the table in the Language.
+// The conditions of a row are evaluated as rule of a RuleSet.
+func (l Language) NewDecision(table DecisionTable) (*Decision, error) {
+	return l.NewDecisionWithContext(context.Background(), table)
+}
+
+// NewDecisionWithContext parses the table in the Language using context.
+func (l Language) NewDecisionWithContext(c context.Context, table DecisionTable) (*Decision, error) {
+	d := &Decision{
+		policy:   table.HitPolicy,
+		outcomes: make(map[string]Evaluable, len(table.Rows)),
+	}
+	switch d.policy {
+	case "":
+		d.policy = HitFirst
+	case HitFirst, HitAll, HitCollect:
+	default:
+		return nil, fmt.Errorf("unknown hit policy %s", table.HitPolicy)
+	}
+
+	rules := make([]Rule, len(table.Rows))
+	for i, row := range table.Rows {
+		name := row.Name
+		if name == "" {
+			name = fmt.Sprintf("row %d", i)
+		}
+		rules[i] = Rule{Name: name, Expression: conjunction(row.Conditions)}
+		if _, ok := d.outcomes[name]; ok {
+			return nil, fmt.Errorf("duplicate rule %s", name)
+		}
+		outcome, err := l.NewEvaluableWithContext(c, row.Outcome)
+		if err != nil {
+			return nil, fmt.Errorf("outcome of rule %s: %w", name, err)
+		}
+		d.outcomes[name] = outcome
+	}
+	var err error
+	d.conditions, err = l.NewRuleSetWithContext(c, rules...)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// NewDecisionFromJSON parses a DecisionTable in its JSON encoding like
+//
+//	{"hitPolicy": "first", "rows": [{"conditions": ["age >= 18"], "outcome": "\"adult\""}]}
+func (l Language) NewDecisionFromJSON(table []byte) (*Decision, error) {
+	var t DecisionTable
+	if err := json.Unmarshal(table, &t); err != nil {
+		return nil, err
+	}
+	return l.NewDecision(t)
+}
+
+// conjunction returns the expression that is true if all conditions are true.
+func conjunction(conditions []string) string {
+	if len(conditions) == 0 {
+		return "true"
+	}
+	parenthesized := make([]string, len(conditions))
+	for i, condition := range conditions {
+		parenthesized[i] = "(" + condition + ")"
+	}
+	return strings.Join(parenthesized, " && ")
+}
+
+// Decide evaluates the rows against the parameter and returns the outcome by the hit policy of the table:
+// the outcome of HitFirst, a map[string]interface{} of HitAll or an []interface{} of HitCollect.
+// It stops with the error of a failing condition or outcome.
+func (d *Decision) Decide(c context.Context, parameter interface{}) (interface{}, error) {
+	if c == nil {
+		c = context.Background()
+	}
+	if d.policy == HitFirst {
+		name, err := d.conditions.FirstMatch(c, parameter)
+		if err != nil || name == "" {
+			return nil, err
+		}
+		return d.outcome(c, name, parameter)
+	}
+
+	all := map[string]interface{}{}
+	collected := []interface{}{}
+	e := d.conditions.newEvaluation(parameter)
+	for i, rule := range d.conditions.rules {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		r := e.result(c, i)
+		if r.Err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, r.Err)
+		}
+		if r.Value != true {
+			continue
+		}
+		v, err := d.outcome(c, rule.Name, parameter)
+		if err != nil {
+			return nil, err
+		}
+		all[rule.Name] = v
+		collected = append(collected, v)
+	}
+	if d.policy == HitAll {
+		return all, nil
+	}
+	return collected, nil
+}
+
+func (d *Decision) outcome(c context.Context, name string, parameter interface{}) (interface{}, error) {
+	v, err := d.outcomes[name](c, parameter)
+	if err != nil {
+		return nil, fmt.Errorf("outcome of rule %s: %w", name, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,71 @@
+package gval
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecision(t *testing.T) {
+	table := DecisionTable{Rows: []DecisionRow{
+		{Name: "minor", Conditions: []string{"age < 18"}, Outcome: `"minor"`},
+		{Name: "senior", Conditions: []string{"age >= 65", `country == "de"`}, Outcome: `"senior " + country`},
+		{Name: "adult", Conditions: []string{"age >= 18"}, Outcome: `"adult"`},
+		{Conditions: nil, Outcome: "discount * 2"},
+	}}
+	for _, test := range []struct {
+		policy    HitPolicy
+		parameter map[string]interface{}
+		want      interface{}
+	}{
+		{"", map[string]interface{}{"age": 70., "country": "de", "discount": 1.}, "senior de"},
+		{HitFirst, map[string]interface{}{"age": 10., "country": "de", "discount": 1.}, "minor"},
+		{HitAll, map[string]interface{}{"age": 70., "country": "de", "discount": 1.},
+			map[string]interface{}{"senior": "senior de", "adult": "adult", "row 3": 2.}},
+		{HitCollect, map[string]interface{}{"age": 70., "country": "fr", "discount": 2.}, []interface{}{"adult", 4.}},
+	} {
+		table.HitPolicy = test.policy
+		d, err := Full().NewDecision(table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := d.Decide(nil, test.parameter)
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Decide() = %v, %v, want %v", test.policy, got, err, test.want)
+		}
+	}
+
+	d, err := Full().NewDecisionFromJSON([]byte(`{"hitPolicy": "collect", "rows": [
+		{"name": "high", "conditions": ["score > 80"], "outcome": "\"A\""},
+		{"name": "low", "conditions": ["score <= 80"], "outcome": "grade.missing"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.Decide(nil, map[string]interface{}{"score": 90.}); err != nil || !reflect.DeepEqual(got, []interface{}{"A"}) {
+		t.Errorf("Decide() = %v, %v", got, err)
+	}
+	if _, err := d.Decide(nil, map[string]interface{}{"score": 10.}); err == nil || !strings.HasPrefix(err.Error(), "outcome of rule low:") {
+		t.Errorf("Decide() error = %v, want error of outcome low", err)
+	}
+	if _, err := d.Decide(nil, map[string]interface{}{}); err == nil {
+		t.Error("Decide() with failing condition expected error")
+	}
+
+	first, _ := Full().NewDecision(DecisionTable{Rows: []DecisionRow{{Conditions: []string{"false"}, Outcome: "1"}}})
+	if got, err := first.Decide(nil, nil); got != nil || err != nil {
+		t.Errorf("Decide() without match = %v, %v, want nil", got, err)
+	}
+
+	for _, bad := range []string{
+		`{"hitPolicy": "unique", "rows": []}`,
+		`{"rows": [{"conditions": ["1 +"], "outcome": "1"}]}`,
+		`{"rows": [{"conditions": [], "outcome": "1 +"}]}`,
+		`{"rows": [{"name": "a", "outcome": "1"}, {"name": "a", "outcome": "2"}]}`,
+		`{"rows": {}}`,
+	} {
+		if _, err := Full().NewDecisionFromJSON([]byte(bad)); err == nil {
+			t.Errorf("NewDecisionFromJSON(%s) expected error", bad)
+		}
+	}
+}
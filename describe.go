@@ -0,0 +1,187 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/scanner"
+)
+
+// Description is the surface of a Language: its operators, functions and constants.
+// It is serialized to JSON for clients validating expressions without Go, e.g. editors in the browser.
+// Description.Language constructs a Language with the same syntax from it.
+type Description struct {
+	Operators []OperatorDescription `json:"operators"`
+	// Prefixes are the symbols starting an operand like - and ! or ( and [
+	Prefixes  []string              `json:"prefixes"`
+	Functions []FunctionDescription `json:"functions"`
+	Constants []ConstantDescription `json:"constants"`
+}
+
+// OperatorDescription describes an operator following an operand.
+type OperatorDescription struct {
+	Name       string `json:"name"`
+	Precedence int    `json:"precedence"`
+	// Infix is true if the operator is followed by another operand, Postfix if not. Both may be true, e.g. for %.
+	Infix            bool `json:"infix"`
+	Postfix          bool `json:"postfix"`
+	RightAssociative bool `json:"rightAssociative,omitempty"`
+}
+
+// FunctionDescription describes a function by its number of arguments.
+type FunctionDescription struct {
+	Name    string `json:"name"`
+	MinArgs int    `json:"minArgs"`
+	// MaxArgs is -1 for functions with any number of arguments.
+	MaxArgs int `json:"maxArgs"`
+}
+
+// ConstantDescription describes a named constant like true.
+type ConstantDescription struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// Describe returns the Description of the Language ordered by name.
+// Prefixes that are neither symbols, functions nor constants like let are not described.
+func (l Language) Describe() Description {
+	d := Description{
+		Operators: []OperatorDescription{},
+		Prefixes:  []string{},
+		Functions: []FunctionDescription{},
+		Constants: []ConstantDescription{},
+	}
+	// the functions and constants of Languages with an Init extension like Interpolation
+	// are used in the expressions it embeds
+	expressions := l
+	expressions.init = nil
+	for name, op := range l.operators {
+		o := OperatorDescription{Name: name, Precedence: int(op.precedence())}
+		switch op := op.(type) {
+		case *infix:
+			o.Infix, o.Postfix, o.RightAssociative = true, op.postfix != nil, op.rightAssociative
+		case directInfix:
+			o.Infix = true
+		case postfix:
+			o.Postfix = true
+		default:
+			// a precedence without operator
+			continue
+		}
+		d.Operators = append(d.Operators, o)
+	}
+	for key := range l.prefixes {
+		switch key := key.(type) {
+		case rune:
+			if key >= 0 {
+				d.Prefixes = append(d.Prefixes, string(key))
+			}
+		case string:
+			if _, ok := l.functions[key]; ok {
+				min, max := expressions.callArity(key)
+				d.Functions = append(d.Functions, FunctionDescription{Name: key, MinArgs: min, MaxArgs: max})
+				continue
+			}
+			if n, err := expressions.Parse(key); err == nil && n != nil && n.Kind == ConstantNode {
+				d.Constants = append(d.Constants, ConstantDescription{Name: key, Value: n.Value})
+			}
+		}
+	}
+	sort.Slice(d.Operators, func(i, j int) bool { return d.Operators[i].Name < d.Operators[j].Name })
+	sort.Strings(d.Prefixes)
+	sort.Slice(d.Functions, func(i, j int) bool { return d.Functions[i].Name < d.Functions[j].Name })
+	sort.Slice(d.Constants, func(i, j int) bool { return d.Constants[i].Name < d.Constants[j].Name })
+	return d
+}
+
+// Language returns a shadow Language with the syntax of the described Language.
+// It parses the expressions the described Language parses, with the same precedences,
+// and fails for calls of the described functions with a wrong number of arguments.
+// Its constants, literals and variables evaluate to their values, but its operators and functions fail.
+//
+// ( [ and { start parentheses, json arrays and objects, ? and ?: are the TernaryOperator and |> the Pipeline.
+// Other prefixes are prefix operators and other postfix operators have no further operands.
+func (d Description) Language() Language {
+	languages := []Language{shadowSyntax}
+	for _, prefix := range d.Prefixes {
+		prefix := prefix
+		switch prefix {
+		case "(":
+			languages = append(languages, parentheses)
+		case "[":
+			languages = append(languages, PrefixExtension('[', parseJSONArray))
+		case "{":
+			languages = append(languages, PrefixExtension('{', parseJSONObject))
+		default:
+			languages = append(languages, PrefixOperator(prefix, func(context.Context, interface{}) (interface{}, error) {
+				return nil, shadowError(prefix)
+			}))
+		}
+	}
+	for _, o := range d.Operators {
+		name := o.Name
+		if o.Infix {
+			languages = append(languages, newLanguageOperator(name, &infix{
+				operatorPrecedence: operatorPrecedence(o.Precedence),
+				arbitrary: func(a, b interface{}) (interface{}, error) {
+					return nil, shadowError(name)
+				},
+				rightAssociative: o.RightAssociative,
+			}))
+		}
+		if parse, ok := shadowPostfixes[name]; ok {
+			languages = append(languages, PostfixOperator(name, parse))
+		} else if o.Postfix {
+			languages = append(languages, PostfixOperatorWithPrecedence(name, uint8(o.Precedence), func(context.Context, interface{}) (interface{}, error) {
+				return nil, shadowError(name)
+			}))
+		}
+		languages = append(languages, Precedence(name, uint8(o.Precedence)))
+	}
+	for _, f := range d.Functions {
+		name := f.Name
+		languages = append(languages, Function(name, func(arguments ...interface{}) (interface{}, error) {
+			return nil, shadowError(name)
+		}, Arity(f.MinArgs, f.MaxArgs)))
+	}
+	for _, c := range d.Constants {
+		languages = append(languages, Constant(c.Name, c.Value))
+	}
+	return NewLanguage(languages...)
+}
+
+// callArity returns the minimal and maximal number of arguments of the function name, -1 as maximum without limit.
+// They are given by the error of a call without arguments or with many arguments, as Arity may restrict the signature.
+func (l Language) callArity(name string) (min, max int) {
+	var err *arityError
+	if _, e := l.Parse(name + "()"); errors.As(e, &err) {
+		return err.min, err.max
+	}
+	if _, e := l.Parse(name + "(" + strings.Repeat("nil, ", 63) + "nil)"); errors.As(e, &err) {
+		return 0, err.max
+	}
+	return 0, -1
+}
+
+// shadowPostfixes are the postfix operators of this package parsing further operands.
+var shadowPostfixes = map[string]func(context.Context, *Parser, Evaluable) (Evaluable, error){
+	"?":  parseIf,
+	"?:": parseElvis,
+	"|>": parsePipe,
+}
+
+// shadowSyntax are the literals and variables of every shadow Language.
+var shadowSyntax = NewLanguage(
+	PrefixExtension(scanner.Int, parseNumber),
+	PrefixExtension(scanner.Float, parseNumber),
+	PrefixExtension(scanner.String, parseString),
+	PrefixExtension(scanner.Char, parseString),
+	PrefixExtension(scanner.RawString, parseString),
+	ident,
+)
+
+func shadowError(name string) error {
+	return fmt.Errorf("%s can not be evaluated by a shadow language", name)
+}
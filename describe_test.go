@@ -0,0 +1,108 @@
+package gval
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	l := NewLanguage(Full(), Pipeline(),
+		Function("between3", func(arguments ...interface{}) (interface{}, error) { return nil, nil }, Arity(2, 3)),
+		Function("atLeast1", func(arguments ...interface{}) (interface{}, error) { return nil, nil }, Arity(1, -1)),
+		Function("upper", strings.ToUpper),
+		Function("now", func(context.Context) (interface{}, error) { return nil, nil }),
+		Constant("pi", 3.14),
+	)
+	d := l.Describe()
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Description
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(d.Operators, decoded.Operators) || !reflect.DeepEqual(d.Functions, decoded.Functions) {
+		t.Errorf("Description changed by JSON:\n%+v\n%+v", d, decoded)
+	}
+
+	functions := map[string]FunctionDescription{}
+	for _, f := range decoded.Functions {
+		functions[f.Name] = f
+	}
+	for _, want := range []FunctionDescription{
+		{Name: "between3", MinArgs: 2, MaxArgs: 3},
+		{Name: "atLeast1", MinArgs: 1, MaxArgs: -1},
+		{Name: "upper", MinArgs: 1, MaxArgs: 1},
+		{Name: "now", MinArgs: 0, MaxArgs: 0},
+		{Name: "date", MinArgs: 1, MaxArgs: 1},
+	} {
+		if got := functions[want.Name]; got != want {
+			t.Errorf("function %s = %+v, want %+v", want.Name, got, want)
+		}
+	}
+	operators := map[string]OperatorDescription{}
+	for _, o := range decoded.Operators {
+		operators[o.Name] = o
+	}
+	if o := operators["&&"]; !o.Infix || o.Postfix || o.Precedence != 21 {
+		t.Errorf("operator && = %+v", o)
+	}
+	if o := operators["|>"]; o.Infix || !o.Postfix {
+		t.Errorf("operator |> = %+v", o)
+	}
+	if !reflect.DeepEqual(decoded.Prefixes, []string{"!", "(", "-", "[", "{", "~"}) {
+		t.Errorf("prefixes = %v", decoded.Prefixes)
+	}
+	if !reflect.DeepEqual(decoded.Constants, []ConstantDescription{{"false", false}, {"nil", nil}, {"pi", 3.14}, {"true", true}}) {
+		t.Errorf("constants = %v", decoded.Constants)
+	}
+
+	shadow := decoded.Language()
+	for _, expression := range []string{
+		`a + b * c - d / e`,
+		`a || b && !c`,
+		`-a ** b ** c`,
+		`x in [1, 2, y] ? {"a": z} : upper(s)`,
+		`a ?: b ?? c`,
+		`order.items[0].price > 10 && name =~ "^a"`,
+		`between3(a, b) + atLeast1(a, b, c, d)`,
+		`s |> upper()`,
+	} {
+		want, err := l.Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", expression, err)
+		}
+		got, err := shadow.Parse(expression)
+		if err != nil {
+			t.Errorf("shadow Parse(%s) error = %v", expression, err)
+		} else if Format(got) != Format(want) {
+			t.Errorf("shadow Parse(%s) = %s, want %s", expression, Format(got), Format(want))
+		}
+	}
+
+	for expression, wantErr := range map[string]string{
+		`between3(a)`:       "between3() expects at least 2 arguments but got 1",
+		`upper(a, b)`:       "upper() expects 1 arguments but got 2",
+		`a +`:               "unexpected EOF",
+		`let a = 1; a`:      "unexpected",
+		`upper(s)`:          "upper can not be evaluated by a shadow language",
+		`a + 1`:             "+ can not be evaluated by a shadow language",
+		`!a`:                "! can not be evaluated by a shadow language",
+		`[1, a] |> upper()`: "upper can not be evaluated by a shadow language",
+	} {
+		_, err := shadow.Evaluate(expression, map[string]interface{}{"a": true, "s": "x"})
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("shadow Evaluate(%s) error = %v, want %s", expression, err, wantErr)
+		}
+	}
+
+	got, err := shadow.Evaluate(`a ? [pi, "x", {"b": nil}] : false`, map[string]interface{}{"a": true})
+	if err != nil || !reflect.DeepEqual(got, []interface{}{3.14, "x", map[string]interface{}{"b": nil}}) {
+		t.Errorf("shadow Evaluate() = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,78 @@
+package gval
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Encoding contains functions to build cache keys and signatures.
+//
+//	uuid()           random UUID of version 4 like "0b9fa4b1-6c67-4c6e-9c8c-4a1e1d0a7f3e"
+//	toBase64(s)      standard base64 encoding of s
+//	fromBase64(s)    string decoded from the standard base64 encoding s
+//	toHex(s)         lower case hexadecimal encoding of s
+//	md5(s)           hexadecimal MD5 checksum of s
+//	sha256(s)        hexadecimal SHA-256 checksum of s
+//
+// The argument s is a string or a []byte.
+// All functions but uuid are Pure, so calls with constant arguments are evaluated while parsing.
+func Encoding() Language {
+	return encodingLanguage
+}
+
+var encodingLanguage = NewLanguage(
+	Function("uuid", func() (string, error) {
+		var u [16]byte
+		if _, err := rand.Read(u[:]); err != nil {
+			return "", err
+		}
+		u[6] = u[6]&0x0f | 0x40
+		u[8] = u[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+	}),
+	Function("toBase64", encodingFunc("toBase64", base64.StdEncoding.EncodeToString), Pure()),
+	Function("fromBase64", func(s interface{}) (string, error) {
+		b, err := bytesOf("fromBase64", s)
+		if err != nil {
+			return "", err
+		}
+		decoded, err := base64.StdEncoding.DecodeString(string(b))
+		if err != nil {
+			return "", fmt.Errorf("fromBase64() %w", err)
+		}
+		return string(decoded), nil
+	}, Pure()),
+	Function("toHex", encodingFunc("toHex", hex.EncodeToString), Pure()),
+	Function("md5", encodingFunc("md5", func(b []byte) string {
+		sum := md5.Sum(b)
+		return hex.EncodeToString(sum[:])
+	}), Pure()),
+	Function("sha256", encodingFunc("sha256", func(b []byte) string {
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:])
+	}), Pure()),
+)
+
+func encodingFunc(name string, encode func([]byte) string) func(s interface{}) (string, error) {
+	return func(s interface{}) (string, error) {
+		b, err := bytesOf(name, s)
+		if err != nil {
+			return "", err
+		}
+		return encode(b), nil
+	}
+}
+
+func bytesOf(name string, s interface{}) ([]byte, error) {
+	switch s := s.(type) {
+	case string:
+		return []byte(s), nil
+	case []byte:
+		return s, nil
+	}
+	return nil, fmt.Errorf("%s() expects a string but got %T", name, s)
+}
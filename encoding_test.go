@@ -0,0 +1,34 @@
+package gval
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestEncoding(t *testing.T) {
+	l := Encoding()
+	parameter := map[string]interface{}{"data": []byte{0xca, 0xfe}, "user": "alice"}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "base64", expression: `toBase64("hello") + " " + fromBase64("aGVsbG8=")`, extension: l, want: "aGVsbG8= hello"},
+			{name: "hex", expression: `toHex(data) + toHex("A")`, extension: l, parameter: parameter, want: "cafe41"},
+			{name: "md5", expression: `md5("hello")`, extension: l, want: "5d41402abc4b2a76b9719d911017c592"},
+			{name: "sha256", expression: `sha256("user:" + user)`, extension: l, parameter: parameter, want: "dabd1db8d35ab13106274f61f1bf977812cce4f477b15014cf38fb796c50a4c4"},
+			{name: "round trip", expression: `fromBase64(toBase64(user)) == user`, extension: l, parameter: parameter, want: true},
+			{name: "invalid base64", expression: `fromBase64("a!")`, extension: l, wantErr: "fromBase64() illegal base64 data"},
+			{name: "invalid argument", expression: `md5(1)`, extension: l, wantErr: "md5() expects a string but got float64"},
+		},
+		t,
+	)
+
+	uuid, err := Evaluate("uuid()", nil, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid.(string)) {
+		t.Errorf("uuid() = %s", uuid)
+	}
+	if other, _ := Evaluate("uuid()", nil, l); other == uuid {
+		t.Errorf("uuid() = %s twice", uuid)
+	}
+}
@@ -0,0 +1,81 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// EvalError is the error of an evaluation that failed.
+// It reports the innermost sub-expression that failed, e.g. the operation or
+// the variable, and wraps the error returned by it.
+type EvalError struct {
+	// Expression is the text of the failed sub-expression.
+	Expression string
+	// Offset is the byte offset of Expression in the evaluated expression,
+	// Line and Column its position starting at 1.
+	Offset       int
+	Line, Column int
+	// Err is the error returned by the sub-expression.
+	Err error
+}
+
+func (err *EvalError) Error() string {
+	return fmt.Sprintf("evaluating %s at %d:%d: %v", err.Expression, err.Line, err.Column, err.Err)
+}
+
+// Unwrap returns the error returned by the sub-expression.
+func (err *EvalError) Unwrap() error {
+	return err.Err
+}
+
+// newEvalError returns err as *EvalError of the sub-expression source[pos:end].
+// Errors of inner sub-expressions are returned unchanged.
+func newEvalError(err error, source string, pos, end int) error {
+	if _, ok := err.(*EvalError); ok {
+		return err
+	}
+	text := source[pos:end]
+	trimmed := strings.TrimLeft(text, " \t\r\n")
+	pos += len(text) - len(trimmed)
+	line, column := position(source, pos)
+	return &EvalError{
+		Expression: strings.TrimRight(trimmed, " \t\r\n"),
+		Offset:     pos,
+		Line:       line,
+		Column:     column,
+		Err:        err,
+	}
+}
+
+// evalErrors returns eval and float reporting their errors as *EvalError of source[pos:end].
+// Constants can not fail and are returned unchanged.
+func evalErrors(eval Evaluable, float floatEvaluable, source string, pos, end int) (Evaluable, floatEvaluable) {
+	if eval == nil || eval.IsConst() {
+		return eval, float
+	}
+	wrapped := func(c context.Context, parameter interface{}) (interface{}, error) {
+		v, err := eval(c, parameter)
+		if err != nil {
+			return nil, newEvalError(err, source, pos, end)
+		}
+		return v, nil
+	}
+	if float == nil {
+		return wrapped, nil
+	}
+	return wrapped, func(c context.Context, parameter interface{}) (float64, interface{}, bool, error) {
+		f, v, ok, err := float(c, parameter)
+		if err != nil {
+			return 0, nil, false, newEvalError(err, source, pos, end)
+		}
+		return f, v, ok, nil
+	}
+}
+
+// position returns the line and column starting at 1 of the byte offset in source.
+func position(source string, offset int) (line, column int) {
+	line = 1 + strings.Count(source[:offset], "\n")
+	column = 1 + len([]rune(source[strings.LastIndexByte(source[:offset], '\n')+1:offset]))
+	return line, column
+}
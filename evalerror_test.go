@@ -0,0 +1,75 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEvalError(t *testing.T) {
+	errFail := errors.New("fail")
+	lang := NewLanguage(Full(), Function("fail", func() (interface{}, error) {
+		return nil, errFail
+	}))
+	parameter := map[string]interface{}{
+		"name":  "gval",
+		"count": 3.,
+	}
+	tests := []struct {
+		name       string
+		expression string
+		want       EvalError
+	}{
+		{
+			name:       "infix",
+			expression: `count > 1 && (count * name) > 2`,
+			want:       EvalError{Expression: "count * name", Offset: 14, Line: 1, Column: 15},
+		},
+		{
+			name:       "unknown parameter",
+			expression: "count + \n  foo.bar",
+			want:       EvalError{Expression: "foo.bar", Offset: 11, Line: 2, Column: 3},
+		},
+		{
+			name:       "function",
+			expression: `name + fail()`,
+			want:       EvalError{Expression: "fail()", Offset: 7, Line: 1, Column: 8, Err: errFail},
+		},
+		{
+			name:       "postfix",
+			expression: `(fail() ? 1 : 2) + 1`,
+			want:       EvalError{Expression: "fail()", Offset: 1, Line: 1, Column: 2, Err: errFail},
+		},
+		{
+			name:       "comparison",
+			expression: `"a" + count < ("b" =~ "[" + name)`,
+			want:       EvalError{Expression: `"b" =~ "[" + name`, Offset: 15, Line: 1, Column: 16},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval, err := lang.NewEvaluable(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			program, err := lang.Compile(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, eval := range map[string]Evaluable{"Evaluable": eval, "Program": program.Evaluable()} {
+				_, err := eval(context.Background(), parameter)
+				var got *EvalError
+				if !errors.As(err, &got) {
+					t.Fatalf("%s error = %v, want *EvalError", name, err)
+				}
+				if got.Expression != tt.want.Expression || got.Offset != tt.want.Offset ||
+					got.Line != tt.want.Line || got.Column != tt.want.Column {
+					t.Errorf("%s error = %#v, want %#v", name, *got, tt.want)
+				}
+				if tt.want.Err != nil && !errors.Is(err, tt.want.Err) {
+					t.Errorf("%s error = %v, want %v", name, err, tt.want.Err)
+				}
+			}
+		})
+	}
+}
@@ -2,9 +2,9 @@ package gval
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 )
@@ -16,6 +16,18 @@ type Selector interface {
 	SelectGVal(c context.Context, key string) (interface{}, error)
 }
 
+// IndexSelector allows for custom selection of integer keys like rows[2] from containers.
+// It takes precedence over Selector for keys that are integers.
+type IndexSelector interface {
+	SelectIndexGVal(c context.Context, index int) (interface{}, error)
+}
+
+// CallSelector allows for custom method calls like row.related("orders") on containers.
+// It is consulted if the called key can not be selected as function, e.g. by Selector or via reflect.
+type CallSelector interface {
+	SelectCallGVal(c context.Context, method string, arguments []interface{}) (interface{}, error)
+}
+
 // Evaluable evaluates given parameter
 type Evaluable func(c context.Context, parameter interface{}) (interface{}, error)
 
@@ -85,13 +97,16 @@ func constant(value interface{}) Evaluable {
 // Var Evaluable represents value at given path.
 // It supports with default language VariableSelector:
 //
-//		map[interface{}]interface{},
+//		map[interface{}]interface{} (also with keys that are no strings),
 //		map[string]interface{} and
 //		[]interface{} and via reflect
 //		struct fields,
 //		struct methods,
 //		slices and
 //	 map with int or string key.
+//
+// json.RawMessage and NodeDecoder like yaml.Node are decoded before keys are selected from them.
+// Custom containers implement Selector, IndexSelector for integer keys and CallSelector for method calls.
 func (p *Parser) Var(path ...Evaluable) Evaluable {
 	if p.selector == nil {
 		return variable(path)
@@ -99,6 +114,18 @@ func (p *Parser) Var(path ...Evaluable) Evaluable {
 	return p.selector(path)
 }
 
+// vars returns Var bound to the selector of the current Language.
+// Evaluables must use it instead of p.Var because the Parser is reused after parsing.
+func (p *Parser) vars() func(path ...Evaluable) Evaluable {
+	selector := p.selector
+	return func(path ...Evaluable) Evaluable {
+		if selector == nil {
+			return variable(path)
+		}
+		return selector(path)
+	}
+}
+
 // Evaluables is a slice of Evaluable.
 type Evaluables []Evaluable
 
@@ -115,44 +142,133 @@ func (evs Evaluables) EvalStrings(c context.Context, parameter interface{}) ([]s
 	return strs, nil
 }
 
+func (evs Evaluables) areConst() bool {
+	for _, e := range evs {
+		if !e.IsConst() {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrUnknownParameter is matched by errors.Is for all errors caused by a variable path
+// that can not be selected from the parameter.
+var ErrUnknownParameter = errors.New("unknown parameter")
+
+// unknownParameterError reports the path of a variable that can not be selected.
+type unknownParameterError struct {
+	path []string
+}
+
+func (err unknownParameterError) Error() string {
+	return fmt.Sprintf("unknown parameter %s", strings.Join(err.path, "."))
+}
+
+func (err unknownParameterError) Is(target error) bool {
+	return target == ErrUnknownParameter
+}
+
 func variable(path Evaluables) Evaluable {
+	return selectPath(path, false)
+}
+
+// selectPath selects path from the parameter.
+// If strict is true, missing map keys are reported as unknown parameter instead of selecting nil.
+func selectPath(path Evaluables, strict bool) Evaluable {
+	if !path.areConst() {
+		return selectDynamicPath(path, strict)
+	}
+	if keys, err := path.EvalStrings(nil, nil); err == nil {
+		// constant paths like a.b are evaluated once instead of for every selection
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			return selectKeys(c, v, keys, strict)
+		}
+	}
+	return selectDynamicPath(path, strict)
+}
+
+func selectDynamicPath(path Evaluables, strict bool) Evaluable {
 	return func(c context.Context, v interface{}) (interface{}, error) {
 		keys, err := path.EvalStrings(c, v)
 		if err != nil {
 			return nil, err
 		}
-		for i, k := range keys {
-			switch o := v.(type) {
-			case Selector:
-				v, err = o.SelectGVal(c, k)
-				if err != nil {
-					return nil, fmt.Errorf("failed to select '%s' on %T: %w", k, o, err)
-				}
-				continue
-			case map[interface{}]interface{}:
-				v = o[k]
-				continue
-			case map[string]interface{}:
-				v = o[k]
+		return selectKeys(c, v, keys, strict)
+	}
+}
+
+// selectKeys selects keys from v. See selectPath for strict.
+func selectKeys(c context.Context, v interface{}, keys []string, strict bool) (interface{}, error) {
+	var err error
+	for i, k := range keys {
+		if v, err = decoded(v, k); err != nil {
+			return nil, err
+		}
+		k = identifier(c, v, k)
+		if r, ok, err := selectIndex(c, v, k); ok {
+			if err != nil {
+				return nil, err
+			}
+			v = r
+			continue
+		}
+		switch o := v.(type) {
+		case Selector:
+			v, err = o.SelectGVal(c, k)
+			if err != nil {
+				return nil, fmt.Errorf("failed to select '%s' on %T: %w", k, o, err)
+			}
+			continue
+		case map[interface{}]interface{}:
+			var ok bool
+			if v, ok = interfaceMapValue(o, k); !ok && strict {
+				return nil, unknownParameterError{keys[:i+1]}
+			}
+			continue
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = o[k]; !ok && strict {
+				return nil, unknownParameterError{keys[:i+1]}
+			}
+			continue
+		case []interface{}:
+			if i, ok := sliceIndex(k, len(o)); ok {
+				v = o[i]
 				continue
-			case []interface{}:
-				if i, err := strconv.Atoi(k); err == nil && i >= 0 && len(o) > i {
-					v = o[i]
-					continue
-				}
-			default:
-				var ok bool
-				v, ok = reflectSelect(k, o)
-				if !ok {
-					return nil, fmt.Errorf("unknown parameter %s", strings.Join(keys[:i+1], "."))
-				}
+			}
+			if strict {
+				return nil, unknownParameterError{keys[:i+1]}
+			}
+		default:
+			var ok bool
+			v, ok = reflectSelect(c, k, o)
+			if !ok {
+				return nil, unknownParameterError{keys[:i+1]}
 			}
 		}
-		return v, nil
 	}
+	return v, nil
 }
 
-func reflectSelect(key string, value interface{}) (selection interface{}, ok bool) {
+// selectIndex selects the integer key k of an IndexSelector v.
+// It returns false if v is no IndexSelector or k is no integer.
+func selectIndex(c context.Context, v interface{}, k string) (interface{}, bool, error) {
+	o, ok := v.(IndexSelector)
+	if !ok {
+		return nil, false, nil
+	}
+	index, err := strconv.Atoi(k)
+	if err != nil {
+		return nil, false, nil
+	}
+	v, err = o.SelectIndexGVal(c, index)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to select '%s' on %T: %w", k, o, err)
+	}
+	return v, true, nil
+}
+
+func reflectSelect(c context.Context, key string, value interface{}) (selection interface{}, ok bool) {
 	vv := reflect.ValueOf(value)
 	vvElem := resolvePotentialPointer(vv)
 
@@ -171,37 +287,108 @@ func reflectSelect(key string, value interface{}) (selection interface{}, ok boo
 		}
 
 		// key didn't exist. Check if there is a bound method
-		method := vv.MethodByName(key)
-		if method.IsValid() {
+		if method, ok := methodByName(c, vv, key); ok {
 			return method.Interface(), true
 		}
 
 	case reflect.Slice:
-		if i, err := strconv.Atoi(key); err == nil && i >= 0 && vv.Len() > i {
+		if i, ok := sliceIndex(key, vv.Len()); ok {
 			vvElem = resolvePotentialPointer(vv.Index(i))
 			return vvElem.Interface(), true
 		}
 
 		// key not an int. Check if there is a bound method
-		method := vv.MethodByName(key)
-		if method.IsValid() {
+		if method, ok := methodByName(c, vv, key); ok {
 			return method.Interface(), true
 		}
 
 	case reflect.Struct:
-		field := vvElem.FieldByName(key)
-		if field.IsValid() {
+		field, ok := structField(vvElem, key)
+		if ok {
 			return field.Interface(), true
 		}
 
-		method := vv.MethodByName(key)
-		if method.IsValid() {
+		if method, ok := methodByName(c, vv, key); ok {
 			return method.Interface(), true
 		}
 	}
 	return nil, false
 }
 
+// structField selects the exported field of a struct by its name or,
+// if no field has that name, by its `gval:"name"` or `json:"name"` struct tag.
+// Fields of embedded structs and embedded struct pointers are selected as well.
+func structField(v reflect.Value, key string) (reflect.Value, bool) {
+	if field, ok := v.Type().FieldByName(key); ok && field.PkgPath == "" {
+		if field, ok := fieldByIndex(v, field.Index); ok {
+			return field, true
+		}
+	}
+	for _, tag := range []string{"gval", "json"} {
+		if field, ok := taggedField(v, tag, key); ok {
+			return field, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but fails on nil embedded struct pointers instead of panicking.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func taggedField(v reflect.Value, tag, key string) (reflect.Value, bool) {
+	t := v.Type()
+	var embedded []reflect.Value
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get(tag), ",")[0]
+		if f.PkgPath == "" && name == key {
+			return v.Field(i), true
+		}
+		if f.Anonymous && name == "" {
+			e := v.Field(i)
+			if e.Kind() == reflect.Ptr {
+				if e.IsNil() {
+					continue
+				}
+				e = e.Elem()
+			}
+			if e.Kind() == reflect.Struct {
+				embedded = append(embedded, e)
+			}
+		}
+	}
+	for _, e := range embedded {
+		if field, ok := taggedField(e, tag, key); ok {
+			return field, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// sliceIndex converts key to an index of a slice with given length.
+// Negative keys count from the end of the slice.
+func sliceIndex(key string, length int) (int, bool) {
+	i, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, false
+	}
+	if i < 0 {
+		i += length
+	}
+	return i, i >= 0 && i < length
+}
+
 func resolvePotentialPointer(value reflect.Value) reflect.Value {
 	if value.Kind() == reflect.Ptr {
 		return value.Elem()
@@ -221,7 +408,10 @@ func reflectConvertTo(k reflect.Kind, value string) (interface{}, bool) {
 	return nil, false
 }
 
-func (*Parser) callFunc(fun function, args ...Evaluable) Evaluable {
+func (p *Parser) callFunc(name string, fun function, args ...Evaluable) Evaluable {
+	if p.nodes.instrumentation() != nil {
+		fun = observeFunction(name, fun, p.nodes.observer)
+	}
 	return func(c context.Context, v interface{}) (ret interface{}, err error) {
 		a := make([]interface{}, len(args))
 		for i, arg := range args {
@@ -235,6 +425,30 @@ func (*Parser) callFunc(fun function, args ...Evaluable) Evaluable {
 	}
 }
 
+// methodValue returns the function selected by fun.
+// If the selection fails or is no function and the receiver is a CallSelector,
+// it returns the call of the method key on the receiver instead.
+func methodValue(fun, receiver, key Evaluable) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		f, err := fun(c, v)
+		if err == nil && reflect.ValueOf(f).Kind() == reflect.Func {
+			return f, nil
+		}
+		r, rErr := receiver(c, v)
+		o, ok := r.(CallSelector)
+		if rErr != nil || !ok {
+			return f, err
+		}
+		method, err := key.EvalString(c, v)
+		if err != nil {
+			return nil, err
+		}
+		return func(c context.Context, arguments ...interface{}) (interface{}, error) {
+			return o.SelectCallGVal(c, method, arguments)
+		}, nil
+	}
+}
+
 func (*Parser) callEvaluable(fullname string, fun Evaluable, args ...Evaluable) Evaluable {
 	return func(c context.Context, v interface{}) (ret interface{}, err error) {
 		f, err := fun(c, v)
@@ -256,13 +470,20 @@ func (*Parser) callEvaluable(fullname string, fun Evaluable, args ...Evaluable)
 			return nil, fmt.Errorf("could not call '%s' type %T", fullname, f)
 		}
 
-		a := make([]reflect.Value, len(args))
+		a := make([]reflect.Value, 0, len(args)+1)
+		// if first argument is a context, use the given execution context
+		if t := ff.Type(); t.NumIn() > 0 && t.In(0) == reflect.TypeOf(createCallArguments).In(0) {
+			if c == nil {
+				c = context.Background()
+			}
+			a = append(a, reflect.ValueOf(c))
+		}
 		for i := range args {
 			arg, err := args[i](c, v)
 			if err != nil {
 				return nil, err
 			}
-			a[i] = reflect.ValueOf(arg)
+			a = append(a, callArgument(ff.Type(), len(a), arg))
 		}
 
 		rr := ff.Call(a)
@@ -291,6 +512,24 @@ func (*Parser) callEvaluable(fullname string, fun Evaluable, args ...Evaluable)
 	}
 }
 
+// callArgument returns arg as i-th argument of a function of type t, converted like the arguments of a Function.
+// Arguments that can not be converted are passed as they are, so Call reports the mismatch.
+func callArgument(t reflect.Type, i int, arg interface{}) reflect.Value {
+	var in reflect.Type
+	switch {
+	case t.IsVariadic() && i >= t.NumIn()-1:
+		in = t.In(t.NumIn() - 1).Elem()
+	case i < t.NumIn():
+		in = t.In(i)
+	default:
+		return reflect.ValueOf(arg)
+	}
+	if converted, ok := convertArgument(arg, in); ok {
+		return converted
+	}
+	return reflect.ValueOf(arg)
+}
+
 // IsConst returns if the Evaluable is a Parser.Const() value
 func (e Evaluable) IsConst() bool {
 	pc := reflect.ValueOf(constant(nil)).Pointer()
@@ -299,65 +538,9 @@ func (e Evaluable) IsConst() bool {
 }
 
 func regEx(a, b Evaluable) (Evaluable, error) {
-	if !b.IsConst() {
-		return func(c context.Context, o interface{}) (interface{}, error) {
-			a, err := a.EvalString(c, o)
-			if err != nil {
-				return nil, err
-			}
-			b, err := b.EvalString(c, o)
-			if err != nil {
-				return nil, err
-			}
-			matched, err := regexp.MatchString(b, a)
-			return matched, err
-		}, nil
-	}
-	s, err := b.EvalString(context.TODO(), nil)
-	if err != nil {
-		return nil, err
-	}
-	regex, err := regexp.Compile(s)
-	if err != nil {
-		return nil, err
-	}
-	return func(c context.Context, v interface{}) (interface{}, error) {
-		s, err := a.EvalString(c, v)
-		if err != nil {
-			return nil, err
-		}
-		return regex.MatchString(s), nil
-	}, nil
+	return RegexLimits{}.matchOperator(false)(a, b)
 }
 
 func notRegEx(a, b Evaluable) (Evaluable, error) {
-	if !b.IsConst() {
-		return func(c context.Context, o interface{}) (interface{}, error) {
-			a, err := a.EvalString(c, o)
-			if err != nil {
-				return nil, err
-			}
-			b, err := b.EvalString(c, o)
-			if err != nil {
-				return nil, err
-			}
-			matched, err := regexp.MatchString(b, a)
-			return !matched, err
-		}, nil
-	}
-	s, err := b.EvalString(context.TODO(), nil)
-	if err != nil {
-		return nil, err
-	}
-	regex, err := regexp.Compile(s)
-	if err != nil {
-		return nil, err
-	}
-	return func(c context.Context, v interface{}) (interface{}, error) {
-		s, err := a.EvalString(c, v)
-		if err != nil {
-			return nil, err
-		}
-		return !regex.MatchString(s), nil
-	}, nil
+	return RegexLimits{}.matchOperator(true)(a, b)
 }
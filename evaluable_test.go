@@ -248,3 +248,175 @@ func TestEvaluable_CustomSelector(t *testing.T) {
 		})
 	}
 }
+
+type taggedAudit struct {
+	CreatedBy string `json:"created_by"`
+}
+
+type taggedCustomer struct {
+	Name string `gval:"customer_name" json:"name"`
+}
+
+type taggedLineItem struct {
+	Sku      string  `json:"sku"`
+	Quantity float64 `json:"quantity,omitempty"`
+	Hidden   string  `json:"-"`
+}
+
+type taggedOrder struct {
+	taggedAudit
+	*taggedCustomer
+	LineItems []taggedLineItem `json:"line_items"`
+	secret    string
+}
+
+func TestEvaluable_StructTags(t *testing.T) {
+	order := taggedOrder{
+		taggedAudit:    taggedAudit{CreatedBy: "bob"},
+		taggedCustomer: &taggedCustomer{Name: "ann"},
+		LineItems:      []taggedLineItem{{Sku: "a", Quantity: 2}},
+		secret:         "s",
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "json tag",
+				expression: `order.line_items[0].sku`,
+				parameter:  map[string]interface{}{"order": order},
+				want:       "a",
+			},
+			{
+				name:       "json tag with options",
+				expression: `order.line_items[0].quantity`,
+				parameter:  map[string]interface{}{"order": &order},
+				want:       2.,
+			},
+			{
+				name:       "field name",
+				expression: `order.LineItems[0].Sku`,
+				parameter:  map[string]interface{}{"order": order},
+				want:       "a",
+			},
+			{
+				name:       "gval tag before json tag",
+				expression: `order.customer_name + order.name`,
+				parameter:  map[string]interface{}{"order": order},
+				want:       "annann",
+			},
+			{
+				name:       "embedded struct",
+				expression: `order.created_by`,
+				parameter:  map[string]interface{}{"order": order},
+				want:       "bob",
+			},
+			{
+				name:       "nil embedded struct pointer",
+				expression: `order.customer_name`,
+				parameter:  map[string]interface{}{"order": taggedOrder{}},
+				wantErr:    "unknown parameter order.customer_name",
+			},
+			{
+				name:       "nil embedded struct pointer field name",
+				expression: `order.Name`,
+				parameter:  map[string]interface{}{"order": taggedOrder{}},
+				wantErr:    "unknown parameter order.Name",
+			},
+			{
+				name:       "unexported field",
+				expression: `order.secret`,
+				parameter:  map[string]interface{}{"order": order},
+				wantErr:    "unknown parameter order.secret",
+			},
+		},
+		t,
+	)
+}
+
+// testRows selects columns by index and the rows starting with a prefix by the method startingWith
+type testRows []string
+
+func (r testRows) SelectIndexGVal(c context.Context, index int) (interface{}, error) {
+	if index < 0 || index >= len(r) {
+		return nil, fmt.Errorf("row %d out of range", index)
+	}
+	return r[index], nil
+}
+
+func (r testRows) SelectGVal(c context.Context, key string) (interface{}, error) {
+	if key == "size" {
+		return len(r), nil
+	}
+	return nil, fmt.Errorf("unknown column %s", key)
+}
+
+func (r testRows) SelectCallGVal(c context.Context, method string, arguments []interface{}) (interface{}, error) {
+	if method != "startingWith" || len(arguments) != 1 {
+		return nil, fmt.Errorf("unknown method %s", method)
+	}
+	prefix := fmt.Sprint(arguments[0])
+	matches := testRows{}
+	for _, s := range r {
+		if strings.HasPrefix(s, prefix) {
+			matches = append(matches, s)
+		}
+	}
+	return matches, nil
+}
+
+func TestEvaluable_IndexAndCallSelector(t *testing.T) {
+	rows := testRows{"apple", "banana", "avocado"}
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "index",
+				expression: `rows[1]`,
+				parameter:  map[string]interface{}{"rows": rows},
+				want:       "banana",
+			},
+			{
+				name:       "computed index",
+				expression: `rows[1+1]`,
+				parameter:  map[string]interface{}{"rows": rows},
+				want:       "avocado",
+			},
+			{
+				name:       "index error",
+				expression: `rows[5]`,
+				parameter:  map[string]interface{}{"rows": rows},
+				wantErr:    "row 5 out of range",
+			},
+			{
+				name:       "key",
+				expression: `rows.size`,
+				parameter:  map[string]interface{}{"rows": rows},
+				want:       3,
+			},
+			{
+				name:       "call",
+				expression: `rows.startingWith("a")[1]`,
+				parameter:  map[string]interface{}{"rows": rows},
+				want:       "avocado",
+			},
+			{
+				name:       "call on parameter",
+				expression: `startingWith("b")[0]`,
+				parameter:  rows,
+				want:       "banana",
+			},
+			{
+				name:       "call error",
+				expression: `rows.delete()`,
+				parameter:  map[string]interface{}{"rows": rows},
+				wantErr:    "unknown method delete",
+			},
+			{
+				name:       "missing field as nil",
+				expression: `rows[2]`,
+				extension:  MissingFieldAsNil(),
+				parameter:  map[string]interface{}{"rows": rows},
+				want:       "avocado",
+			},
+		},
+		t,
+	)
+}
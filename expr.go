@@ -0,0 +1,232 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ExprCompat returns a Language with the surface syntax of expr-lang (github.com/expr-lang/expr)
+// on top of the Full language, so rules can be moved between both engines:
+//
+//	all(array, predicate)     true iff the predicate is true for all elements
+//	any(array, predicate)     true iff the predicate is true for any element
+//	one(array, predicate)     true iff the predicate is true for exactly one element
+//	none(array, predicate)    true iff the predicate is true for no element
+//	count(array, predicate)   number of elements the predicate is true for
+//	filter(array, predicate)  elements the predicate is true for
+//	map(array, expression)    values of the expression for the elements
+//	a | f(b)                  f(a, b) like |> of Pipeline, e.g. users | filter(.Age >= 18) | map(.Name)
+//	and, or, not              &&, || and !
+//	contains, startsWith, endsWith, matches   string operators like s startsWith "a"
+//	^                         power like **
+//	a ?? b                    a if it is not nil, otherwise b
+//
+// The predicate is evaluated for each element, which is # in the predicate. Fields of the element are
+// selected by #.name or .name. Predicates may be enclosed in braces, e.g. all(tweets, {.Size < 280}).
+// Methods of values are called like in Go, e.g. user.Name().
+func ExprCompat() Language {
+	return exprCompat
+}
+
+var exprCompat = NewLanguage(full.Without("|", "??"),
+	PrefixExtension('#', func(c context.Context, p *Parser) (Evaluable, error) {
+		return exprElementValue, nil
+	}),
+	PrefixExtension('.', func(c context.Context, p *Parser) (Evaluable, error) {
+		// .name is #.name
+		p.Camouflage("field", '.')
+		return p.parseSelectors(c, "", "", exprElementValue, nil)
+	}),
+
+	exprBuiltin("all", func(elements []interface{}, test func(i int) (bool, error)) (interface{}, error) {
+		for i := range elements {
+			if ok, err := test(i); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}),
+	exprBuiltin("any", func(elements []interface{}, test func(i int) (bool, error)) (interface{}, error) {
+		for i := range elements {
+			if ok, err := test(i); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}),
+	exprBuiltin("one", func(elements []interface{}, test func(i int) (bool, error)) (interface{}, error) {
+		n, err := exprCount(elements, test, 2)
+		return n == 1, err
+	}),
+	exprBuiltin("none", func(elements []interface{}, test func(i int) (bool, error)) (interface{}, error) {
+		n, err := exprCount(elements, test, 1)
+		return n == 0, err
+	}),
+	exprBuiltin("count", func(elements []interface{}, test func(i int) (bool, error)) (interface{}, error) {
+		n, err := exprCount(elements, test, len(elements))
+		return float64(n), err
+	}),
+	exprBuiltin("filter", func(elements []interface{}, test func(i int) (bool, error)) (interface{}, error) {
+		filtered := []interface{}{}
+		for i, x := range elements {
+			ok, err := test(i)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				filtered = append(filtered, x)
+			}
+		}
+		return filtered, nil
+	}),
+	exprMap,
+
+	PostfixOperator("|", parsePipe),
+	Precedence("|", 10),
+	Alias("^", "**"),
+	Alias("and", "&&"),
+	Alias("or", "||"),
+	Alias("not", "!"),
+	InfixTextOperator("contains", containsOp),
+	InfixTextOperator("startsWith", startsWithOp),
+	InfixTextOperator("endsWith", endsWithOp),
+	InfixEvalOperator("matches", regEx),
+	Precedence("contains", 40),
+	Precedence("startsWith", 40),
+	Precedence("endsWith", 40),
+	Precedence("matches", 40),
+	InfixEvalOperator("??", coalesce),
+)
+
+// exprElementKey is the context key of the element a predicate is evaluated for.
+type exprElementKey struct{}
+
+// exprElement is the element a predicate is evaluated for.
+type exprElement struct {
+	value interface{}
+}
+
+// exprElementValue is the value of # in a predicate.
+func exprElementValue(c context.Context, v interface{}) (interface{}, error) {
+	e, ok := c.Value(exprElementKey{}).(exprElement)
+	if !ok {
+		return nil, fmt.Errorf("# is only defined in predicates")
+	}
+	return e.value, nil
+}
+
+// exprCount counts the elements the predicate is true for up to max.
+func exprCount(elements []interface{}, test func(i int) (bool, error), max int) (int, error) {
+	n := 0
+	for i := 0; i < len(elements) && n < max; i++ {
+		ok, err := test(i)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+var exprMap = exprFunction("map", func(elements []interface{}, call func(i int) (interface{}, error)) (interface{}, error) {
+	mapped := make([]interface{}, len(elements))
+	for i := range elements {
+		v, err := call(i)
+		if err != nil {
+			return nil, err
+		}
+		mapped[i] = v
+	}
+	return mapped, nil
+})
+
+// exprBuiltin returns a Language with the function name of an array and a predicate testing its elements.
+func exprBuiltin(name string, f func(elements []interface{}, test func(i int) (bool, error)) (interface{}, error)) Language {
+	return exprFunction(name, func(elements []interface{}, call func(i int) (interface{}, error)) (interface{}, error) {
+		return f(elements, func(i int) (bool, error) {
+			v, err := call(i)
+			if err != nil {
+				return false, err
+			}
+			ok, isBool := v.(bool)
+			if !isBool {
+				return false, fmt.Errorf("%s() expects a bool predicate but got %v (%T)", name, v, v)
+			}
+			return ok, nil
+		})
+	})
+}
+
+// exprFunction returns a Language with the function name of an array and an expression evaluated by call
+// for the element i. The function is a variable unless it is called.
+func exprFunction(name string, f func(elements []interface{}, call func(i int) (interface{}, error)) (interface{}, error)) Language {
+	l := newLanguage()
+	l.functions[name] = reflect.TypeOf(func(array, predicate interface{}) (interface{}, error) { return nil, nil })
+	l.prefixes[name] = func(c context.Context, p *Parser) (Evaluable, error) {
+		args := p.pipedArguments()
+		if p.Scan() != '(' {
+			return nil, p.Expected(name, '(')
+		}
+		for scan := p.Scan(); scan != ')'; {
+			p.Camouflage(name, ')')
+			arg, err := parseExprArgument(c, p, len(args) == 1)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			switch scan = p.Scan(); scan {
+			case ',':
+				scan = p.Scan()
+			case ')':
+			default:
+				return nil, p.Expected(name, ',', ')')
+			}
+		}
+		if err := checkArity(name, 2, 2, len(args)); err != nil {
+			return nil, err
+		}
+		array, predicate := args[0], args[1]
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			a, err := array(c, v)
+			if err != nil {
+				return nil, err
+			}
+			collected, err := collect(c, a)
+			if err != nil {
+				return nil, err
+			}
+			elements, ok := convertToSlice(collected)
+			if !ok {
+				return nil, fmt.Errorf("%s() expects an array but got %v (%T)", name, a, a)
+			}
+			return f(elements, func(i int) (interface{}, error) {
+				if err := CheckContext(c, i); err != nil {
+					return nil, err
+				}
+				return predicate(context.WithValue(c, exprElementKey{}, exprElement{elements[i]}), v)
+			})
+		}, nil
+	}
+	return builtin(l, name)
+}
+
+// parseExprArgument parses an argument, a predicate may be enclosed in braces.
+func parseExprArgument(c context.Context, p *Parser, predicate bool) (Evaluable, error) {
+	if !predicate || p.Scan() != '{' {
+		if predicate {
+			p.Camouflage("predicate", '{')
+		}
+		return p.ParseExpression(c)
+	}
+	eval, err := p.ParseExpression(c)
+	if err != nil {
+		return nil, err
+	}
+	if p.Scan() != '}' {
+		return nil, p.Expected("predicate", '}')
+	}
+	return eval, nil
+}
@@ -0,0 +1,74 @@
+package gval
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type exprTweet struct {
+	Size float64
+	Text string
+}
+
+func (t exprTweet) Shout() string {
+	return strings.ToUpper(t.Text)
+}
+
+func TestExprCompat(t *testing.T) {
+	parameter := map[string]interface{}{
+		"tweets": []exprTweet{{10, "hi"}, {300, "long"}},
+		"users": []interface{}{
+			map[string]interface{}{"Age": 20., "Name": "Ann"},
+			map[string]interface{}{"Age": 10., "Name": "Bob"},
+		},
+		"nums":    []interface{}{1., 2., 3.},
+		"missing": nil,
+	}
+	for _, test := range []struct {
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{expression: `all(tweets, {.Size < 280})`, want: false},
+		{expression: `any(tweets, .Size > 280)`, want: true},
+		{expression: `one(tweets, #.Size > 100)`, want: true},
+		{expression: `one(tweets, #.Size > 5)`, want: false},
+		{expression: `none(nums, # > 3)`, want: true},
+		{expression: `count(nums, # > 1)`, want: 2.},
+		{expression: `filter(nums, # > 1)`, want: []interface{}{2., 3.}},
+		{expression: `map(nums, {# * 10})`, want: []interface{}{10., 20., 30.}},
+		{expression: `map(tweets, #.Shout())`, want: []interface{}{"HI", "LONG"}},
+		{expression: `users | filter(.Age >= 18) | map(.Name)`, want: []interface{}{"Ann"}},
+		{expression: `nums | count(# > 0)`, want: 3.},
+		{expression: `any(users, .Name == "Bob" && .Age < 18)`, want: true},
+		{expression: `map(nums, count(nums, # > 1))`, want: []interface{}{2., 2., 2.}},
+		{expression: `len(filter(nums, # >= 2)) == 2 ? "two" : "other"`, want: "two"},
+		{expression: `all([], # > 0) && !any([], # > 0)`, want: true},
+		{expression: `2 ^ 3`, want: 8.},
+		{expression: `true and not false or false`, want: true},
+		{expression: `"abc" startsWith "a" and "abc" contains "b" and "abc" endsWith "c" and "abc" matches "^a.c$"`, want: true},
+		{expression: `missing ?? 1`, want: 1.},
+		{expression: `0 ?? 1`, want: 0.},
+		{expression: `# + 1`, wantErr: "# is only defined in predicates"},
+		{expression: `all(nums)`, wantErr: "all() expects 2 arguments but got 1"},
+		{expression: `filter(nums, # + 1)`, wantErr: "filter() expects a bool predicate but got 2 (float64)"},
+		{expression: `map(1, #)`, wantErr: "map() expects an array but got 1 (float64)"},
+		{expression: `all(nums, {# > 0)`, wantErr: "while scanning predicate"},
+	} {
+		got, err := ExprCompat().Evaluate(test.expression, parameter)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Evaluate(%s) = %v, %v, want error %s", test.expression, got, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Evaluate(%s) = %v, %v, want %v", test.expression, got, err, test.want)
+		}
+	}
+
+	if got, err := ExprCompat().Evaluate(`map + 1`, map[string]interface{}{"map": 1.}); err != nil || got != 2. {
+		t.Errorf("Evaluate(map + 1) = %v, %v, want the variable map", got, err)
+	}
+}
@@ -0,0 +1,263 @@
+package gval
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Format returns the canonical text of a syntax tree created by Language.Parse or the builders like NewInfix.
+// Infix operators are surrounded by single spaces, parentheses are set according to operator precedence,
+// string and number literals are normalized and variables are written as a.b["c d"][0].
+// Other whitespace is collapsed, e.g. f( a,b ) is formatted as f(a, b).
+func Format(node *Node) string {
+	if node == nil {
+		return ""
+	}
+	b := &strings.Builder{}
+	node.format(b)
+	return b.String()
+}
+
+func (n *Node) format(b *strings.Builder) {
+	switch n.Kind {
+	case ConstantNode:
+		b.WriteString(n.literal())
+	case VariableNode:
+		writePath(b, n.Path)
+	case InfixNode:
+		a, c := n.Children[0], n.Children[1]
+		// operands with the same precedence are enclosed unless the operator groups them,
+		// comparisons also if they would be chained like (1 < x) < 3
+		right := n.rightAssociative && n.precedence == a.precedence
+		chained := n.precedence == a.precedence && a.Kind == InfixNode &&
+			full.chainsComparison(n.Operator) && full.chainsComparison(a.Operator)
+		a.formatEnclosed(b, a.precedence < n.precedence || right || chained || a.Kind == PostfixNode && len(a.Children) > 1)
+		b.WriteString(" ")
+		b.WriteString(n.Operator)
+		b.WriteString(" ")
+		c.formatEnclosed(b, c.precedence < n.precedence || c.precedence == n.precedence && !n.rightAssociative)
+	case PostfixNode:
+		a := n.Children[0]
+		// operands with the same precedence are enclosed, e.g. (a ? b : c) ? d : e
+		a.formatEnclosed(b, a.precedence <= n.precedence)
+		pos := a.Pos
+		for _, child := range n.Children {
+			if child != a {
+				writeOperatorGlue(b, withoutComments(n.source[pos:child.Pos]), true)
+				child.format(b)
+			}
+			pos = child.End
+		}
+		glue := withoutComments(n.source[pos:n.end])
+		if len(n.Children) == 1 && !startsWithWord(glue) {
+			// postfix operators like % are not separated from their operand
+			b.WriteString(strings.Join(strings.Fields(glue), " "))
+			break
+		}
+		writeOperatorGlue(b, glue, false)
+	default:
+		n.formatOperand(b)
+	}
+}
+
+func (n *Node) formatEnclosed(b *strings.Builder, enclosed bool) {
+	if !enclosed {
+		n.format(b)
+		return
+	}
+	b.WriteString("(")
+	n.format(b)
+	b.WriteString(")")
+}
+
+// literal returns the normalized text of a constant.
+// Named constants like true are returned as written.
+func (n *Node) literal() string {
+	text := n.source[n.start:n.end]
+	if text == "" {
+		return text
+	}
+	switch v := n.Value.(type) {
+	case string:
+		if strings.ContainsRune("\"'`", rune(text[0])) {
+			return strconv.Quote(v)
+		}
+	case float64:
+		if text[0] == '.' || unicode.IsDigit(rune(text[0])) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+	}
+	return text
+}
+
+func writePath(b *strings.Builder, path []string) {
+	for i, key := range path {
+		switch {
+		case i == 0:
+			b.WriteString(key)
+		case isIdentifier(key):
+			b.WriteString(".")
+			b.WriteString(key)
+		default:
+			b.WriteString("[")
+			if _, err := strconv.Atoi(key); err == nil {
+				b.WriteString(key)
+			} else {
+				b.WriteString(strconv.Quote(key))
+			}
+			b.WriteString("]")
+		}
+	}
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// formatOperand writes the text of the operand with its children formatted
+// and the text between them normalized.
+func (n *Node) formatOperand(b *strings.Builder) {
+	pieces := make([]string, 0, 2*len(n.Children)+1)
+	pos := n.start
+	for _, child := range n.Children {
+		c := &strings.Builder{}
+		child.formatEnclosed(c, child.parenthesized && (child.Kind == InfixNode || child.Kind == PostfixNode))
+		pieces = append(pieces, withoutComments(n.source[pos:child.Pos]), c.String())
+		pos = child.End
+	}
+	pieces = append(pieces, withoutComments(n.source[pos:n.end]))
+
+	// prefix operators like - are not separated from their operand
+	if strings.IndexFunc(pieces[0], func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_\"'`", r)
+	}) < 0 {
+		pieces[0] = strings.Join(strings.Fields(pieces[0]), "")
+	}
+
+	for i, piece := range pieces {
+		if i%2 == 1 {
+			b.WriteString(piece)
+			continue
+		}
+		var next byte
+		if i+1 < len(pieces) && pieces[i+1] != "" {
+			next = pieces[i+1][0]
+		}
+		writeGlue(b, piece, next)
+	}
+}
+
+// writeGlue writes text between formatted children.
+// Whitespace is collapsed to a single space and removed inside brackets and around dots and colons.
+// Commas are followed by a space. Quoted text is written as is.
+func writeGlue(b *strings.Builder, glue string, next byte) {
+	space := false
+	for i := 0; i < len(glue); i++ {
+		c := glue[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			space = true
+			continue
+		case space:
+			writeSpace(b, c)
+			space = false
+		}
+		if strings.IndexByte("\"'`", c) >= 0 {
+			end := closingQuote(glue, i)
+			b.WriteString(glue[i:end])
+			i = end - 1
+			continue
+		}
+		b.WriteByte(c)
+		space = c == ','
+	}
+	if space && next != 0 {
+		writeSpace(b, next)
+	}
+}
+
+func writeSpace(b *strings.Builder, next byte) {
+	s := b.String()
+	if s == "" {
+		return
+	}
+	if strings.IndexByte("([{.:", s[len(s)-1]) >= 0 || strings.IndexByte(")]}.,:", next) >= 0 {
+		return
+	}
+	b.WriteByte(' ')
+}
+
+// closingQuote returns the offset after the quoted text starting at i.
+func closingQuote(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case quote:
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// withoutComments returns s with each // and /* */ comment outside of quotes replaced by a space.
+func withoutComments(s string) string {
+	if !strings.Contains(s, "/") {
+		return s
+	}
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.IndexByte("\"'`", s[i]) >= 0:
+			end := closingQuote(s, i)
+			b.WriteString(s[i:end])
+			i = end - 1
+		case strings.HasPrefix(s[i:], "//"):
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				end = len(s) - i
+			}
+			b.WriteByte(' ')
+			i += end - 1
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				end = len(s) - i - 4
+			}
+			b.WriteByte(' ')
+			i += end + 3
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// writeOperatorGlue writes the text of a postfix operator preceded by a single space.
+// If more follows, it is followed by a single space as well.
+// startsWithWord reports whether s starts with a letter, digit or underscore after leading spaces.
+func startsWithWord(s string) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && (s[0] == '_' || unicode.IsLetter(rune(s[0])) || unicode.IsDigit(rune(s[0])))
+}
+
+func writeOperatorGlue(b *strings.Builder, glue string, more bool) {
+	glue = strings.Join(strings.Fields(glue), " ")
+	if glue == "" {
+		return
+	}
+	b.WriteString(" ")
+	b.WriteString(glue)
+	if more {
+		b.WriteString(" ")
+	}
+}
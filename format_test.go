@@ -0,0 +1,128 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		extension  Language
+		want       string
+	}{
+		{name: "spacing", expression: "1+2*3", want: "1 + 2 * 3"},
+		{name: "redundant parentheses", expression: "(1*2)+((3))", want: "1 * 2 + 3"},
+		{name: "required parentheses", expression: "(1+2)*3", want: "(1 + 2) * 3"},
+		{name: "right associativity", expression: "a-(b-c)", want: "a - (b - c)"},
+		{name: "left associativity", expression: "(a-b)-c", want: "a - b - c"},
+		{name: "string literal", expression: "`a` + 'b' + \"c\"", want: `"a" + "b" + "c"`},
+		{name: "number literal", expression: "1.50 + 2", want: "1.5 + 2"},
+		{name: "named constants", expression: "true&&nil==false", want: "true && nil == false"},
+		{name: "variables", expression: `a . b["c"][ 0 ]["d e"]`, want: `a.b.c[0]["d e"]`},
+		{name: "dynamic keys", expression: `a[ b+1 ]`, want: `a[b + 1]`},
+		{name: "function", expression: `date( "2020-01-01" )`, want: `date("2020-01-01")`},
+		{name: "method", expression: `a.Func( 1,2 )`, want: `a.Func(1, 2)`},
+		{name: "prefix", expression: `!( a&&b ) || - ( 1+2 )`, want: `!(a && b) || -(1 + 2)`},
+		{name: "prefix without parentheses", expression: `-(a)`, want: `-a`},
+		{name: "array", expression: `[ 1,2 , [3] ]`, want: `[1, 2, [3]]`},
+		{name: "object", expression: `{ "a" : 1,"b":x+1 }`, want: `{"a":1, "b":x + 1}`},
+		{name: "parenthesized comparison", expression: `(1<n)<4`, want: `(1 < n) < 4`},
+		{name: "chained comparison", expression: `1<n<=4`, want: `1 < n && n <= 4`},
+		{name: "comparison of equality", expression: `(a==b)<4`, want: `a == b < 4`},
+		{name: "ternary", expression: `a>1?"x":b`, want: `a > 1 ? "x" : b`},
+		{name: "ternary condition", expression: `(x?1:y)?2:3`, want: `(x ? 1 : y) ? 2 : 3`},
+		{name: "nested ternary", expression: `x?1:y?2:3`, want: `x ? 1 : y ? 2 : 3`},
+		{name: "ternary operand", expression: `(a?b:c)+1`, want: `(a ? b : c) + 1`},
+		{name: "elvis", expression: `(a?:b)+1 == c?:d`, want: `(a ?: b) + 1 == c ?: d`},
+		{name: "ternary left of infix", expression: `(a?b:c)??d`, want: `(a ? b : c) ?? d`},
+		{name: "word operators", expression: `x   in [1,2] && y between [1,3]`, want: `x in [1, 2] && y between [1, 3]`},
+		{name: "slice", expression: `a[ 1 : 2 ]`, want: `a[1:2]`},
+		{name: "selection from operands", expression: `date( x ).Unix( ) + ( a+b ).c[ 0 ]`, want: `date(x).Unix() + (a + b).c[0]`},
+		{name: "pipeline", expression: `a+b|>date( ) == x  |> date`, want: `(a + b |> date()) == x |> date`},
+		{name: "selection from pipeline", expression: `m |> keys( )[ k ].a`, want: `m |> keys()[k].a`},
+		{name: "comments", expression: "[1, // one\n 2 /* two */, a ? /* then */ b : c, f( // x\n x)] // end", want: `[1, 2, a ? b : c, f(x)]`},
+		{name: "jsonpath", expression: `$..book[?( @.price<10 )].title`, extension: NewLanguage(Full(), JSONPath()), want: `$..book[?(@.price < 10)].title`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := Full()
+			if tt.extension.prefixes != nil {
+				l = tt.extension
+			}
+			node, err := l.Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%s) error = %v", tt.expression, err)
+			}
+			got := Format(node)
+			if got != tt.want {
+				t.Errorf("Format(%s) = %s, want %s", tt.expression, got, tt.want)
+			}
+			if node, err = l.Parse(got); err != nil || Format(node) != got {
+				t.Errorf("Format(%s) = %s is not stable", tt.expression, got)
+			}
+		})
+	}
+}
+
+func TestFormat_evaluation(t *testing.T) {
+	for _, expression := range []string{
+		`(1 < n) < 4`,
+		`(1 < n) <= 4`,
+		`(n > 1) > 0`,
+		`1 < n < 4`,
+		`1 < n < 2`,
+		`(1 < n) == (n < 4)`,
+		`(n > 1 ? true : n < 0) ? 2 : 3`,
+		`n > 1 ? true : n < 0 ? 2 : 3`,
+		`(n ?: 1) ?: 2`,
+	} {
+		node, err := Full().Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", expression, err)
+		}
+		formatted := Format(node)
+		for _, n := range []float64{0, 3, 5} {
+			parameter := map[string]interface{}{"n": n}
+			want, wantErr := Full().Evaluate(expression, parameter)
+			got, err := Full().Evaluate(formatted, parameter)
+			if got != want || (err != nil) != (wantErr != nil) {
+				t.Errorf("Evaluate(%s) = %v, %v with n = %v, but Format() %s evaluates to %v, %v", expression, want, wantErr, n, formatted, got, err)
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	node, err := Full().Parse(`a.b + f(1, 2) * 3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Kind != InfixNode || node.Operator != "+" {
+		t.Fatalf("Parse() = %v %s, want infix +", node.Kind, node.Operator)
+	}
+	variable := node.Children[0]
+	if variable.Kind != VariableNode || len(variable.Path) != 2 || variable.Path[1] != "b" || variable.Text() != "a.b" {
+		t.Errorf("Parse() variable = %+v", variable)
+	}
+	product := node.Children[1]
+	if product.Kind != InfixNode || product.Text() != "f(1, 2) * 3" {
+		t.Errorf("Parse() product = %+v", product)
+	}
+	call := product.Children[0]
+	if call.Kind != OperandNode || len(call.Children) != 2 || call.Children[1].Value != 2. {
+		t.Errorf("Parse() call = %+v", call)
+	}
+
+	node, err = Full().Parse(`(1 + 2) * 3`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !node.IsConst() || node.Value != 9. || node.Children[0].Text() != "(1 + 2)" {
+		t.Errorf("Parse() = %+v, want constant 9", node)
+	}
+
+	if _, err := Full().Parse(`1 +`); err == nil {
+		t.Errorf("Parse() expected error")
+	}
+}
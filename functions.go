@@ -3,7 +3,9 @@ package gval
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
+	"text/scanner"
 )
 
 type function func(ctx context.Context, arguments ...interface{}) (interface{}, error)
@@ -90,6 +92,116 @@ func toFunc(f interface{}) function {
 	}
 }
 
+// functionArity returns the minimal and maximal number of arguments of the function,
+// -1 as maximum of variadic functions.
+func functionArity(function interface{}) (min, max int) {
+	t := reflect.TypeOf(function)
+	if t == nil || t.Kind() != reflect.Func {
+		return 0, -1
+	}
+	return arity(t)
+}
+
+// checkArity returns an *arityError if the function is called with the wrong number of arguments.
+func checkArity(name string, min, max, arguments int) error {
+	if arguments < min || max >= 0 && arguments > max {
+		return &arityError{name: name, min: min, max: max, arguments: arguments}
+	}
+	return nil
+}
+
+// arityError reports a call of a function with the wrong number of arguments.
+type arityError struct {
+	name                string
+	min, max, arguments int
+}
+
+func (err *arityError) Error() string {
+	switch {
+	case err.min == err.max:
+		return fmt.Sprintf("%s() expects %d arguments but got %d", err.name, err.min, err.arguments)
+	case err.arguments < err.min:
+		return fmt.Sprintf("%s() expects at least %d arguments but got %d", err.name, err.min, err.arguments)
+	}
+	return fmt.Sprintf("%s() expects at most %d arguments but got %d", err.name, err.max, err.arguments)
+}
+
+// parseNamedArguments parses the arguments of a call of the function name given by position
+// or by the names of o.parameters like round(x, places: 2) following the positional arguments args.
+// It returns them in the order of the parameters with the defaults of omitted parameters.
+func (p *Parser) parseNamedArguments(c context.Context, name string, o *functionOptions, args []Evaluable) ([]Evaluable, error) {
+	named := map[string]Evaluable{}
+	if p.Scan() != ')' {
+		p.Camouflage("scan arguments", ')')
+	arguments:
+		for {
+			parameter := ""
+			if p.Scan() == scanner.Ident && p.Peek() == ':' {
+				parameter = p.TokenText()
+				p.Next()
+			} else {
+				p.Camouflage("arguments")
+			}
+			arg, err := p.ParseExpression(c)
+			if err != nil {
+				return nil, err
+			}
+			i := indexOf(o.parameters, parameter)
+			switch {
+			case parameter == "" && len(named) > 0:
+				return nil, fmt.Errorf("%s() got a positional argument after named arguments", name)
+			case parameter == "":
+				args = append(args, arg)
+			case i < 0:
+				return nil, fmt.Errorf("%s() has no parameter %s", name, parameter)
+			case i < len(args) || named[parameter] != nil:
+				return nil, fmt.Errorf("%s() got the argument %s twice", name, parameter)
+			default:
+				named[parameter] = arg
+			}
+			switch p.Scan() {
+			case ')':
+				break arguments
+			case ',':
+			default:
+				return nil, p.Expected("arguments", ')', ',')
+			}
+		}
+	}
+	return p.defaultArguments(name, o, args, named)
+}
+
+// defaultArguments appends the named arguments and the defaults of the parameters following args.
+func (p *Parser) defaultArguments(name string, o *functionOptions, args []Evaluable, named map[string]Evaluable) ([]Evaluable, error) {
+	for i := len(args); i < len(o.parameters); i++ {
+		parameter := o.parameters[i]
+		if arg, ok := named[parameter]; ok {
+			args = append(args, arg)
+			delete(named, parameter)
+			continue
+		}
+		value, ok := o.defaults[parameter]
+		if !ok {
+			if len(named) > 0 {
+				return nil, fmt.Errorf("%s() expects the argument %s", name, parameter)
+			}
+			// too few arguments are reported by the arity check
+			break
+		}
+		args = append(args, p.Const(value))
+	}
+	return args, nil
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
 func createCallArguments(ctx context.Context, t reflect.Type, args []interface{}) ([]reflect.Value, error) {
 	variadic := t.IsVariadic()
 	numIn := t.NumIn()
@@ -115,10 +227,8 @@ func createCallArguments(ctx context.Context, t reflect.Type, args []interface{}
 		} else if i == numIn-1 {
 			inType = t.In(numIn - 1).Elem()
 		}
-		argVal := reflect.ValueOf(arg)
-		if arg == nil {
-			argVal = reflect.Zero(reflect.TypeOf((*interface{})(nil)).Elem())
-		} else if !argVal.Type().AssignableTo(inType) {
+		argVal, ok := convertArgument(arg, inType)
+		if !ok {
 			return nil, fmt.Errorf("expected type %s for parameter %d but got %T",
 				inType.String(), i, arg)
 		}
@@ -126,3 +236,50 @@ func createCallArguments(ctx context.Context, t reflect.Type, args []interface{}
 	}
 	return in, nil
 }
+
+// convertArgument converts arg to the parameter type t: numbers to all number types,
+// arrays element by element and values to types of the same kind, e.g. string to a named string type.
+// nil is converted to the zero value of t.
+// Numbers with a fraction are not converted to integer types and strings are not converted to numbers.
+func convertArgument(arg interface{}, t reflect.Type) (reflect.Value, bool) {
+	if arg == nil {
+		return reflect.Zero(t), true
+	}
+	v := reflect.ValueOf(arg)
+	if v.Type().AssignableTo(t) {
+		return v, true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, ok := strictFloat(arg)
+		if !ok || f != math.Trunc(f) || f < 0 && t.Kind() >= reflect.Uint {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(f).Convert(t), true
+	case reflect.Float32, reflect.Float64:
+		f, ok := strictFloat(arg)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(f).Convert(t), true
+	case reflect.Slice:
+		elements, ok := convertToSlice(arg)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		slice := reflect.MakeSlice(t, len(elements), len(elements))
+		for i, element := range elements {
+			e, ok := convertArgument(element, t.Elem())
+			if !ok {
+				return reflect.Value{}, false
+			}
+			slice.Index(i).Set(e)
+		}
+		return slice, true
+	}
+	if v.Kind() == t.Kind() && v.Type().ConvertibleTo(t) {
+		return v.Convert(t), true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,139 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/scanner"
+	"time"
+)
+
+// Govaluate is a Language with the syntax of github.com/Knetic/govaluate to ease the migration of its expressions.
+//
+//	Operator ?: a ? b is b if bool a is true, otherwise nil
+//	Operator :: a : b is a if it is not nil, otherwise b. So a ? b : c is (a ? b) : c
+//	Operator ??: a ?? b is a if it is not nil, otherwise b
+//	Operator IN: a IN (b, c) is true iff a is an element of the array
+//	Operators AND, OR: are && and ||
+//
+// ?, : and ?? have the lowest precedence and are left associative.
+// Parentheses enclosing expressions separated by commas like (1, 2) are arrays.
+// Strings are enclosed in single or double quotes, \ escapes the following character.
+// Strings that are dates like '2014-01-02 10:00' are their unix time in seconds as float64, parsed in time.Local.
+// Parameters with names that are no identifiers are escaped by brackets like [response-time].
+// As govaluate, Govaluate has no functions. They are added by Function.
+func Govaluate() Language {
+	return govaluate
+}
+
+var govaluate = NewLanguage(arithmetic, bitmask, text, propositionalLogic,
+	PrefixExtension('(', parseGovaluateParentheses),
+	PrefixExtension('[', parseEscapedParameter),
+	PrefixExtension(scanner.String, parseGovaluateString),
+	PrefixExtension(scanner.Char, parseGovaluateString),
+
+	InfixEvalOperator("?", func(a, b Evaluable) (Evaluable, error) {
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			x, err := a(c, v)
+			if err != nil {
+				return nil, err
+			}
+			condition, ok := x.(bool)
+			if !ok {
+				return nil, fmt.Errorf("value '%v' cannot be used with the ternary operator '?', it is not a bool", x)
+			}
+			if !condition {
+				return nil, nil
+			}
+			return b(c, v)
+		}, nil
+	}),
+	InfixEvalOperator(":", coalesce),
+	InfixEvalOperator("??", coalesce),
+	Precedence("?", 0),
+	Precedence(":", 0),
+	Precedence("??", 0),
+
+	InfixContextOperator("IN", inArray),
+	Precedence("IN", 40),
+	Alias("AND", "&&"),
+	Alias("OR", "||"),
+)
+
+// coalesce returns a if it is not nil, otherwise b.
+func coalesce(a, b Evaluable) (Evaluable, error) {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		x, err := a(c, v)
+		if err != nil || x != nil {
+			return x, err
+		}
+		return b(c, v)
+	}, nil
+}
+
+// parseGovaluateParentheses parses an expression in parentheses or an array like (1, 2).
+func parseGovaluateParentheses(c context.Context, p *Parser) (Evaluable, error) {
+	evals := Evaluables{}
+	for {
+		eval, err := p.ParseExpression(c)
+		if err != nil {
+			return nil, err
+		}
+		evals = append(evals, eval)
+		switch p.Scan() {
+		case ',':
+		case ')':
+			if len(evals) == 1 {
+				p.nodes.enclose()
+				return eval, nil
+			}
+			return func(c context.Context, v interface{}) (interface{}, error) {
+				vs := make([]interface{}, len(evals))
+				for i, e := range evals {
+					x, err := e(c, v)
+					if err != nil {
+						return nil, err
+					}
+					vs[i] = x
+				}
+				return vs, nil
+			}, nil
+		default:
+			return nil, p.Expected("parentheses", ',', ')')
+		}
+	}
+}
+
+// parseEscapedParameter parses the name of a parameter like [response-time] up to the closing bracket.
+func parseEscapedParameter(c context.Context, p *Parser) (Evaluable, error) {
+	var name strings.Builder
+	for {
+		switch r := p.Next(); r {
+		case scanner.EOF:
+			return nil, p.Expected("escaped parameter", ']')
+		case ']':
+			return p.Var(p.Const(name.String())), nil
+		case '\\':
+			name.WriteRune(p.Next())
+		default:
+			name.WriteRune(r)
+		}
+	}
+}
+
+// parseGovaluateString parses a string in single or double quotes, where \ escapes the following character.
+// Dates are parsed to their unix time.
+func parseGovaluateString(c context.Context, p *Parser) (Evaluable, error) {
+	text := p.TokenText()
+	var s strings.Builder
+	for i := 1; i < len(text)-1; i++ {
+		if text[i] == '\\' && i+1 < len(text)-1 {
+			i++
+		}
+		s.WriteByte(text[i])
+	}
+	if t, err := parseDate(s.String(), time.Local); err == nil {
+		return p.Const(float64(t.Unix())), nil
+	}
+	return p.Const(s.String()), nil
+}
@@ -0,0 +1,62 @@
+package gval
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGovaluate(t *testing.T) {
+	parameter := map[string]interface{}{
+		"response-time": 120.,
+		"a]b":           1.,
+		"foo":           "bar",
+		"empty":         "",
+		"missing":       nil,
+		"x":             3.,
+		"point":         struct{ X, Y float64 }{1, 2},
+	}
+	for _, test := range []struct {
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{expression: `[response-time] > 100 && [a\]b] == 1`, want: true},
+		{expression: `'abc' + "d\"e" + 'it\'s'`, want: `abcd"eit's`},
+		{expression: `foo IN ('bar', 'baz') && !(x IN (1, 2))`, want: true},
+		{expression: `(1 + 2) * 3`, want: 9.},
+		{expression: `x > 2 ? 'big' : 'small'`, want: "big"},
+		{expression: `x > 5 ? 'big' : 'small'`, want: "small"},
+		{expression: `x > 5 ? 'big'`, want: nil},
+		{expression: `true ? missing : 'else'`, want: "else"},
+		{expression: `false ? 1 : true || false`, want: true},
+		{expression: `x ? 1 : 2`, wantErr: "value '3' cannot be used with the ternary operator '?', it is not a bool"},
+		{expression: `missing ?? empty ?? 'default'`, want: ""},
+		{expression: `x > 1 AND foo == 'bar' OR false`, want: true},
+		{expression: `'2014-01-02' > '2014-01-01 23:59:59'`, want: true},
+		{expression: `'2014-01-02'`, want: float64(time.Date(2014, 1, 2, 0, 0, 0, 0, time.Local).Unix())},
+		{expression: `foo =~ '^b' && foo !~ 'z'`, want: true},
+		{expression: `-x + 2 ** 2 + (~1 & 3) + (1 << 2) + 7 % 4`, want: 10.},
+		{expression: `point.Y - point.X`, want: 1.},
+		{expression: `strlen(foo)`, wantErr: "could not call 'strlen'"},
+		{expression: `(1, 2`, wantErr: "unexpected EOF"},
+		{expression: `[response-time`, wantErr: "while scanning escaped parameter"},
+	} {
+		got, err := Govaluate().Evaluate(test.expression, parameter)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Evaluate(%s) = %v, %v, want error %s", test.expression, got, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Evaluate(%s) = %v, %v, want %v", test.expression, got, err, test.want)
+		}
+	}
+
+	strlen := NewLanguage(Govaluate(), Function("strlen", func(s string) float64 { return float64(len(s)) }))
+	if got, err := strlen.Evaluate(`strlen(foo) + 1`, parameter); err != nil || got != 4. {
+		t.Errorf("Evaluate(strlen) = %v, %v", got, err)
+	}
+}
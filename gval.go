@@ -12,7 +12,6 @@ import (
 	"reflect"
 	"strings"
 	"text/scanner"
-	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -33,10 +32,14 @@ func EvaluateWithContext(c context.Context, expression string, parameter interfa
 
 // Full is the union of Arithmetic, Bitmask, Text, PropositionalLogic, TernaryOperator, and Json
 //
-//	Operator in: a in b is true iff value a is an element of array b
+//	Operator in: a in b is true iff value a is an element of array, Iterator or channel b
+//	Operator between: a between [min, max] is true iff min <= a <= max. It compares
+//	decimals, times, numbers and strings
 //	Operator ??: a ?? b returns a if a is not false or nil, otherwise n
+//	Operator |>: a |> f(b) calls f(a, b), see Pipeline
 //
-// Function Date: Date(a) parses string a. a must match RFC3339, ISO8601, ruby date, or unix date
+// Function Date: Date(a) parses string a. a must match RFC3339, ISO8601, ruby date, or unix date.
+// The string is parsed in the location given by WithLocation or ContextWithLocation, otherwise in time.Local.
 func Full(extensions ...Language) Language {
 	if len(extensions) == 0 {
 		return full
@@ -47,6 +50,10 @@ func Full(extensions ...Language) Language {
 // TernaryOperator contains following Operator
 //
 //	?: a ? b : c returns b if bool a is true, otherwise b
+//	?: a ?: b returns a if a is true, otherwise b
+//
+// Conditions that are not bool are true unless they are nil or the zero value of their type like 0 or "".
+// In a Strict Language such conditions fail with a TypeMismatchError instead.
 func TernaryOperator() Language {
 	return ternaryOperator
 }
@@ -83,6 +90,11 @@ func Bitmask() Language {
 
 // Text contains base, lexical order on strings (<=,<,>,>=),
 // regex match (=~) and regex not match (!~)
+// and SQL pattern match (like) and case insensitive SQL pattern match (ilike).
+// In like patterns % matches any sequence of characters and _ matches a single character.
+//
+// It contains starts with (sw), contains (co) and ends with (ew)
+// and their case insensitive variants (swi, coi, ewi) as well as case insensitive equal (eqi).
 func Text() Language {
 	return text
 }
@@ -99,6 +111,9 @@ func PropositionalLogic() Language {
 
 // JSON contains json objects ({string:expression,...})
 // and json arrays ([expression, ...])
+// as well as the functions jsonDecode(s), which parses the json string s, and jsonEncode(v), which serializes v to a json string.
+// Decoded numbers are float64, objects map[string]interface{} and arrays []interface{} like their literals.
+// The function names are variables unless they are called.
 func JSON() Language {
 	return ljson
 }
@@ -115,14 +130,17 @@ func Ident() Language {
 
 // Base contains equal (==) and not equal (!=), perentheses and general support for variables, constants and functions
 // It contains true, false, (floating point) number, string  ("" or ") and char (") constants
+// and the functions of Introspection.
 func Base() Language {
 	return base
 }
 
 // cfaOperator handles custom filtering for arrays/slices
 // Parameters: [value, operator] where operator can be "equal", "startswith", "endswith", "contains", "notequal"
+// or one of their case insensitive variants (see matchesCondition)
 // Returns: true if match found and slice was modified in-place, false if no match found
-func cfaOperator(a, b interface{}) (interface{}, error) {
+// a may be an Iterator or channel, which is consumed up to the first match and not modified
+func cfaOperator(c context.Context, a, b interface{}) (interface{}, error) {
 	// b must be []interface{} with at least 2 elements: [value, operator]
 	bSlice, ok := b.([]interface{})
 	if !ok || len(bSlice) < 2 {
@@ -146,6 +164,9 @@ func cfaOperator(a, b interface{}) (interface{}, error) {
 		}
 		
 		for i, elem := range sliceOfSlices {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
 			// Check if any element in the slice matches based on operator
 			for _, val := range elem {
 				if strVal, ok := val.(string); ok {
@@ -167,6 +188,9 @@ func cfaOperator(a, b interface{}) (interface{}, error) {
 		}
 		
 		for i, val := range slice {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
 			if strVal, ok := val.(string); ok {
 				if matchesCondition(strVal, targetValue, operator) {
 					// Swap with first element (modifies original slice in-place)
@@ -178,13 +202,22 @@ func cfaOperator(a, b interface{}) (interface{}, error) {
 		return false, nil
 	}
 
-	return false, nil
+	// Handle streams, which can not be reordered
+	found := false
+	_, err := iterate(c, a, func(val interface{}) bool {
+		strVal, ok := val.(string)
+		found = ok && matchesCondition(strVal, targetValue, operator)
+		return !found
+	})
+	return found, err
 }
 
 // cfmOperator handles custom filtering for maps
 // Parameters: [fieldname, operator, value] where operator can be "equal", "startswith", "endswith", "contains", "notequal"
+// or one of their case insensitive variants (see matchesCondition)
 // Returns: true if match found and slice was modified in-place, false if no match found
-func cfmOperator(a, b interface{}) (interface{}, error) {
+// a may be an Iterator or channel, which is consumed up to the first match and not modified
+func cfmOperator(c context.Context, a, b interface{}) (interface{}, error) {
 	// b must be []interface{} with exactly 3 elements: [fieldname, operator, value]
 	bSlice, ok := b.([]interface{})
 	if !ok || len(bSlice) < 3 {
@@ -213,6 +246,9 @@ func cfmOperator(a, b interface{}) (interface{}, error) {
 		}
 		
 		for i, m := range sliceOfMaps {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
 			if val, exists := m[fieldName]; exists {
 				if strVal, ok := val.(string); ok {
 					if matchesCondition(strVal, targetValue, operator) {
@@ -233,6 +269,9 @@ func cfmOperator(a, b interface{}) (interface{}, error) {
 		}
 		
 		for i, item := range slice {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
 			if m, ok := item.(map[string]interface{}); ok {
 				if val, exists := m[fieldName]; exists {
 					if strVal, ok := val.(string); ok {
@@ -248,7 +287,16 @@ func cfmOperator(a, b interface{}) (interface{}, error) {
 		return false, nil
 	}
 
-	return false, nil
+	// Handle streams of maps, which can not be reordered
+	found := false
+	_, err := iterate(c, a, func(item interface{}) bool {
+		if m, ok := item.(map[string]interface{}); ok {
+			strVal, ok := m[fieldName].(string)
+			found = ok && matchesCondition(strVal, targetValue, operator)
+		}
+		return !found
+	})
+	return found, err
 }
 
 // matchesCondition checks if value matches target based on the operator
@@ -264,6 +312,16 @@ func matchesCondition(value, target, operator string) bool {
 		return strings.HasSuffix(value, target)
 	case "contains", "co":
 		return strings.Contains(value, target)
+	case "equalignorecase", "eqi":
+		return strings.EqualFold(value, target)
+	case "notequalignorecase", "nei":
+		return !strings.EqualFold(value, target)
+	case "startswithignorecase", "swi":
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(target))
+	case "endswithignorecase", "ewi":
+		return strings.HasSuffix(strings.ToLower(value), strings.ToLower(target))
+	case "containsignorecase", "coi":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(target))
 	default:
 		return value == target // default to equal
 	}
@@ -271,7 +329,8 @@ func matchesCondition(value, target, operator string) bool {
 
 var full = NewLanguage(arithmetic, bitmask, text, propositionalLogic, ljson,
 
-	InfixOperator("in", inArray),
+	InfixContextOperator("in", inArray),
+	InfixOperator("between", between),
 
 	InfixShortCircuit("??", func(a interface{}) (interface{}, bool) {
 		v := reflect.ValueOf(a)
@@ -285,12 +344,14 @@ var full = NewLanguage(arithmetic, bitmask, text, propositionalLogic, ljson,
 	}),
 
 	// Custom filter operators
-	InfixOperator("cfa", cfaOperator),
-	InfixOperator("cfm", cfmOperator),
+	InfixContextOperator("cfa", cfaOperator),
+	InfixContextOperator("cfm", cfmOperator),
 
 	ternaryOperator,
 
-	Function("date", func(arguments ...interface{}) (interface{}, error) {
+	ChainedComparisons("<", "<=", ">=", ">"),
+
+	Function("date", func(c context.Context, arguments ...interface{}) (interface{}, error) {
 		if len(arguments) != 1 {
 			return nil, fmt.Errorf("date() expects exactly one string argument")
 		}
@@ -298,45 +359,28 @@ var full = NewLanguage(arithmetic, bitmask, text, propositionalLogic, ljson,
 		if !ok {
 			return nil, fmt.Errorf("date() expects exactly one string argument")
 		}
-		for _, format := range [...]string{
-			time.ANSIC,
-			time.UnixDate,
-			time.RubyDate,
-			time.Kitchen,
-			time.RFC3339,
-			time.RFC3339Nano,
-			"2006-01-02",                         // RFC 3339
-			"2006-01-02 15:04",                   // RFC 3339 with minutes
-			"2006-01-02 15:04:05",                // RFC 3339 with seconds
-			"2006-01-02 15:04:05-07:00",          // RFC 3339 with seconds and timezone
-			"2006-01-02T15Z0700",                 // ISO8601 with hour
-			"2006-01-02T15:04Z0700",              // ISO8601 with minutes
-			"2006-01-02T15:04:05Z0700",           // ISO8601 with seconds
-			"2006-01-02T15:04:05.999999999Z0700", // ISO8601 with nanoseconds
-		} {
-			ret, err := time.ParseInLocation(format, s, time.Local)
-			if err == nil {
-				return ret, nil
-			}
-		}
-		return nil, fmt.Errorf("date() could not parse %s", s)
-	}),
+		return parseDate(s, location(c))
+	}, Arity(1, 1), Validate(constantStrings), foldDate),
 )
 
-var ternaryOperator = PostfixOperator("?", parseIf)
+var ternaryOperator = NewLanguage(
+	PostfixOperator("?", parseIf),
+	PostfixOperator("?:", parseElvis),
+)
 
 var ljson = NewLanguage(
 	PrefixExtension('[', parseJSONArray),
 	PrefixExtension('{', parseJSONObject),
+	jsonCodec,
 )
 
 var arithmetic = NewLanguage(
-	InfixNumberOperator("+", func(a, b float64) (interface{}, error) { return a + b, nil }),
-	InfixNumberOperator("-", func(a, b float64) (interface{}, error) { return a - b, nil }),
-	InfixNumberOperator("*", func(a, b float64) (interface{}, error) { return a * b, nil }),
-	InfixNumberOperator("/", func(a, b float64) (interface{}, error) { return a / b, nil }),
-	InfixNumberOperator("%", func(a, b float64) (interface{}, error) { return math.Mod(a, b), nil }),
-	InfixNumberOperator("**", func(a, b float64) (interface{}, error) { return math.Pow(a, b), nil }),
+	infixFloatOperator("+", func(a, b float64) float64 { return a + b }),
+	infixFloatOperator("-", func(a, b float64) float64 { return a - b }),
+	infixFloatOperator("*", func(a, b float64) float64 { return a * b }),
+	infixFloatOperator("/", func(a, b float64) float64 { return a / b }),
+	infixFloatOperator("%", math.Mod),
+	infixFloatOperator("**", math.Pow),
 
 	InfixNumberOperator(">", func(a, b float64) (interface{}, error) { return a > b, nil }),
 	InfixNumberOperator(">=", func(a, b float64) (interface{}, error) { return a >= b, nil }),
@@ -353,8 +397,18 @@ var decimalArithmetic = NewLanguage(
 	InfixDecimalOperator("+", func(a, b decimal.Decimal) (interface{}, error) { return a.Add(b), nil }),
 	InfixDecimalOperator("-", func(a, b decimal.Decimal) (interface{}, error) { return a.Sub(b), nil }),
 	InfixDecimalOperator("*", func(a, b decimal.Decimal) (interface{}, error) { return a.Mul(b), nil }),
-	InfixDecimalOperator("/", func(a, b decimal.Decimal) (interface{}, error) { return a.Div(b), nil }),
-	InfixDecimalOperator("%", func(a, b decimal.Decimal) (interface{}, error) { return a.Mod(b), nil }),
+	InfixDecimalOperator("/", func(a, b decimal.Decimal) (interface{}, error) {
+		if b.IsZero() {
+			return nil, fmt.Errorf("invalid operation %s / %s division by zero", a, b)
+		}
+		return a.Div(b), nil
+	}),
+	InfixDecimalOperator("%", func(a, b decimal.Decimal) (interface{}, error) {
+		if b.IsZero() {
+			return nil, fmt.Errorf("invalid operation %s %% %s division by zero", a, b)
+		}
+		return a.Mod(b), nil
+	}),
 	InfixDecimalOperator("**", func(a, b decimal.Decimal) (interface{}, error) { return a.Pow(b), nil }),
 
 	InfixDecimalOperator(">", func(a, b decimal.Decimal) (interface{}, error) { return a.GreaterThan(b), nil }),
@@ -378,11 +432,11 @@ var decimalArithmetic = NewLanguage(
 )
 
 var bitmask = NewLanguage(
-	InfixNumberOperator("^", func(a, b float64) (interface{}, error) { return float64(int64(a) ^ int64(b)), nil }),
-	InfixNumberOperator("&", func(a, b float64) (interface{}, error) { return float64(int64(a) & int64(b)), nil }),
-	InfixNumberOperator("|", func(a, b float64) (interface{}, error) { return float64(int64(a) | int64(b)), nil }),
-	InfixNumberOperator("<<", func(a, b float64) (interface{}, error) { return float64(int64(a) << uint64(b)), nil }),
-	InfixNumberOperator(">>", func(a, b float64) (interface{}, error) { return float64(int64(a) >> uint64(b)), nil }),
+	infixFloatOperator("^", func(a, b float64) float64 { return float64(int64(a) ^ int64(b)) }),
+	infixFloatOperator("&", func(a, b float64) float64 { return float64(int64(a) & int64(b)) }),
+	infixFloatOperator("|", func(a, b float64) float64 { return float64(int64(a) | int64(b)) }),
+	infixFloatOperator("<<", func(a, b float64) float64 { return float64(int64(a) << uint64(b)) }),
+	infixFloatOperator(">>", func(a, b float64) float64 { return float64(int64(a) >> uint64(b)) }),
 
 	PrefixOperator("~", func(c context.Context, v interface{}) (interface{}, error) {
 		i, ok := convertToFloat(v)
@@ -404,6 +458,12 @@ var text = NewLanguage(
 	InfixTextOperator("co", containsOp),
 	InfixTextOperator("ew", endsWithOp),
 	InfixTextOperator("mw", matchOp),
+	InfixTextOperator("eqi", equalsIgnoreCaseOp),
+	InfixTextOperator("swi", startsWithIgnoreCaseOp),
+	InfixTextOperator("coi", containsIgnoreCaseOp),
+	InfixTextOperator("ewi", endsWithIgnoreCaseOp),
+	InfixTextOperator("like", likeOp),
+	InfixTextOperator("ilike", ilikeOp),
 
 	InfixEvalOperator("=~", regEx),
 	InfixEvalOperator("!~", notRegEx),
@@ -434,9 +494,14 @@ var parentheses = NewLanguage(
 	PrefixExtension('(', parseParentheses),
 )
 
-var ident = NewLanguage(
-	PrefixMetaPrefix(scanner.Ident, parseIdent),
-)
+var ident = func() Language {
+	l := NewLanguage(
+		PrefixMetaPrefix(scanner.Ident, parseIdent),
+	)
+	// fields and keys are selected from all operands like lookup(id).status
+	l.setOption(option{name: "selectors", value: true})
+	return l
+}()
 
 var base = NewLanguage(
 	PrefixExtension(scanner.Int, parseNumber),
@@ -493,10 +558,17 @@ var base = NewLanguage(
 	Precedence("=~", 40),
 	Precedence("!~", 40),
 	Precedence("in", 40),
+	Precedence("between", 40),
 	Precedence("sw", 40),
 	Precedence("co", 40),
 	Precedence("ew", 40),
 	Precedence("mw", 40),
+	Precedence("eqi", 40),
+	Precedence("swi", 40),
+	Precedence("coi", 40),
+	Precedence("ewi", 40),
+	Precedence("like", 40),
+	Precedence("ilike", 40),
 	Precedence("cfa", 40),
 	Precedence("cfm", 40),
 
@@ -516,5 +588,7 @@ var base = NewLanguage(
 
 	Precedence("**", 200),
 
+	pipeline,
 	ident,
+	introspection,
 )
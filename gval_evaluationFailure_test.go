@@ -333,8 +333,8 @@ func TestModifierTyping(test *testing.T) {
 			wantErr:    mismatchedParameters,
 		},
 		{
-			name:       "Negative Array Index",
-			expression: "foo[-1]",
+			name:       "Negative Array Index out of bound",
+			expression: "foo[-4]",
 			parameter: map[string]interface{}{
 				"foo": []int{1, 2, 3},
 			},
@@ -381,6 +381,29 @@ func TestParameterized(t *testing.T) {
 				}),
 				want: "foo",
 			},
+			{
+
+				name:       "Short-circuit elvis",
+				expression: `"foo" ?: fail()`,
+				extension: Function("fail", func(arguments ...interface{}) (interface{}, error) {
+					return nil, fmt.Errorf("Did not short-circuit")
+				}),
+				want: "foo",
+			},
+			{
+
+				name:       "Elvis with zero values",
+				expression: `name ?: count ?: "none"`,
+				parameter:  map[string]interface{}{"name": "", "count": 0.},
+				want:       "none",
+			},
+			{
+
+				name:       "Elvis precedence",
+				expression: `(count ?: 2) * 3 + (count ?: 1 + 1)`,
+				parameter:  map[string]interface{}{"count": 0.},
+				want:       8.0,
+			},
 			{
 
 				name:       "Simple parameter call",
@@ -713,6 +736,169 @@ func TestParameterized(t *testing.T) {
 				},
 				want: 2,
 			},
+			{
+				name:       "Between numbers",
+				expression: `x between [1, 10] && !(y between [1, 10])`,
+				parameter:  map[string]interface{}{"x": 10, "y": 10.5},
+				want:       true,
+			},
+			{
+				name:       "Between precedence",
+				expression: `x + 1 between [1, 10] == true`,
+				parameter:  map[string]interface{}{"x": 0},
+				want:       true,
+			},
+			{
+				name:       "Between decimals",
+				expression: `x between [1.1, 1.3]`,
+				extension:  decimalArithmetic,
+				parameter:  map[string]interface{}{"x": decimal.RequireFromString("1.3")},
+				want:       true,
+			},
+			{
+				name:       "Between strings",
+				expression: `name between ["a", "m"]`,
+				parameter:  map[string]interface{}{"name": "gval"},
+				want:       true,
+			},
+			{
+				name:       "Between times",
+				expression: `date("2024-01-01") between [start, "2024-12-31"]`,
+				parameter:  map[string]interface{}{"start": time.Date(2023, time.December, 31, 0, 0, 0, 0, time.Local)},
+				want:       true,
+			},
+			{
+				name:       "Between invalid bounds",
+				expression: `1 between [1]`,
+				wantErr:    "expected [min, max] for between operator",
+			},
+			{
+				name:       "Between mixed types",
+				expression: `x between ["a", 2]`,
+				parameter:  map[string]interface{}{"x": true},
+				wantErr:    "invalid operation (bool) between [string, float64]",
+			},
+			{
+				name:       "Like prefix",
+				expression: `name like "Prem%"`,
+				parameter:  map[string]interface{}{"name": "Premium"},
+				want:       true,
+			},
+			{
+				name:       "Like single character",
+				expression: `[code like "A_1", code like "A_"]`,
+				parameter:  map[string]interface{}{"code": "AB1"},
+				want:       []interface{}{true, false},
+			},
+			{
+				name:       "Like is case sensitive and anchored",
+				expression: `[name like "prem%", name like "rem%", name like "%ium"]`,
+				parameter:  map[string]interface{}{"name": "Premium"},
+				want:       []interface{}{false, false, true},
+			},
+			{
+				name:       "Like escapes regex characters",
+				expression: `name like "a.b%"`,
+				parameter:  map[string]interface{}{"name": "axb"},
+				want:       false,
+			},
+			{
+				name:       "Like escaped wildcard",
+				expression: `[rate like "10\\%", other like "10\\%"]`,
+				parameter:  map[string]interface{}{"rate": "10%", "other": "100"},
+				want:       []interface{}{true, false},
+			},
+			{
+				name:       "Ilike",
+				expression: `name ilike "prem%" && !(name ilike "%basic%")`,
+				parameter:  map[string]interface{}{"name": "PREMIUM"},
+				want:       true,
+			},
+			{
+				name:       "Case insensitive text operators",
+				expression: `[name eqi "premium", name swi "PREM", name coi "miu", name ewi "IUM", name eqi "basic"]`,
+				parameter:  map[string]interface{}{"name": "Premium"},
+				want:       []interface{}{true, true, true, true, false},
+			},
+			{
+				name:       "Custom filter array ignore case",
+				expression: `tags cfa ["VIP", "eqi"]`,
+				parameter:  map[string]interface{}{"tags": []interface{}{"new", "vip"}},
+				want:       true,
+			},
+			{
+				name:       "Custom filter array ignore case no match",
+				expression: `tags cfa ["VIP", "eq"]`,
+				parameter:  map[string]interface{}{"tags": []interface{}{"new", "vip"}},
+				want:       false,
+			},
+			{
+				name:       "Custom filter map ignore case",
+				expression: `[items cfm ["name", "swi", "PREM"], items cfm ["name", "containsignorecase", "XYZ"]]`,
+				parameter: map[string]interface{}{"items": []interface{}{
+					map[string]interface{}{"name": "basic"},
+					map[string]interface{}{"name": "premium"},
+				}},
+				want: []interface{}{true, false},
+			},
+			{
+				name:       "Negative array index",
+				expression: `[foo[-1], bar[-2]]`,
+				parameter: map[string]interface{}{
+					"foo": []interface{}{1, 2, 3},
+					"bar": []int{1, 2, 3},
+				},
+				want: []interface{}{3, 2},
+			},
+			{
+				name:       "Array slice",
+				expression: `[foo[1:3], foo[:2], foo[1:], foo[-2:], foo[:], foo[2:1], foo[1:10]]`,
+				parameter: map[string]interface{}{
+					"foo": []interface{}{1, 2, 3},
+				},
+				want: []interface{}{
+					[]interface{}{2, 3},
+					[]interface{}{1, 2},
+					[]interface{}{2, 3},
+					[]interface{}{2, 3},
+					[]interface{}{1, 2, 3},
+					[]interface{}{},
+					[]interface{}{2, 3},
+				},
+			},
+			{
+				name:       "Typed slice slice with selector",
+				expression: `foo.Nested.Slice[1:][0] + foo.Nested.Slice[x - 1:][1]`,
+				parameter:  map[string]interface{}{"foo": foo, "x": 1},
+				want:       4.,
+			},
+			{
+				name:       "String slice",
+				expression: `name[0:4] + name[-3:]`,
+				parameter:  map[string]interface{}{"name": "Premium"},
+				want:       "Premium",
+			},
+			{
+				name:       "Array spread",
+				expression: `[0, ...a, ...b, ...[4]]`,
+				parameter: map[string]interface{}{
+					"a": []interface{}{1, 2},
+					"b": []int{3},
+				},
+				want: []interface{}{0., 1, 2, 3, 4.},
+			},
+			{
+				name:       "Array spread of no array",
+				expression: `[...a]`,
+				parameter:  map[string]interface{}{"a": 1},
+				wantErr:    "can not spread 1 (int) expected array",
+			},
+			{
+				name:       "Slice of no array",
+				expression: `a[1:]`,
+				parameter:  map[string]interface{}{"a": true},
+				wantErr:    "can not slice true (bool)",
+			},
 		},
 		t,
 	)
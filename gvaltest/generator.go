@@ -0,0 +1,295 @@
+// Package gvaltest provides helpers to test Languages and their extensions.
+//
+// A Generator generates random expressions of a Language from its Description
+// and random parameters matching a schema, so custom operators and functions
+// can be fuzzed for panics with Generator.Check:
+//
+//	g := gvaltest.NewGenerator(gval.Full(myOperators), map[string]gval.Type{"order.total": gval.NumberType}, 1)
+//	g.Check(t, 1000)
+//
+// RunSpec runs golden tests of a Language from a spec file of expressions, parameters and expected results.
+package gvaltest
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Nandagopi/gval"
+)
+
+// Generator generates random expressions and parameters. It is not safe for concurrent use.
+type Generator struct {
+	// MaxDepth limits the nesting of operators, calls, arrays and objects in expressions.
+	MaxDepth int
+	// Rand is the source of the random choices, equal seeds generate equal expressions.
+	Rand *rand.Rand
+
+	language    gval.Language
+	schema      map[string]gval.Type
+	description gval.Description
+	// variables are the paths of the schema in order, parents before their fields.
+	variables   []string
+	prefixes    map[string]bool
+	operators   []gval.OperatorDescription
+	prefixOps   []string
+	parentheses bool
+}
+
+// NewGenerator returns a Generator for expressions of the Language with variables of the schema.
+// The schema maps dotted paths of identifiers, e.g. "order.total", to their types like for Language.TypeCheck.
+// Expressions without variables are generated if the schema is empty.
+func NewGenerator(l gval.Language, schema map[string]gval.Type, seed int64) *Generator {
+	g := &Generator{
+		MaxDepth:    4,
+		Rand:        rand.New(rand.NewSource(seed)),
+		language:    l,
+		schema:      schema,
+		description: l.Describe(),
+		prefixes:    map[string]bool{},
+	}
+	for path := range schema {
+		g.variables = append(g.variables, path)
+	}
+	sort.Strings(g.variables)
+	for _, prefix := range g.description.Prefixes {
+		g.prefixes[prefix] = true
+		if prefix != "(" && prefix != "[" && prefix != "{" {
+			g.prefixOps = append(g.prefixOps, prefix)
+		}
+	}
+	g.parentheses = g.prefixes["("]
+	for _, o := range g.description.Operators {
+		if o.Name != "|>" || len(g.description.Functions) > 0 {
+			g.operators = append(g.operators, o)
+		}
+	}
+	return g
+}
+
+// maxAttempts limits the expressions generated by Expression until one parses.
+const maxAttempts = 100
+
+// Expression returns a random expression the Language parses.
+// Calls of functions with invalid constant arguments may fail while parsing,
+// so Expression generates expressions until one parses and returns the last one
+// if none of them does. Expressions panicking while parsing, e.g. when operations on
+// constants are evaluated, are returned as well.
+func (g *Generator) Expression() string {
+	var expression string
+	for i := 0; i < maxAttempts; i++ {
+		expression = g.expression(g.MaxDepth)
+		if g.parses(expression) {
+			break
+		}
+	}
+	return expression
+}
+
+func (g *Generator) parses(expression string) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = true
+		}
+	}()
+	_, err := g.language.NewEvaluable(expression)
+	return err == nil
+}
+
+func (g *Generator) expression(depth int) string {
+	if depth <= 0 {
+		return g.leaf()
+	}
+	depth--
+	switch g.Rand.Intn(8) {
+	case 0, 1, 2:
+		if len(g.operators) > 0 {
+			return g.operator(depth)
+		}
+	case 3:
+		if len(g.prefixOps) > 0 {
+			return g.pick(g.prefixOps) + g.group(g.expression(depth))
+		}
+	case 4:
+		if len(g.description.Functions) > 0 {
+			f := g.description.Functions[g.Rand.Intn(len(g.description.Functions))]
+			return f.Name + "(" + g.arguments(depth, g.argumentCount(f, 0)) + ")"
+		}
+	case 5:
+		if g.prefixes["["] {
+			return "[" + g.arguments(depth, g.Rand.Intn(4)) + "]"
+		}
+	case 6:
+		if g.prefixes["{"] {
+			fields := make([]string, g.Rand.Intn(3))
+			for i := range fields {
+				fields[i] = strconv.Quote(g.pick(words)) + ": " + g.expression(depth)
+			}
+			return "{" + strings.Join(fields, ", ") + "}"
+		}
+	}
+	return g.leaf()
+}
+
+// operator returns an infix or postfix operation with operands of depth.
+func (g *Generator) operator(depth int) string {
+	o := g.operators[g.Rand.Intn(len(g.operators))]
+	a := g.expression(depth)
+	switch {
+	case o.Name == "?":
+		return g.group(a + " ? " + g.expression(depth) + " : " + g.expression(depth))
+	case o.Name == "|>":
+		f := g.description.Functions[g.Rand.Intn(len(g.description.Functions))]
+		return g.group(a + " |> " + f.Name + "(" + g.arguments(depth, g.argumentCount(f, 1)) + ")")
+	case o.Infix && (!o.Postfix || g.Rand.Intn(2) == 0):
+		return g.group(a + " " + o.Name + " " + g.expression(depth))
+	}
+	return g.group(a + " " + o.Name)
+}
+
+// argumentCount returns a random number of arguments of f besides the given ones.
+func (g *Generator) argumentCount(f gval.FunctionDescription, given int) int {
+	min, max := f.MinArgs-given, f.MaxArgs-given
+	if min < 0 {
+		min = 0
+	}
+	if f.MaxArgs < 0 {
+		max = min + 3
+	}
+	if max < min {
+		return min
+	}
+	return min + g.Rand.Intn(max-min+1)
+}
+
+func (g *Generator) arguments(depth, n int) string {
+	arguments := make([]string, n)
+	for i := range arguments {
+		arguments[i] = g.expression(depth)
+	}
+	return strings.Join(arguments, ", ")
+}
+
+// group encloses the expression in parentheses if the Language has them.
+func (g *Generator) group(expression string) string {
+	if !g.parentheses {
+		return expression
+	}
+	return "(" + expression + ")"
+}
+
+// leaf returns a literal, constant or variable.
+func (g *Generator) leaf() string {
+	switch g.Rand.Intn(4) {
+	case 0:
+		if len(g.variables) > 0 {
+			return g.pick(g.variables)
+		}
+	case 1:
+		if len(g.description.Constants) > 0 {
+			return g.description.Constants[g.Rand.Intn(len(g.description.Constants))].Name
+		}
+	case 2:
+		return strconv.Quote(g.pick(words))
+	}
+	return strconv.FormatFloat(numbers[g.Rand.Intn(len(numbers))], 'g', -1, 64)
+}
+
+// numbers and words are the literals and values, including edge cases like 0 and "".
+var (
+	numbers = []float64{0, 1, 2, 3, 0.5, 10, 100, 1e300, 1e-300}
+	words   = []string{"", "a", "b", "abc", "Zoë", "0", "1.5", "true", "nil", " ", "a.b", "[1]", "\\", "\""}
+)
+
+func (g *Generator) pick(values []string) string {
+	return values[g.Rand.Intn(len(values))]
+}
+
+// Parameters returns random parameters with values of the types of the schema.
+// Fields of the schema are set in objects, replacing parents of other types.
+func (g *Generator) Parameters() map[string]interface{} {
+	parameters := map[string]interface{}{}
+	for _, path := range g.variables {
+		keys := strings.Split(path, ".")
+		m := parameters
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := m[key].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				m[key] = child
+			}
+			m = child
+		}
+		m[keys[len(keys)-1]] = g.Value(g.schema[path])
+	}
+	return parameters
+}
+
+// Value returns a random value of type t.
+// Numbers are float64, arrays []interface{} and objects map[string]interface{},
+// values of AnyType are of any of these types, strings, bools or nil.
+func (g *Generator) Value(t gval.Type) interface{} {
+	switch t {
+	case gval.NumberType:
+		return numbers[g.Rand.Intn(len(numbers))]
+	case gval.StringType:
+		return g.pick(words)
+	case gval.BoolType:
+		return g.Rand.Intn(2) == 0
+	case gval.ArrayType:
+		values := make([]interface{}, g.Rand.Intn(4))
+		for i := range values {
+			values[i] = g.Value(g.anyType())
+		}
+		return values
+	case gval.ObjectType:
+		values := map[string]interface{}{}
+		for i := g.Rand.Intn(3); i > 0; i-- {
+			values[g.pick(words)] = g.Value(g.anyType())
+		}
+		return values
+	}
+	if g.Rand.Intn(6) == 0 {
+		return nil
+	}
+	return g.Value(g.anyType())
+}
+
+// valueTypes are the types of values of AnyType, scalars more often than arrays and objects.
+var valueTypes = []gval.Type{
+	gval.NumberType, gval.NumberType, gval.StringType, gval.StringType, gval.BoolType, gval.ArrayType, gval.ObjectType,
+}
+
+// anyType returns a random type other than AnyType.
+func (g *Generator) anyType() gval.Type {
+	return valueTypes[g.Rand.Intn(len(valueTypes))]
+}
+
+// Check parses and evaluates n generated expressions with generated parameters
+// and reports the expressions panicking, together with their parameters and the panic.
+// Errors are expected for random expressions and not reported.
+// Note that gval recovers panics of functions and returns them as errors.
+func (g *Generator) Check(t testing.TB, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		expression, parameters := g.Expression(), g.Parameters()
+		if recovered := g.evaluate(expression, parameters); recovered != nil {
+			t.Errorf("%s with parameters %v panics: %v", expression, parameters, recovered)
+		}
+	}
+}
+
+func (g *Generator) evaluate(expression string, parameters map[string]interface{}) (recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+	eval, err := g.language.NewEvaluable(expression)
+	if err != nil {
+		return nil
+	}
+	eval(context.Background(), parameters)
+	return nil
+}
@@ -0,0 +1,101 @@
+package gvaltest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Nandagopi/gval"
+)
+
+var schema = map[string]gval.Type{
+	"order":       gval.ObjectType,
+	"order.total": gval.NumberType,
+	"order.items": gval.ArrayType,
+	"name":        gval.StringType,
+	"active":      gval.BoolType,
+	"extra":       gval.AnyType,
+}
+
+func TestGenerator(t *testing.T) {
+	l := gval.Full(gval.Math())
+	g := NewGenerator(l, schema, 1)
+	for i := 0; i < 200; i++ {
+		expression := g.Expression()
+		if _, err := l.Parse(expression); err != nil {
+			t.Fatalf("Expression() = %s does not parse: %v", expression, err)
+		}
+		parameters := g.Parameters()
+		order, ok := parameters["order"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Parameters() = %v without order object", parameters)
+		}
+		if _, ok := order["total"].(float64); !ok {
+			t.Errorf("Parameters() = %v without order.total number", parameters)
+		}
+		if _, ok := order["items"].([]interface{}); !ok {
+			t.Errorf("Parameters() = %v without order.items array", parameters)
+		}
+		if _, ok := parameters["name"].(string); !ok {
+			t.Errorf("Parameters() = %v without name string", parameters)
+		}
+		if _, ok := parameters["active"].(bool); !ok {
+			t.Errorf("Parameters() = %v without active bool", parameters)
+		}
+		if _, ok := parameters["extra"]; !ok {
+			t.Errorf("Parameters() = %v without extra", parameters)
+		}
+	}
+
+	a, b := NewGenerator(l, schema, 7), NewGenerator(l, schema, 7)
+	for i := 0; i < 20; i++ {
+		if x, y := a.Expression(), b.Expression(); x != y {
+			t.Fatalf("Expression() of equal seeds = %s and %s", x, y)
+		}
+		if x, y := a.Parameters(), b.Parameters(); !reflect.DeepEqual(x, y) {
+			t.Fatalf("Parameters() of equal seeds = %v and %v", x, y)
+		}
+	}
+
+	shallow := NewGenerator(gval.Arithmetic(), nil, 1)
+	shallow.MaxDepth = 0
+	if expression := shallow.Expression(); strings.ContainsAny(expression, "+-*/()") {
+		t.Errorf("Expression() of MaxDepth 0 = %s", expression)
+	}
+}
+
+// recorder records the errors of Check.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheck(t *testing.T) {
+	r := &recorder{TB: t}
+	NewGenerator(gval.Full(), schema, 1).Check(r, 200)
+	if len(r.errors) > 0 {
+		t.Errorf("Check() of Full reported %v", r.errors)
+	}
+
+	fragile := gval.NewLanguage(gval.Base(), gval.InfixOperator("snip", func(a, b interface{}) (interface{}, error) {
+		if s, ok := a.(string); ok {
+			return s[:1], nil
+		}
+		return a, nil
+	}))
+	r = &recorder{TB: t}
+	NewGenerator(fragile, map[string]gval.Type{"name": gval.StringType}, 1).Check(r, 500)
+	if len(r.errors) == 0 {
+		t.Fatal("Check() did not report the panic of snip")
+	}
+	if !strings.Contains(r.errors[0], "snip") || !strings.Contains(r.errors[0], "out of range") {
+		t.Errorf("Check() reported %s", r.errors[0])
+	}
+}
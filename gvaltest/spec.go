@@ -0,0 +1,173 @@
+package gvaltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Nandagopi/gval"
+)
+
+// Case is an expression of a spec file with its parameters and expected result or error.
+//
+//	[
+//	  {"name": "discount", "expression": "order.total > 100", "parameters": {"order": {"total": 120}}, "want": true},
+//	  {"expression": "order.total >", "error": "unexpected EOF"}
+//	]
+type Case struct {
+	// Name names the subtest of the case, the expression if it is empty.
+	Name       string                 `json:"name"`
+	Expression string                 `json:"expression"`
+	Parameters map[string]interface{} `json:"parameters"`
+	// Want is the expected result, compared to the result as JSON, e.g. decimal.Decimal as string.
+	Want interface{} `json:"want"`
+	// Error is a part of the expected error message. Want is ignored if it is set.
+	Error string `json:"error"`
+}
+
+// Unmarshalers decode spec files into []Case by their extension.
+// Only JSON is supported by default, YAML specs need a YAML library, e.g.
+//
+//	gvaltest.Unmarshalers[".yaml"] = yaml.Unmarshal // gopkg.in/yaml.v3
+var Unmarshalers = map[string]func(data []byte, v interface{}) error{
+	".json": json.Unmarshal,
+}
+
+// RunSpec runs the cases of the spec file as subtests evaluating their expressions with the Language.
+// It reports the differences between the expected and actual results by their paths, e.g.
+//
+//	result.items[1].price: got 3, want 2
+func RunSpec(t *testing.T, l gval.Language, specFile string) {
+	t.Helper()
+	cases, err := ReadSpec(specFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		c := c
+		name := c.Name
+		if name == "" {
+			name = c.Expression
+		}
+		t.Run(name, func(t *testing.T) {
+			for _, d := range c.Run(l) {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+// ReadSpec reads the cases of the spec file with the Unmarshaler of its extension.
+func ReadSpec(specFile string) ([]Case, error) {
+	unmarshal, ok := Unmarshalers[strings.ToLower(filepath.Ext(specFile))]
+	if !ok {
+		return nil, fmt.Errorf("no Unmarshaler for spec file %s", specFile)
+	}
+	data, err := ioutil.ReadFile(specFile)
+	if err != nil {
+		return nil, err
+	}
+	var cases []Case
+	if err := unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("can not read spec file %s: %w", specFile, err)
+	}
+	return cases, nil
+}
+
+// Run evaluates the expression of the case with the Language and returns the differences to the expected result or error.
+func (c Case) Run(l gval.Language) []string {
+	got, err := l.Evaluate(c.Expression, c.Parameters)
+	switch {
+	case c.Error != "" && err == nil:
+		return []string{fmt.Sprintf("got %s, want error %q", js(got), c.Error)}
+	case c.Error != "" && !strings.Contains(err.Error(), c.Error):
+		return []string{fmt.Sprintf("got error %q, want error %q", err, c.Error)}
+	case c.Error != "":
+		return nil
+	case err != nil:
+		return []string{fmt.Sprintf("got error %q, want %s", err, js(c.Want))}
+	}
+	data, err := json.Marshal(got)
+	if err != nil {
+		return []string{fmt.Sprintf("got %v (%T), which is not JSON: %v", got, got, err)}
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return []string{err.Error()}
+	}
+	data, err = json.Marshal(c.Want)
+	if err != nil {
+		return []string{fmt.Sprintf("want %v (%T), which is not JSON: %v", c.Want, c.Want, err)}
+	}
+	var want interface{}
+	if err := json.Unmarshal(data, &want); err != nil {
+		return []string{err.Error()}
+	}
+	return diff("result", want, result)
+}
+
+// diff returns the differences between the decoded JSON values want and got by their path.
+func diff(path string, want, got interface{}) []string {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w)+len(g))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range g {
+			if _, ok := w[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var diffs []string
+		for _, k := range keys {
+			x, inWant := w[k]
+			y, inGot := g[k]
+			switch {
+			case !inWant:
+				diffs = append(diffs, fmt.Sprintf("%s.%s: got %s, want none", path, k, js(y)))
+			case !inGot:
+				diffs = append(diffs, fmt.Sprintf("%s.%s: got none, want %s", path, k, js(x)))
+			default:
+				diffs = append(diffs, diff(path+"."+k, x, y)...)
+			}
+		}
+		return diffs
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			break
+		}
+		var diffs []string
+		if len(w) != len(g) {
+			diffs = append(diffs, fmt.Sprintf("%s: got %d elements, want %d", path, len(g), len(w)))
+		}
+		for i := 0; i < len(w) && i < len(g); i++ {
+			diffs = append(diffs, diff(fmt.Sprintf("%s[%d]", path, i), w[i], g[i])...)
+		}
+		return diffs
+	}
+	if reflect.DeepEqual(want, got) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: got %s, want %s", path, js(got), js(want))}
+}
+
+// js returns v as JSON or formatted by fmt if it is not representable in JSON.
+func js(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,77 @@
+package gvaltest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Nandagopi/gval"
+)
+
+func TestRunSpec(t *testing.T) {
+	RunSpec(t, gval.Full(), filepath.Join("testdata", "spec.json"))
+}
+
+func TestCaseRun(t *testing.T) {
+	parameters := map[string]interface{}{"a": 1., "s": "x"}
+	for _, test := range []struct {
+		c    Case
+		want []string
+	}{
+		{Case{Expression: `a + 1`, Parameters: parameters, Want: 2}, nil},
+		{Case{Expression: `a + 1`, Parameters: parameters, Want: 3}, []string{"result: got 2, want 3"}},
+		{
+			Case{Expression: `{"x": [a, 2, 3], "y": s, "z": true}`, Parameters: parameters, Want: map[string]interface{}{
+				"x": []interface{}{1, 5}, "y": "x", "w": false,
+			}},
+			[]string{
+				"result.w: got none, want false",
+				"result.x: got 3 elements, want 2",
+				"result.x[1]: got 2, want 5",
+				"result.z: got true, want none",
+			},
+		},
+		{Case{Expression: `s`, Parameters: parameters, Want: []interface{}{"x"}}, []string{`result: got "x", want ["x"]`}},
+		{Case{Expression: `s - 1`, Parameters: parameters, Want: 1}, []string{
+			`got error "can not evaluate s - 1: evaluating s - 1 at 1:1: invalid operation (string) - (float64)", want 1`,
+		}},
+		{Case{Expression: `a +`, Error: "unexpected EOF"}, nil},
+		{Case{Expression: `a`, Parameters: parameters, Error: "unexpected EOF"}, []string{`got 1, want error "unexpected EOF"`}},
+		{Case{Expression: `s - 1`, Parameters: parameters, Error: "unknown"}, []string{
+			`got error "can not evaluate s - 1: evaluating s - 1 at 1:1: invalid operation (string) - (float64)", want error "unknown"`,
+		}},
+	} {
+		if got := test.c.Run(gval.Full()); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Run(%s) = %q, want %q", test.c.Expression, got, test.want)
+		}
+	}
+}
+
+func TestReadSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gvaltest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := ReadSpec(filepath.Join(dir, "spec.yaml")); err == nil || !strings.Contains(err.Error(), "no Unmarshaler") {
+		t.Errorf("ReadSpec() of yaml error = %v", err)
+	}
+	if _, err := ReadSpec(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("ReadSpec() of a missing file expected error")
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := ioutil.WriteFile(invalid, []byte(`{"expression": 1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSpec(invalid); err == nil || !strings.Contains(err.Error(), "can not read spec file") {
+		t.Errorf("ReadSpec() of invalid spec error = %v", err)
+	}
+	cases, err := ReadSpec(filepath.Join("testdata", "spec.json"))
+	if err != nil || len(cases) != 6 || cases[0].Name != "discount" || cases[4].Error != "unexpected EOF" {
+		t.Errorf("ReadSpec() = %+v, %v", cases, err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package httpgval serves the evaluation of gval expressions over HTTP,
+// so services written in other languages can evaluate the same rules.
+//
+//	http.Handle("/", httpgval.Handler(gval.Full(), httpgval.WithTimeout(time.Second)))
+//
+// POST /evaluate expects a JSON object with the expression and its parameters
+// and answers with the result or the error:
+//
+//	{"expression": "a.b > 5", "parameters": {"a": {"b": 7}}}
+//	{"result": true}
+//	{"error": "..."}
+//
+// Invalid requests and expressions fail with 400 Bad Request, expressions that are not allowed with 403 Forbidden,
+// failing evaluations with 422 Unprocessable Entity and evaluations exceeding the timeout with 504 Gateway Timeout.
+package httpgval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Nandagopi/gval"
+)
+
+// Option configures a Handler.
+type Option func(*handler)
+
+// WithLimits restricts the resources used by each expression, see gval.WithLimits.
+func WithLimits(limits gval.Limits) Option {
+	return func(h *handler) {
+		h.language = gval.NewLanguage(h.language, gval.WithLimits(limits))
+	}
+}
+
+// WithTimeout restricts the duration of each evaluation.
+func WithTimeout(timeout time.Duration) Option {
+	return func(h *handler) {
+		h.timeout = timeout
+	}
+}
+
+// WithMaxBodySize restricts the size of each request in bytes. The default is 1 MiB.
+func WithMaxBodySize(size int64) Option {
+	return func(h *handler) {
+		h.maxBodySize = size
+	}
+}
+
+// AllowExpressions restricts the evaluation to the given expressions.
+// Expressions are compared by their gval.Language.Canonical form, so a > 1 && b allows b && 1 < a.
+func AllowExpressions(expressions ...string) Option {
+	return func(h *handler) {
+		if h.allowed == nil {
+			h.allowed = map[string]bool{}
+		}
+		h.allowedExpressions = append(h.allowedExpressions, expressions...)
+	}
+}
+
+type handler struct {
+	language    gval.Language
+	timeout     time.Duration
+	maxBodySize int64
+	// allowed holds the canonical forms of allowedExpressions, nil if all expressions are allowed
+	allowed            map[string]bool
+	allowedExpressions []string
+}
+
+// Request is the body of POST /evaluate.
+type Request struct {
+	Expression string      `json:"expression"`
+	Parameters interface{} `json:"parameters,omitempty"`
+}
+
+// Response is the answer to POST /evaluate, either the JSON of the result or the error of the evaluation.
+type Response struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Handler returns a http.Handler evaluating expressions in the given language at POST /evaluate.
+func Handler(l gval.Language, opts ...Option) http.Handler {
+	h := &handler{language: l, maxBodySize: 1 << 20}
+	for _, opt := range opts {
+		opt(h)
+	}
+	for _, expression := range h.allowedExpressions {
+		h.allowed[h.canonical(expression)] = true
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/evaluate", h)
+	return mux
+}
+
+// canonical returns the canonical form of expression or the expression itself if it can not be parsed.
+func (h *handler) canonical(expression string) string {
+	if canonical, err := h.language.Canonical(expression); err == nil {
+		return canonical
+	}
+	return expression
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		respond(w, http.StatusMethodNotAllowed, Response{Error: "method " + r.Method + " not allowed"})
+		return
+	}
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodySize)).Decode(&req); err != nil {
+		respond(w, http.StatusBadRequest, Response{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	if h.allowed != nil && !h.allowed[h.canonical(req.Expression)] {
+		respond(w, http.StatusForbidden, Response{Error: fmt.Sprintf("expression %s is not allowed", req.Expression)})
+		return
+	}
+
+	c := r.Context()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		c, cancel = context.WithTimeout(c, h.timeout)
+		defer cancel()
+	}
+	eval, err := h.language.NewEvaluableWithContext(c, req.Expression)
+	if err != nil {
+		respond(w, http.StatusBadRequest, Response{Error: err.Error()})
+		return
+	}
+	result, err := eval(c, req.Parameters)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		respond(w, http.StatusGatewayTimeout, Response{Error: err.Error()})
+	case err != nil:
+		respond(w, http.StatusUnprocessableEntity, Response{Error: err.Error()})
+	default:
+		data, err := marshal(result)
+		if err != nil {
+			respond(w, http.StatusInternalServerError, Response{Error: fmt.Sprintf("invalid result: %v", err)})
+			return
+		}
+		respond(w, http.StatusOK, Response{Result: data})
+	}
+}
+
+func respond(w http.ResponseWriter, status int, response Response) {
+	data, _ := marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// marshal returns the JSON of v followed by a newline. Operators like && are not escaped.
+func marshal(v interface{}) ([]byte, error) {
+	b := &bytes.Buffer{}
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	err := e.Encode(v)
+	return b.Bytes(), err
+}
@@ -0,0 +1,159 @@
+package httpgval
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nandagopi/gval"
+)
+
+func TestHandler(t *testing.T) {
+	slow := gval.Function("sleep", func(c context.Context) (bool, error) {
+		select {
+		case <-c.Done():
+			return false, c.Err()
+		case <-time.After(time.Second):
+			return true, nil
+		}
+	})
+	full := Handler(gval.Full(slow), WithTimeout(10*time.Millisecond), WithLimits(gval.Limits{MaxNodes: 10}), WithMaxBodySize(200))
+	allowed := Handler(gval.Full(), AllowExpressions("a > 1 && b", "a + ", "1 < n && n < 4"))
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		method  string
+		path    string
+		body    string
+		status  int
+		want    string
+	}{
+		{
+			name:    "evaluate",
+			handler: full,
+			body:    `{"expression": "a.b > 5", "parameters": {"a": {"b": 7}}}`,
+			status:  http.StatusOK,
+			want:    `{"result":true}`,
+		},
+		{
+			name:    "false",
+			handler: full,
+			body:    `{"expression": "1 > 2"}`,
+			status:  http.StatusOK,
+			want:    `{"result":false}`,
+		},
+		{
+			name:    "parse error",
+			handler: full,
+			body:    `{"expression": "a >"}`,
+			status:  http.StatusBadRequest,
+			want:    `{"error":"parsing error: a >\t:1:4 - 1:4 unexpected EOF while scanning extensions"}`,
+		},
+		{
+			name:    "invalid request",
+			handler: full,
+			body:    `{"expression": `,
+			status:  http.StatusBadRequest,
+			want:    `{"error":"invalid request: unexpected EOF"}`,
+		},
+		{
+			name:    "body too large",
+			handler: full,
+			body:    `{"expression": "` + strings.Repeat("1", 300) + `"}`,
+			status:  http.StatusBadRequest,
+			want:    `{"error":"invalid request: http: request body too large"}`,
+		},
+		{
+			name:    "limit",
+			handler: full,
+			body:    `{"expression": "1+1+1+1+1+1+1+1+1+1+1"}`,
+			status:  http.StatusBadRequest,
+			want:    `{"error":"parsing error: 1+1+1+1+1+1+1+1+1+1+1\t:1:10 - 1:11 limit exceeded: MaxNodes is 10"}`,
+		},
+		{
+			name:    "evaluation error",
+			handler: full,
+			body:    `{"expression": "-a", "parameters": {"a": "x"}}`,
+			status:  http.StatusUnprocessableEntity,
+			want:    `{"error":"evaluating -a at 1:1: unexpected x(string) expected number"}`,
+		},
+		{
+			name:    "timeout",
+			handler: full,
+			body:    `{"expression": "sleep()"}`,
+			status:  http.StatusGatewayTimeout,
+			want:    `{"error":"evaluating sleep() at 1:1: context deadline exceeded"}`,
+		},
+		{
+			name:    "method not allowed",
+			handler: full,
+			method:  http.MethodGet,
+			status:  http.StatusMethodNotAllowed,
+			want:    `{"error":"method GET not allowed"}`,
+		},
+		{
+			name:    "not found",
+			handler: full,
+			path:    "/other",
+			status:  http.StatusNotFound,
+			want:    "404 page not found",
+		},
+		{
+			name:    "allowed",
+			handler: allowed,
+			body:    `{"expression": "b && 1 < a", "parameters": {"a": 2, "b": true}}`,
+			status:  http.StatusOK,
+			want:    `{"result":true}`,
+		},
+		{
+			name:    "allowed invalid expression",
+			handler: allowed,
+			body:    `{"expression": "a + "}`,
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:    "not allowed",
+			handler: allowed,
+			body:    `{"expression": "a > 2 && b"}`,
+			status:  http.StatusForbidden,
+			want:    `{"error":"expression a > 2 && b is not allowed"}`,
+		},
+		{
+			name:    "allowed chain",
+			handler: allowed,
+			body:    `{"expression": "1 < n < 4", "parameters": {"n": 3}}`,
+			status:  http.StatusOK,
+			want:    `{"result":true}`,
+		},
+		{
+			name:    "not allowed parenthesized comparison",
+			handler: allowed,
+			body:    `{"expression": "(1 < n) < 4", "parameters": {"n": 3}}`,
+			status:  http.StatusForbidden,
+			want:    `{"error":"expression (1 < n) < 4 is not allowed"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, path := tt.method, tt.path
+			if method == "" {
+				method = http.MethodPost
+			}
+			if path == "" {
+				path = "/evaluate"
+			}
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if got := strings.TrimSpace(w.Body.String()); tt.want != "" && got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+type caseInsensitiveKey struct{}
+
+// CaseInsensitiveIdentifiers returns a Language that selects map keys and struct fields
+// case-insensitively if the parameter contains no exact match.
+// If several keys or fields match, the lexically smallest one is selected,
+// e.g. PackageName is selected before packageName.
+func CaseInsensitiveIdentifiers() Language {
+	l := newLanguage()
+	l.setOption(option{
+		name: "caseInsensitiveIdentifiers",
+		wrap: func(eval Evaluable) Evaluable {
+			if eval.IsConst() {
+				return eval
+			}
+			return func(c context.Context, parameter interface{}) (interface{}, error) {
+				if c == nil {
+					c = context.Background()
+				}
+				return eval(context.WithValue(c, caseInsensitiveKey{}, true), parameter)
+			}
+		},
+	})
+	return l
+}
+
+// identifier returns the key of o matching key.
+// Unless case-insensitive identifiers are enabled in c, key is returned unchanged.
+func identifier(c context.Context, o interface{}, key string) string {
+	if c == nil {
+		return key
+	}
+	if enabled, _ := c.Value(caseInsensitiveKey{}).(bool); !enabled {
+		return key
+	}
+	candidates := identifiers(o)
+	match, found := "", false
+	for _, candidate := range candidates {
+		if candidate == key {
+			return key
+		}
+		if strings.EqualFold(candidate, key) && (!found || candidate < match) {
+			match, found = candidate, true
+		}
+	}
+	if !found {
+		return key
+	}
+	return match
+}
+
+// identifiers returns the string keys of maps and the field names and tag names of structs.
+func identifiers(o interface{}) []string {
+	var keys []string
+	switch o := o.(type) {
+	case map[string]interface{}:
+		for k := range o {
+			keys = append(keys, k)
+		}
+		return keys
+	case map[interface{}]interface{}:
+		for k := range o {
+			if s, ok := k.(string); ok {
+				keys = append(keys, s)
+			}
+		}
+		return keys
+	}
+	vv := resolvePotentialPointer(reflect.ValueOf(o))
+	switch vv.Kind() {
+	case reflect.Map:
+		if vv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for _, k := range vv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+	case reflect.Struct:
+		keys = fieldIdentifiers(keys, vv.Type())
+	}
+	return keys
+}
+
+func fieldIdentifiers(keys []string, t reflect.Type) []string {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			e := f.Type
+			if e.Kind() == reflect.Ptr {
+				e = e.Elem()
+			}
+			if e.Kind() == reflect.Struct {
+				keys = fieldIdentifiers(keys, e)
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		keys = append(keys, f.Name)
+		for _, tag := range []string{"gval", "json"} {
+			if name := strings.Split(f.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+				keys = append(keys, name)
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,68 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestCaseInsensitiveIdentifiers(t *testing.T) {
+	language := NewLanguage(Full(), CaseInsensitiveIdentifiers())
+	params := map[string]interface{}{
+		"PackageName": "gval",
+		"version":     map[string]interface{}{"Major": 1., "major": 2.},
+		"order":       taggedOrder{LineItems: []taggedLineItem{{Sku: "a"}}, taggedCustomer: &taggedCustomer{Name: "ann"}},
+	}
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "map key",
+				expression: `packageName`,
+				extension:  language,
+				parameter:  params,
+				want:       "gval",
+			},
+			{
+				name:       "exact match first",
+				expression: `version.major`,
+				extension:  language,
+				parameter:  params,
+				want:       2.,
+			},
+			{
+				name:       "smallest match",
+				expression: `version.MAJOR`,
+				extension:  language,
+				parameter:  params,
+				want:       1.,
+			},
+			{
+				name:       "struct field",
+				expression: `ORDER.lineitems[0].SKU`,
+				extension:  language,
+				parameter:  params,
+				want:       "a",
+			},
+			{
+				name:       "embedded struct tag",
+				expression: `order.Customer_Name`,
+				extension:  language,
+				parameter:  params,
+				want:       "ann",
+			},
+			{
+				name:       "tolerant selector",
+				expression: `packagename`,
+				extension:  NewLanguage(Full(), WithMissingFieldBehavior(ErrorOnMissingField), CaseInsensitiveIdentifiers()),
+				parameter:  params,
+				want:       "gval",
+			},
+			{
+				name:       "case sensitive by default",
+				expression: `packageName`,
+				parameter:  params,
+				want:       nil,
+			},
+		},
+		t,
+	)
+}
@@ -0,0 +1,58 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"text/scanner"
+)
+
+// Integers returns a Language parsing integer literals to int64 instead of float64
+// and integers exceeding int64 to *big.Int, so they keep their precision in results like {"id": 9007199254740993}.
+// Negation keeps int64 and *big.Int, other operators compute with float64 like for integer parameters.
+//
+//	gval.NewLanguage(gval.Full(), gval.Integers())
+func Integers() Language {
+	return integers
+}
+
+var integers = NewLanguage(
+	PrefixExtension(scanner.Int, parseInteger),
+	PrefixOperator("-", func(c context.Context, v interface{}) (interface{}, error) {
+		switch i := v.(type) {
+		case int64:
+			if i != math.MinInt64 {
+				return -i, nil
+			}
+			return new(big.Int).Neg(big.NewInt(i)), nil
+		case *big.Int:
+			n := new(big.Int).Neg(i)
+			if n.IsInt64() {
+				return n.Int64(), nil
+			}
+			return n, nil
+		}
+		f, ok := convertToFloat(v)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %v(%T) expected number", v, v)
+		}
+		return -f, nil
+	}),
+)
+
+func parseInteger(c context.Context, p *Parser) (Evaluable, error) {
+	text := p.TokenText()
+	i, err := strconv.ParseInt(text, 10, 64)
+	if err == nil {
+		return p.Const(i), nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		if b, ok := new(big.Int).SetString(text, 10); ok {
+			return p.Const(b), nil
+		}
+	}
+	return parseNumber(c, p)
+}
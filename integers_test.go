@@ -0,0 +1,52 @@
+package gval
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestIntegers(t *testing.T) {
+	l := NewLanguage(Full(), Integers())
+	huge, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	for _, tt := range []struct {
+		expression string
+		want       interface{}
+	}{
+		{"9007199254740993", int64(9007199254740993)},
+		{"-42", int64(-42)},
+		{"-9223372036854775808", int64(-9223372036854775808)},
+		{"-123456789012345678901234567890", huge},
+		{"1.5", 1.5},
+		{"1e3", 1000.},
+		{"7 / 2", 3.5},
+		{"2 * 3 == 6 && 123456789012345678901234567890 > 1", true},
+		{"x + 1", 3.},
+		{"1 in ys && x in [1, 2]", true},
+		{`2 in ys || "1" in [1] || 1 in ["1"]`, false},
+	} {
+		got, err := l.Evaluate(tt.expression, map[string]interface{}{"x": 2, "ys": []interface{}{1.}})
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", tt.expression, err)
+			continue
+		}
+		if b, ok := got.(*big.Int); ok {
+			if b.Cmp(huge) != 0 {
+				t.Errorf("Evaluate(%s) = %v, want %v", tt.expression, got, tt.want)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Evaluate(%s) = %v (%T), want %v (%T)", tt.expression, got, got, tt.want, tt.want)
+		}
+	}
+
+	got, err := l.Evaluate(`{"id": 9007199254740993, "ids": [1, 9223372036854775808]}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(got)
+	if err != nil || string(b) != `{"id":9007199254740993,"ids":[1,9223372036854775808]}` {
+		t.Errorf("json.Marshal() = %s, %v", b, err)
+	}
+}
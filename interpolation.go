@@ -0,0 +1,82 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/scanner"
+)
+
+// Interpolation returns a Language evaluating templates to strings.
+// The template is text with embedded expressions of the Full language enclosed in ${ and },
+// e.g. `Hello ${user.name}, you owe ${total * 1.19}`.
+// The values of the expressions are formatted like fmt.Sprint, nil as empty string.
+// \${ is written as ${ without starting an expression.
+// Templates have no syntax tree, so Parse and the tools based on it like Optimize fail with ErrNoSyntaxTree.
+func Interpolation() Language {
+	return interpolation
+}
+
+var interpolation = NewLanguage(full, Init(parseInterpolation))
+
+func parseInterpolation(c context.Context, p *Parser) (Evaluable, error) {
+	parts := []Evaluable{}
+	text := strings.Builder{}
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, p.Const(text.String()))
+			text.Reset()
+		}
+	}
+	for {
+		switch r := p.Next(); r {
+		case scanner.EOF:
+			flush()
+			return interpolate(p, parts), nil
+		case '\\':
+			if p.Peek() == '$' {
+				r = p.Next()
+			}
+			text.WriteRune(r)
+		case '$':
+			if p.Peek() != '{' {
+				text.WriteRune(r)
+				continue
+			}
+			p.Next()
+			flush()
+			eval, err := p.ParseExpression(c)
+			if err != nil {
+				return nil, err
+			}
+			if p.Scan() != '}' {
+				return nil, p.Expected("interpolation", '}')
+			}
+			parts = append(parts, eval)
+		default:
+			text.WriteRune(r)
+		}
+	}
+}
+
+// interpolate returns the Evaluable concatenating the formatted values of parts.
+func interpolate(p *Parser, parts []Evaluable) Evaluable {
+	eval := func(c context.Context, v interface{}) (interface{}, error) {
+		b := strings.Builder{}
+		for _, part := range parts {
+			value, err := part(c, v)
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				fmt.Fprint(&b, value)
+			}
+		}
+		return b.String(), nil
+	}
+	if !Evaluables(parts).areConst() {
+		return eval
+	}
+	s, _ := eval(context.Background(), nil)
+	return p.Const(s)
+}
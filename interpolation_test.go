@@ -0,0 +1,106 @@
+package gval
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInterpolation(t *testing.T) {
+	parameter := map[string]interface{}{
+		"user":  map[string]interface{}{"name": "Ada"},
+		"total": 100,
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "expressions",
+				expression: `Hello ${user.name}, you owe ${total * 1.19}`,
+				extension:  Interpolation(),
+				parameter:  parameter,
+				want:       "Hello Ada, you owe 119",
+			},
+			{
+				name:       "text only",
+				expression: `  it's 100% "plain" text $ `,
+				extension:  Interpolation(),
+				want:       `  it's 100% "plain" text $ `,
+			},
+			{
+				name:       "empty",
+				expression: ``,
+				extension:  Interpolation(),
+				want:       ``,
+			},
+			{
+				name:       "adjacent expressions",
+				expression: `${1}${"a"}${ {"b": 2} != nil }`,
+				extension:  Interpolation(),
+				want:       `1atrue`,
+			},
+			{
+				name:       "nested braces and nil",
+				expression: `[${user.missing}] ${total > 50 ? "high" : "low"}`,
+				extension:  Interpolation(),
+				parameter:  parameter,
+				want:       `[] high`,
+			},
+			{
+				name:       "escaped",
+				expression: `\${user.name} costs \$5 \n`,
+				extension:  Interpolation(),
+				parameter:  parameter,
+				want:       `${user.name} costs $5 \n`,
+			},
+			{
+				name:       "unclosed",
+				expression: `Hello ${user.name`,
+				extension:  Interpolation(),
+				parameter:  parameter,
+				wantErr:    "unexpected EOF while scanning interpolation",
+			},
+			{
+				name:       "evaluation error",
+				expression: `Hello ${user.name * 2}`,
+				extension:  Interpolation(),
+				parameter:  parameter,
+				wantErr:    "invalid operation",
+			},
+		},
+		t,
+	)
+}
+
+func TestInterpolationSyntaxTree(t *testing.T) {
+	l := Interpolation()
+	if _, err := l.Parse("total: ${1 + 2}"); !errors.Is(err, ErrNoSyntaxTree) {
+		t.Errorf("Parse() error = %v, want %v", err, ErrNoSyntaxTree)
+	}
+	if got, err := l.Optimize("total: ${1 + 2}"); !errors.Is(err, ErrNoSyntaxTree) {
+		t.Errorf("Optimize() = %q, %v, want %v", got, err, ErrNoSyntaxTree)
+	}
+	if got, err := PartialEvaluate("hi ${a} and ${b}", map[string]interface{}{"a": 1.}, l); !errors.Is(err, ErrNoSyntaxTree) {
+		t.Errorf("PartialEvaluate() = %q, %v, want %v", got, err, ErrNoSyntaxTree)
+	}
+	if got, err := l.Canonical("${a} ${b}"); !errors.Is(err, ErrNoSyntaxTree) {
+		t.Errorf("Canonical() = %q, %v, want %v", got, err, ErrNoSyntaxTree)
+	}
+	if got, err := l.Equal("${a}", "${a}"); !errors.Is(err, ErrNoSyntaxTree) {
+		t.Errorf("Equal() = %v, %v, want %v", got, err, ErrNoSyntaxTree)
+	}
+
+	d := l.Describe()
+	functions := map[string]FunctionDescription{}
+	for _, f := range d.Functions {
+		functions[f.Name] = f
+	}
+	if f := functions["date"]; f.MinArgs != 1 || f.MaxArgs != 1 {
+		t.Errorf("Describe() date = %+v, want 1 argument", f)
+	}
+	constants := map[string]interface{}{}
+	for _, c := range d.Constants {
+		constants[c.Name] = c.Value
+	}
+	if constants["true"] != true || constants["false"] != false {
+		t.Errorf("Describe() constants = %v, want true and false", constants)
+	}
+}
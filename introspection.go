@@ -0,0 +1,295 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/scanner"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// Introspection contains functions examining values and parameters. It is part of Base.
+//
+//	len(x)       number of characters of string x or number of elements of array or object x
+//	keys(m)      keys of object m ordered by key
+//	values(m)    values of object m ordered by key
+//	type(x)      type name of x: nil, bool, number, string, array, object, time, duration, function or unknown
+//	exists(a.b)  true iff the variable a.b can be selected from the parameter and all its keys are present in maps
+//	has(a.b)     same as exists(a.b)
+//	get(o, path) value of path like "a.b[2].c" in o or nil if it is not present
+//	get(o, path, default)
+//	             value of path in o or default if it is not present
+//
+// exists and has select the path with the VariableSelector of the Language,
+// but missing fields are not resolved by WithMissingFieldBehavior, so has(a.b) && a.b > 1
+// is false without error for a missing field even with ErrorOnMissingField.
+// get selects the path with the VariableSelector as well, so get(a, "b") is a.b with an inline default.
+// Its path is evaluated, so it can contain dynamic field names like get(fields, name + ".label").
+// A path can also be given as array of keys like get(o, ["a", "b.c"]).
+// The names are variables unless they are called, so parameters named like type are still selected.
+func Introspection() Language {
+	return introspection
+}
+
+var introspection = NewLanguage(
+	builtin(Function("len", func(x interface{}) (interface{}, error) {
+		if s, ok := x.(string); ok {
+			return float64(utf8.RuneCountInString(s)), nil
+		}
+		switch v := resolvePotentialPointer(reflect.ValueOf(x)); v.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return float64(v.Len()), nil
+		case reflect.Invalid:
+			return 0., nil
+		}
+		return nil, fmt.Errorf("len() expects a string, array or object but got %v (%T)", x, x)
+	}, Pure()), "len"),
+	builtin(Function("keys", func(m interface{}) (interface{}, error) {
+		v := resolvePotentialPointer(reflect.ValueOf(m))
+		if v.Kind() != reflect.Map {
+			return nil, fmt.Errorf("keys() expects an object but got %v (%T)", m, m)
+		}
+		keys := sortedMapKeys(v)
+		r := make([]interface{}, len(keys))
+		for i, k := range keys {
+			r[i] = k.Interface()
+		}
+		return r, nil
+	}, Pure()), "keys"),
+	builtin(Function("values", func(m interface{}) (interface{}, error) {
+		if v := resolvePotentialPointer(reflect.ValueOf(m)); v.Kind() != reflect.Map {
+			return nil, fmt.Errorf("values() expects an object but got %v (%T)", m, m)
+		}
+		values, _ := childValues(m)
+		return values, nil
+	}, Pure()), "values"),
+	builtin(Function("type", typeName, Pure()), "type"),
+	builtin(presence("exists"), "exists"),
+	builtin(presence("has"), "has"),
+	builtin(func() Language {
+		l := newLanguage()
+		l.prefixes[l.makePrefixKey("get")] = parseGet
+		return l
+	}(), "get"),
+)
+
+// presence returns a Language with the function name testing the presence of a variable path like exists(a.b).
+func presence(name string) Language {
+	l := newLanguage()
+	l.prefixes[l.makePrefixKey(name)] = func(c context.Context, p *Parser) (Evaluable, error) {
+		return parsePresence(c, p, name)
+	}
+	return l
+}
+
+// builtin returns the Language l defining the function name that is a variable unless it is called.
+func builtin(l Language, name string) Language {
+	prefix := l.prefixes[name]
+	b := newLanguage()
+	b.functions[name] = l.functions[name]
+	b.prefixes[name] = func(c context.Context, p *Parser) (Evaluable, error) {
+		if p.piped != nil {
+			return prefix(c, p)
+		}
+		_, variable, _ := parseIdent(c, p)
+		if p.Scan() != '(' {
+			p.Camouflage("variable", '(')
+			return variable()
+		}
+		p.Camouflage("function call", '(')
+		return prefix(c, p)
+	}
+	return b
+}
+
+// parsePresence parses the path of exists(path) after the name. The path is a variable like a.b[0] or a["b"].
+func parsePresence(c context.Context, p *Parser, name string) (Evaluable, error) {
+	if p.Scan() != '(' {
+		return nil, p.Expected(name, '(')
+	}
+	if p.Scan() != scanner.Ident {
+		return nil, p.Expected(name, scanner.Ident)
+	}
+	var base Evaluable
+	keys := []Evaluable{p.Const(p.TokenText())}
+	if b := p.letBinding(p.TokenText()); b != nil {
+		base, keys = b.value, nil
+	}
+	for {
+		switch p.Scan() {
+		case '.':
+			if p.Scan() != scanner.Ident {
+				return nil, p.Expected(name+" field", scanner.Ident)
+			}
+			keys = append(keys, p.Const(p.TokenText()))
+		case '[':
+			key, err := p.ParseExpression(c)
+			if err != nil {
+				return nil, err
+			}
+			if p.Scan() != ']' {
+				return nil, p.Expected(name+" key", ']')
+			}
+			keys = append(keys, key)
+		case ')':
+			return presentPath(p.vars(), base, keys), nil
+		default:
+			return nil, p.Expected(name, '.', '[', ')')
+		}
+	}
+}
+
+// parseGet parses the arguments of get(o, path, default) after the name.
+func parseGet(c context.Context, p *Parser) (Evaluable, error) {
+	if p.Scan() != '(' {
+		return nil, p.Expected("get", '(')
+	}
+	args, err := p.parseArguments(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < 2 || len(args) > 3 {
+		return nil, fmt.Errorf("get() expects 2 or 3 arguments but got %d", len(args))
+	}
+	vars := p.vars()
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		if c == nil {
+			c = context.Background()
+		}
+		o, err := args[0](c, v)
+		if err != nil {
+			return nil, err
+		}
+		path, err := args[1](c, v)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := pathKeys(path)
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := selectPresent(context.WithValue(c, presenceKey{}, true), vars, o, keys); ok {
+			return value, nil
+		}
+		if len(args) == 2 {
+			return nil, nil
+		}
+		return args[2](c, v)
+	}, nil
+}
+
+// pathKeys returns the keys of the path of get, which is an array of keys or a string like a.b[2]["c.d"].
+func pathKeys(path interface{}) ([]Evaluable, error) {
+	if path, ok := path.([]interface{}); ok {
+		keys := make([]Evaluable, len(path))
+		for i, key := range path {
+			keys[i] = constant(key)
+		}
+		return keys, nil
+	}
+	s, ok := path.(string)
+	if !ok {
+		return nil, fmt.Errorf("get() expects a path string or array but got %T", path)
+	}
+	keys := []Evaluable{}
+	for rest := s; rest != ""; {
+		var key string
+		switch {
+		case strings.HasPrefix(rest, `["`) || strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest[2:], rest[1:2]+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("get() invalid path %s", s)
+			}
+			key, rest = rest[2:2+end], rest[4+end:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("get() invalid path %s", s)
+			}
+			key, rest = rest[1:end], rest[end+1:]
+		default:
+			if len(keys) > 0 {
+				if rest[0] != '.' {
+					return nil, fmt.Errorf("get() invalid path %s", s)
+				}
+				rest = rest[1:]
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("get() invalid path %s", s)
+			}
+			key, rest = rest[:end], rest[end:]
+		}
+		keys = append(keys, constant(key))
+	}
+	return keys, nil
+}
+
+// presentPath returns if the keys can be selected from the result of base, from the parameter if base is nil.
+// Missing keys of maps are not present even if the selector yields nil for them.
+func presentPath(vars func(path ...Evaluable) Evaluable, base Evaluable, keys []Evaluable) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		if c == nil {
+			c = context.Background()
+		}
+		path := make([]Evaluable, len(keys))
+		for i, key := range keys {
+			k, err := key(c, v)
+			if err != nil {
+				return nil, err
+			}
+			path[i] = constant(k)
+		}
+		if base != nil {
+			b, err := base(c, v)
+			if err != nil {
+				return nil, err
+			}
+			v = b
+		}
+		_, ok := selectPresent(context.WithValue(c, presenceKey{}, true), vars, v, path)
+		return ok, nil
+	}
+}
+
+// presenceKey marks the context of presence checks, in which missing fields are not resolved.
+type presenceKey struct{}
+
+// checksPresence returns if c is the context of a presence check like has(a.b).
+func checksPresence(c context.Context) bool {
+	return c != nil && c.Value(presenceKey{}) == true
+}
+
+// typeName returns the name of the type of x returned by type(x).
+func typeName(x interface{}) string {
+	switch x.(type) {
+	case nil:
+		return "nil"
+	case decimal.Decimal:
+		return "number"
+	case time.Time:
+		return "time"
+	case time.Duration:
+		return "duration"
+	}
+	v := resolvePotentialPointer(reflect.ValueOf(x))
+	switch v.Kind() {
+	case reflect.Struct:
+		return "object"
+	case reflect.Func:
+		return "function"
+	case reflect.Invalid:
+		return "nil"
+	}
+	if t := typeOf(v.Type()); t != AnyType {
+		return t.String()
+	}
+	return "unknown"
+}
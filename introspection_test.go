@@ -0,0 +1,112 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestIntrospection(t *testing.T) {
+	parameter := map[string]interface{}{
+		"name":  "Zoë",
+		"tags":  []string{"a", "b"},
+		"order": map[string]interface{}{"total": 10., "id": "x", "note": nil},
+		"type":  "gold",
+		"keys":  map[string]interface{}{"a": 1.},
+		"at":    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"price": decimal.NewFromInt(3),
+		"point": struct{ X int }{1},
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "len string", expression: `len(name)`, parameter: parameter, want: 3.},
+			{name: "len array", expression: `len(tags) + len([1, 2, 3])`, parameter: parameter, want: 5.},
+			{name: "len object", expression: `len(order)`, parameter: parameter, want: 3.},
+			{name: "len nil", expression: `len(nil)`, want: 0.},
+			{name: "len number", expression: `len(1)`, wantErr: "len() expects a string, array or object but got 1 (float64)"},
+			{name: "keys", expression: `keys(order)`, parameter: parameter, want: []interface{}{"id", "note", "total"}},
+			{name: "values", expression: `values(order)`, parameter: parameter, want: []interface{}{"x", nil, 10.}},
+			{name: "values of array", expression: `values(tags)`, parameter: parameter, wantErr: "values() expects an object"},
+			{name: "type names", expression: `[type(nil), type(true), type(1), type(price), type("a"), type(tags), type(order), type(point), type(at), type(len)]`,
+				parameter: parameter, want: []interface{}{"nil", "bool", "number", "number", "string", "array", "object", "object", "time", "nil"}},
+			{name: "exists", expression: `exists(order.total) && !exists(order.discount) && exists(order.note)`, parameter: parameter, want: true},
+			{name: "exists index", expression: `[exists(tags[1]), exists(tags[2]), exists(missing)]`, parameter: parameter, want: []interface{}{true, false, false}},
+			{name: "exists let binding", expression: `let o = order; exists(o.id) && !exists(o["nope"])`, extension: Let(), parameter: parameter, want: true},
+			{name: "variables named like functions", expression: `type + keys.a`, parameter: parameter, want: "gold1"},
+			{name: "exists without path", expression: `exists(1)`, wantErr: "unexpected Int while scanning exists expected Ident"},
+		},
+		t,
+	)
+}
+
+func TestHasWithMissingFieldBehavior(t *testing.T) {
+	parameter := map[string]interface{}{
+		"information": map[string]interface{}{
+			"subscriptionDetails": map[string]interface{}{"plan": "gold"},
+			"tags":                []interface{}{"a"},
+			"user":                struct{ Name string }{"ada"},
+		},
+	}
+	resolved := 0
+	for name, l := range map[string]Language{
+		"error": NewLanguage(Full(), WithMissingFieldBehavior(ErrorOnMissingField)),
+		"false": NewLanguage(Full(), WithMissingFieldBehavior(FalseOnMissingField)),
+		"func": NewLanguage(Full(), WithMissingFieldBehavior(MissingFieldFunc(func(c context.Context, path []string) (interface{}, error) {
+			resolved++
+			return "fetched", nil
+		}))),
+	} {
+		for expression, want := range map[string]interface{}{
+			"has(information.subscriptionDetails.plan)":                                                  true,
+			"has(information.subscriptionDetails.promo)":                                                 false,
+			`has(information.subscriptionDetails.promo) && information.subscriptionDetails.promo == "x"`: false,
+			"has(information.tags[0]) && !has(information.tags[1])":                                      true,
+			"has(information.user.Name) && !has(information.user.Age)":                                   true,
+			"has(missing.field)": false,
+		} {
+			got, err := l.Evaluate(expression, parameter)
+			if err != nil || got != want {
+				t.Errorf("%s: Evaluate(%s) = %v, %v, want %v", name, expression, got, err, want)
+			}
+		}
+	}
+	if resolved != 0 {
+		t.Errorf("has() resolved %d missing fields, want 0", resolved)
+	}
+	if _, err := NewLanguage(Full(), WithMissingFieldBehavior(ErrorOnMissingField)).Evaluate("information.subscriptionDetails.promo", parameter); !errors.Is(err, ErrUnknownParameter) {
+		t.Errorf("Evaluate(information.subscriptionDetails.promo) error = %v, want ErrUnknownParameter", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	parameter := map[string]interface{}{
+		"order": map[string]interface{}{
+			"items":  []interface{}{map[string]interface{}{"sku": "a"}, map[string]interface{}{"sku": "b", "tags": []interface{}{"x", "y", "z"}}},
+			"labels": map[string]interface{}{"de.title": "Titel"},
+			"empty":  nil,
+		},
+		"field": "sku",
+		"get":   "variable",
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "path", expression: `get(order, "items[1].tags[2]")`, parameter: parameter, want: "z"},
+			{name: "dynamic field", expression: `get(order, "items[0]." + field)`, parameter: parameter, want: "a"},
+			{name: "quoted key", expression: `get(order, "labels[\"de.title\"]") + get(order, "labels['de.title']")`, parameter: parameter, want: "TitelTitel"},
+			{name: "array of keys", expression: `get(order, ["labels", "de.title"])`, parameter: parameter, want: "Titel"},
+			{name: "default", expression: `get(order, "items[0].tags[0]", "none") + get(order, "items[5]", "!")`, parameter: parameter, want: "none!"},
+			{name: "index out of range", expression: `exists(order.items[1]) && !exists(order.items[5])`, parameter: parameter, want: true},
+			{name: "missing without default", expression: `get(order, "missing.key")`, parameter: parameter, want: nil},
+			{name: "present nil", expression: `get(order, "empty", 1)`, parameter: parameter, want: nil},
+			{name: "runtime object", expression: `get({"a": [1, {"b": 2}]}, "a[1].b")`, want: 2.},
+			{name: "variables named like functions", expression: `get + "!"`, parameter: parameter, want: "variable!"},
+			{name: "invalid path", expression: `get(order, "items[0")`, parameter: parameter, wantErr: "get() invalid path items[0"},
+			{name: "invalid path type", expression: `get(order, 1)`, parameter: parameter, wantErr: "get() expects a path string or array but got float64"},
+			{name: "arguments", expression: `get(order)`, parameter: parameter, wantErr: "get() expects 2 or 3 arguments but got 1"},
+		},
+		t,
+	)
+}
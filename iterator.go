@@ -0,0 +1,64 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+)
+
+// Iterator streams the values of a collection, e.g. the rows of a database cursor.
+// Next returns false after the last value.
+//
+// The operators in, cfa and cfm and the operators and functions of Sets accept an Iterator
+// or a receive channel wherever they expect an array, so large collections need not be materialized.
+// in, cfa and cfm stop consuming the stream at the first match.
+// A stream is consumed by the evaluation, so it can be used only once.
+type Iterator interface {
+	Next() (interface{}, bool)
+}
+
+// iterate calls f with the values of the Iterator or channel o until f returns false.
+// It returns false if o is neither an Iterator nor a receive channel.
+// Receiving from a channel is canceled with the context.
+func iterate(c context.Context, o interface{}, f func(v interface{}) bool) (bool, error) {
+	if it, ok := o.(Iterator); ok {
+		for i := 0; ; i++ {
+			if err := CheckContext(c, i); err != nil {
+				return true, err
+			}
+			v, ok := it.Next()
+			if !ok || !f(v) {
+				return true, nil
+			}
+		}
+	}
+	ch := reflect.ValueOf(o)
+	if o == nil || ch.Kind() != reflect.Chan || ch.Type().ChanDir()&reflect.RecvDir == 0 {
+		return false, nil
+	}
+	cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: ch}}
+	if c != nil && c.Done() != nil {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.Done())})
+	}
+	for {
+		i, v, ok := reflect.Select(cases)
+		if i == 1 {
+			return true, c.Err()
+		}
+		if !ok || !f(v.Interface()) {
+			return true, nil
+		}
+	}
+}
+
+// collect returns the values of the Iterator or channel o as []interface{}, otherwise o.
+func collect(c context.Context, o interface{}) (interface{}, error) {
+	s := []interface{}{}
+	ok, err := iterate(c, o, func(v interface{}) bool {
+		s = append(s, v)
+		return true
+	})
+	if !ok {
+		return o, nil
+	}
+	return s, err
+}
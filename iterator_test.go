@@ -0,0 +1,113 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type sliceIterator struct {
+	values []interface{}
+	next   int
+}
+
+func (it *sliceIterator) Next() (interface{}, bool) {
+	if it.next >= len(it.values) {
+		return nil, false
+	}
+	it.next++
+	return it.values[it.next-1], true
+}
+
+func channel(values ...interface{}) <-chan interface{} {
+	ch := make(chan interface{}, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
+
+func TestIterator(t *testing.T) {
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "in iterator",
+				expression: `"b" in s`,
+				parameter:  map[string]interface{}{"s": &sliceIterator{values: []interface{}{"a", "b", "c"}}},
+				want:       true,
+			},
+			{
+				name:       "not in iterator",
+				expression: `"d" in s`,
+				parameter:  map[string]interface{}{"s": &sliceIterator{values: []interface{}{"a", "b", "c"}}},
+				want:       false,
+			},
+			{
+				name:       "in channel",
+				expression: `2 in s`,
+				parameter:  map[string]interface{}{"s": channel(1., 2., 3.)},
+				want:       true,
+			},
+			{
+				name:       "in typed channel",
+				expression: `"y" in s`,
+				parameter:  map[string]interface{}{"s": func() chan string { ch := make(chan string, 1); ch <- "y"; close(ch); return ch }()},
+				want:       true,
+			},
+			{
+				name:       "cfa iterator",
+				expression: `s cfa ["b", "sw"]`,
+				parameter:  map[string]interface{}{"s": &sliceIterator{values: []interface{}{"abc", "bcd"}}},
+				want:       true,
+			},
+			{
+				name:       "cfm channel",
+				expression: `s cfm ["name", "eq", "bob"]`,
+				parameter:  map[string]interface{}{"s": channel(map[string]interface{}{"name": "alice"}, map[string]interface{}{"name": "bob"})},
+				want:       true,
+			},
+			{
+				name:       "cfm channel without match",
+				expression: `s cfm ["name", "eq", "carol"]`,
+				parameter:  map[string]interface{}{"s": channel(map[string]interface{}{"name": "alice"})},
+				want:       false,
+			},
+			{
+				name:       "intersect iterator",
+				expression: `s intersect [2, 3, 4]`,
+				extension:  Sets(),
+				parameter:  map[string]interface{}{"s": &sliceIterator{values: []interface{}{1., 2., 3., 2.}}},
+				want:       []interface{}{2., 3.},
+			},
+			{
+				name:       "distinct channel",
+				expression: `distinct(s)`,
+				extension:  Sets(),
+				parameter:  map[string]interface{}{"s": channel("a", "b", "a")},
+				want:       []interface{}{"a", "b"},
+			},
+		},
+		t,
+	)
+}
+
+func TestIterator_consumedUpToMatch(t *testing.T) {
+	it := &sliceIterator{values: []interface{}{1., 2., 3.}}
+	got, err := Evaluate("2 in s", map[string]interface{}{"s": it})
+	if err != nil || got != true {
+		t.Fatalf("Evaluate() = %v, %v, want true", got, err)
+	}
+	if it.next != 2 {
+		t.Errorf("iterator consumed %d values, want 2", it.next)
+	}
+}
+
+func TestIterator_canceled(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := EvaluateWithContext(c, "1 in s", map[string]interface{}{"s": make(chan interface{})})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("EvaluateWithContext() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,46 @@
+package gval
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonCodec contains jsonDecode and jsonEncode, which are part of JSON.
+var jsonCodec = NewLanguage(
+	builtin(Function("jsonDecode", func(s interface{}) (interface{}, error) {
+		var data []byte
+		switch s := s.(type) {
+		case string:
+			data = []byte(s)
+		case []byte:
+			data = s
+		default:
+			return nil, fmt.Errorf("jsonDecode() expects a string but got %T", s)
+		}
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("jsonDecode() %w", err)
+		}
+		return v, nil
+	}, Pure()), "jsonDecode"),
+	builtin(Function("jsonEncode", func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("jsonEncode() %w", err)
+		}
+		return string(data), nil
+	}, Pure()), "jsonEncode"),
+)
+
+// decodeRawMessage decodes raw lazily when a key is selected from it.
+// Numbers are decoded as json.Number to keep their precision.
+func decodeRawMessage(raw json.RawMessage) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,57 @@
+package gval
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestJSONCodec(t *testing.T) {
+	parameter := map[string]interface{}{
+		"event":      map[string]interface{}{"payload": `{"user": {"id": 7, "roles": ["admin"]}}`},
+		"jsonDecode": "variable",
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "decode", expression: `jsonDecode(event.payload).user.id`, parameter: parameter, want: 7.},
+			{name: "decode array", expression: `"admin" in jsonDecode(event.payload).user.roles`, parameter: parameter, want: true},
+			{name: "decode constant", expression: `jsonDecode("[1, \"a\", null, {}]")`, want: []interface{}{1., "a", nil, map[string]interface{}{}}},
+			{name: "encode", expression: `jsonEncode({"b": [1, true], "a": "x"})`, want: `{"a":"x","b":[1,true]}`},
+			{name: "round trip", expression: `jsonDecode(jsonEncode(jsonDecode(event.payload))) == jsonDecode(event.payload)`, parameter: parameter, want: true},
+			{name: "variables named like functions", expression: `jsonDecode + "!"`, parameter: parameter, want: "variable!"},
+			{name: "invalid json", expression: `jsonDecode("{")`, wantErr: "jsonDecode() unexpected end of JSON input"},
+			{name: "invalid argument", expression: `jsonDecode(1)`, wantErr: "jsonDecode() expects a string but got float64"},
+		},
+		t,
+	)
+}
+
+func TestJSONNumber(t *testing.T) {
+	parameter := map[string]interface{}{
+		"price":   json.Number("0.1"),
+		"amount":  json.Number("12345678901234567890.123456789"),
+		"invalid": json.Number("x"),
+		"raw":     json.RawMessage(`{"user": {"id": 7, "roles": ["admin"]}, "total": 0.1}`),
+		"broken":  json.RawMessage(`{`),
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "comparison", expression: `price < 1`, parameter: parameter, want: true},
+			{name: "equality", expression: `price == 0.1`, parameter: parameter, want: true},
+			{name: "arithmetic", expression: `price * 10`, parameter: parameter, want: 1.},
+			{name: "negation", expression: `-price`, parameter: parameter, want: -0.1},
+			{name: "decimal", expression: `price + 0.2 == 0.3`, extension: DecimalArithmetic(), parameter: parameter, want: true},
+			{name: "decimal precision", expression: `amount + 0`, extension: DecimalArithmetic(), parameter: parameter, want: decimal.RequireFromString("12345678901234567890.123456789")},
+			{name: "in", expression: `price in [0.1, 0.2] && 7 in [raw.user.id] && raw.user.id in [1, 7]`, parameter: parameter, want: true},
+			{name: "not in", expression: `price in ["0.1"] || "0.1" in [price] || invalid in [0]`, parameter: parameter, want: false},
+			{name: "invalid number", expression: `invalid * 2`, parameter: parameter, wantErr: "invalid operation (json.Number) * (float64)"},
+			{name: "raw message field", expression: `raw.user.id == 7`, parameter: parameter, want: true},
+			{name: "raw message array", expression: `raw.user.roles[0]`, parameter: parameter, want: "admin"},
+			{name: "raw message number", expression: `raw.total + 0.2 == 0.3`, extension: DecimalArithmetic(), parameter: parameter, want: true},
+			{name: "raw message missing field", expression: `raw.user.name`, parameter: parameter, want: nil},
+			{name: "invalid raw message", expression: `broken.a`, parameter: parameter, wantErr: "unexpected EOF"},
+		},
+		t,
+	)
+}
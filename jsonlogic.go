@@ -0,0 +1,290 @@
+package gval
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FromJSONLogic returns an Evaluable in the Full language for given JsonLogic rule.
+//
+// Supported are the operators var (with default), ==, ===, !=, !==, <, <=, >, >= (including between),
+// !, !!, and, or, if, ?:, in, +, -, *, / and %.
+// Like in JsonLogic, and and or return their deciding operand,
+// but truthiness follows gval, e.g. an empty array is true.
+// in tests for a substring if its second argument is a string literal, otherwise for an array element.
+func FromJSONLogic(rule []byte) (Evaluable, error) {
+	var r interface{}
+	if err := json.Unmarshal(rule, &r); err != nil {
+		return nil, fmt.Errorf("invalid JsonLogic: %w", err)
+	}
+	expression, err := jsonLogicExpression(r)
+	if err != nil {
+		return nil, err
+	}
+	return Full().NewEvaluable(expression)
+}
+
+// jsonLogicExpression returns the expression in the Full language for JsonLogic rule r.
+func jsonLogicExpression(r interface{}) (string, error) {
+	switch r := r.(type) {
+	case nil:
+		return "nil", nil
+	case bool:
+		return strconv.FormatBool(r), nil
+	case float64:
+		return strconv.FormatFloat(r, 'g', -1, 64), nil
+	case string:
+		return strconv.Quote(r), nil
+	case []interface{}:
+		elements, err := jsonLogicExpressions(r)
+		if err != nil {
+			return "", err
+		}
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	case map[string]interface{}:
+		if len(r) != 1 {
+			return "", fmt.Errorf("JsonLogic operation expects exactly one operator but got %d", len(r))
+		}
+		for op, args := range r {
+			a, ok := args.([]interface{})
+			if !ok {
+				a = []interface{}{args}
+			}
+			return jsonLogicOperation(op, a)
+		}
+	}
+	return "", fmt.Errorf("unexpected JsonLogic %v(%T)", r, r)
+}
+
+func jsonLogicExpressions(rules []interface{}) ([]string, error) {
+	expressions := make([]string, len(rules))
+	for i, r := range rules {
+		e, err := jsonLogicExpression(r)
+		if err != nil {
+			return nil, err
+		}
+		expressions[i] = e
+	}
+	return expressions, nil
+}
+
+// jsonLogicOperators maps JsonLogic operators to the infix operators of Full.
+var jsonLogicOperators = map[string]string{
+	"==": "==", "===": "==", "!=": "!=", "!==": "!=",
+	">": ">", ">=": ">=", "<": "<", "<=": "<=",
+	"+": "+", "-": "-", "*": "*", "/": "/", "%": "%",
+}
+
+func jsonLogicOperation(op string, args []interface{}) (string, error) {
+	if op == "var" {
+		return jsonLogicVar(args)
+	}
+	a, err := jsonLogicExpressions(args)
+	if err != nil {
+		return "", err
+	}
+	for i := range a {
+		a[i] = "(" + a[i] + ")"
+	}
+	switch op {
+	case "!", "!!":
+		if len(a) != 1 {
+			break
+		}
+		if op == "!!" {
+			return a[0] + " ? true : false", nil
+		}
+		return a[0] + " ? false : true", nil
+	case "and", "or":
+		if len(a) == 0 {
+			break
+		}
+		// the deciding operand is the first falsy operand of and and the first truthy operand of or
+		expression := a[len(a)-1]
+		for i := len(a) - 2; i >= 0; i-- {
+			if op == "and" {
+				expression = a[i] + " ? (" + expression + ") : " + a[i]
+			} else {
+				expression = a[i] + " ? " + a[i] + " : (" + expression + ")"
+			}
+		}
+		return expression, nil
+	case "if", "?:":
+		if len(a) == 0 {
+			break
+		}
+		expression := "nil"
+		if len(a)%2 == 1 {
+			expression = a[len(a)-1]
+		}
+		for i := len(a)/2*2 - 2; i >= 0; i -= 2 {
+			expression = a[i] + " ? " + a[i+1] + " : (" + expression + ")"
+		}
+		return expression, nil
+	case "in":
+		if len(a) != 2 {
+			break
+		}
+		if _, ok := args[1].(string); ok {
+			return a[1] + " co " + a[0], nil
+		}
+		return a[0] + " in " + a[1], nil
+	case "<", "<=":
+		if len(a) == 3 {
+			return a[0] + " " + op + " " + a[1] + " && " + a[1] + " " + op + " " + a[2], nil
+		}
+	case "-":
+		if len(a) == 1 {
+			return "-" + a[0], nil
+		}
+	case "+", "*":
+		if len(a) == 1 {
+			return a[0], nil
+		}
+		if len(a) > 1 {
+			return strings.Join(a, " "+jsonLogicOperators[op]+" "), nil
+		}
+	}
+	infix, ok := jsonLogicOperators[op]
+	if !ok {
+		return "", fmt.Errorf("unsupported JsonLogic operator %s", op)
+	}
+	if len(a) != 2 {
+		return "", fmt.Errorf("JsonLogic operator %s expects 2 arguments but got %d", op, len(a))
+	}
+	return a[0] + " " + infix + " " + a[1], nil
+}
+
+func jsonLogicVar(args []interface{}) (string, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return "", fmt.Errorf("JsonLogic var expects a path and an optional default")
+	}
+	var path string
+	switch p := args[0].(type) {
+	case string:
+		path = p
+	case float64:
+		path = strconv.FormatFloat(p, 'f', -1, 64)
+	default:
+		return "", fmt.Errorf("JsonLogic var unexpected path %v(%T)", p, p)
+	}
+	if path == "" {
+		return "", fmt.Errorf("JsonLogic var of the whole data is not supported")
+	}
+	keys := strings.Split(path, ".")
+	if !isIdentifier(keys[0]) {
+		return "", fmt.Errorf("JsonLogic var %q does not start with an identifier", path)
+	}
+	b := &strings.Builder{}
+	writePath(b, keys)
+	if len(args) == 1 {
+		return b.String(), nil
+	}
+	def, err := jsonLogicExpression(args[1])
+	if err != nil {
+		return "", err
+	}
+	return "(" + b.String() + " ?? " + def + ")", nil
+}
+
+// ToJSONLogic returns the JsonLogic rule of a syntax tree created by Language.Parse.
+// It supports constants, variables, arrays, the operators !, - and ?:
+// and the infix operators ==, !=, <, <=, >, >=, &&, ||, in, +, -, *, / and %.
+func ToJSONLogic(ast *Node) ([]byte, error) {
+	if ast == nil {
+		return nil, fmt.Errorf("no syntax tree")
+	}
+	r, err := ast.jsonLogic()
+	if err != nil {
+		return nil, err
+	}
+	b := &bytes.Buffer{}
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(r); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// infixJSONLogic maps the infix operators of Full to JsonLogic operators.
+var infixJSONLogic = func() map[string]string {
+	m := map[string]string{"&&": "and", "||": "or", "in": "in"}
+	ops := make([]string, 0, len(jsonLogicOperators))
+	for op := range jsonLogicOperators {
+		ops = append(ops, op)
+	}
+	// === and !== map to the same infix operators as == and !=
+	sort.Sort(sort.Reverse(sort.StringSlice(ops)))
+	for _, op := range ops {
+		m[jsonLogicOperators[op]] = op
+	}
+	return m
+}()
+
+func (n *Node) jsonLogic() (interface{}, error) {
+	if n.constant {
+		return n.Value, nil
+	}
+	switch n.Kind {
+	case VariableNode:
+		return map[string]interface{}{"var": strings.Join(n.Path, ".")}, nil
+	case InfixNode:
+		op, ok := infixJSONLogic[n.Operator]
+		if !ok {
+			break
+		}
+		args := []interface{}{}
+		for _, child := range n.Children {
+			a, err := child.jsonLogic()
+			if err != nil {
+				return nil, err
+			}
+			// a && b && c is written as one operation
+			if nested, ok := a.(map[string]interface{}); ok && !child.parenthesized && (op == "and" || op == "or") && nested[op] != nil {
+				args = append(args, nested[op].([]interface{})...)
+				continue
+			}
+			args = append(args, a)
+		}
+		return map[string]interface{}{op: args}, nil
+	case PostfixNode:
+		if n.Operator != "?" {
+			break
+		}
+		args, err := jsonLogicChildren(n.Children)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"if": args}, nil
+	case OperandNode:
+		text := strings.TrimSpace(n.source[n.start:n.end])
+		switch {
+		case strings.HasPrefix(text, "["):
+			return jsonLogicChildren(n.Children)
+		case len(n.Children) == 1 && (strings.HasPrefix(text, "!") || strings.HasPrefix(text, "-")):
+			args, err := jsonLogicChildren(n.Children)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{text[:1]: args}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s can not be converted to JsonLogic", n.Text())
+}
+
+func jsonLogicChildren(children []*Node) ([]interface{}, error) {
+	args := make([]interface{}, len(children))
+	for i, child := range children {
+		a, err := child.jsonLogic()
+		if err != nil {
+			return nil, err
+		}
+		args[i] = a
+	}
+	return args, nil
+}
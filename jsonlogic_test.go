@@ -0,0 +1,125 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFromJSONLogic(t *testing.T) {
+	data := map[string]interface{}{
+		"a":    map[string]interface{}{"b": 3.0},
+		"list": []interface{}{"x", 2.0},
+		"temp": 25.0,
+		"name": "gval",
+	}
+	tests := []struct {
+		name    string
+		rule    string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "constant", rule: `42`, want: 42.0},
+		{name: "var", rule: `{"var": "a.b"}`, want: 3.0},
+		{name: "var sugar", rule: `{"var": ["temp"]}`, want: 25.0},
+		{name: "var index", rule: `{"var": "list.1"}`, want: 2.0},
+		{name: "var default", rule: `{"var": ["missing", 7]}`, want: 7.0},
+		{name: "equal", rule: `{"==": [{"var": "a.b"}, 3]}`, want: true},
+		{name: "strict not equal", rule: `{"!==": [1, 2]}`, want: true},
+		{name: "between", rule: `{"<": [0, {"var": "temp"}, 30]}`, want: true},
+		{name: "between exclusive", rule: `{"<=": [0, {"var": "temp"}, 20]}`, want: false},
+		{name: "not", rule: `{"!": [true]}`, want: false},
+		{name: "double not", rule: `{"!!": ["x"]}`, want: true},
+		{name: "and", rule: `{"and": [true, {">": [{"var": "temp"}, 20]}, {"var": "name"}]}`, want: "gval"},
+		{name: "or", rule: `{"or": [false, 0, "a", "b"]}`, want: "a"},
+		{name: "or false", rule: `{"or": [false, false]}`, want: false},
+		{name: "if", rule: `{"if": [{"<": [{"var": "temp"}, 0]}, "freezing", {"<": [{"var": "temp"}, 30]}, "nice", "hot"]}`, want: "nice"},
+		{name: "if without else", rule: `{"if": [false, 1]}`, want: nil},
+		{name: "in array", rule: `{"in": ["x", {"var": "list"}]}`, want: true},
+		{name: "in string", rule: `{"in": ["va", "gval"]}`, want: true},
+		{name: "arithmetic", rule: `{"-": [{"*": [2, 3, 4]}, {"/": [{"%": [7, 4]}, 3]}]}`, want: 23.0},
+		{name: "plus", rule: `{"+": [1, 2, 3]}`, want: 6.0},
+		{name: "negate", rule: `{"-": [{"var": "temp"}]}`, want: -25.0},
+		{name: "unsupported", rule: `{"map": [[1], {"var": ""}]}`, wantErr: true},
+		{name: "several operators", rule: `{"==": [1, 1], "!=": [1, 2]}`, wantErr: true},
+		{name: "invalid JSON", rule: `{"==": `, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval, err := FromJSONLogic([]byte(tt.rule))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromJSONLogic(%s) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := eval(context.Background(), data)
+			if err != nil {
+				t.Fatalf("FromJSONLogic(%s) evaluation error = %v", tt.rule, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromJSONLogic(%s) = %v, want %v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJSONLogic(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+		wantErr    bool
+	}{
+		{expression: `a.b == 3`, want: `{"==":[{"var":"a.b"},3]}`},
+		{expression: `a && b > 1 && !c`, want: `{"and":[{"var":"a"},{">":[{"var":"b"},1]},{"!":[{"var":"c"}]}]}`},
+		{expression: `a && (b || c)`, want: `{"and":[{"var":"a"},{"or":[{"var":"b"},{"var":"c"}]}]}`},
+		{expression: `x in [1, y]`, want: `{"in":[{"var":"x"},[1,{"var":"y"}]]}`},
+		{expression: `a > 1 ? "x" : -b`, want: `{"if":[{">":[{"var":"a"},1]},"x",{"-":[{"var":"b"}]}]}`},
+		{expression: `1 + 2`, want: `3`},
+		{expression: `a != nil`, want: `{"!=":[{"var":"a"},null]}`},
+		{expression: `date(a)`, wantErr: true},
+		{expression: `a =~ "x"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			node, err := Full().Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%s) error = %v", tt.expression, err)
+			}
+			got, err := ToJSONLogic(node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToJSONLogic(%s) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("ToJSONLogic(%s) = %s, want %s", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONLogicRoundTrip(t *testing.T) {
+	parameter := map[string]interface{}{"a": 2.0, "b": "x", "c": []interface{}{1.0, 2.0}}
+	for _, expression := range []string{
+		`a * 3 - 1 > 4 && b == "x"`,
+		`!(a in c) || a % 2 == 0`,
+		`a >= 2 ? (b != "y" ? 1 : 2) : 3`,
+	} {
+		node, err := Full().Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", expression, err)
+		}
+		rule, err := ToJSONLogic(node)
+		if err != nil {
+			t.Fatalf("ToJSONLogic(%s) error = %v", expression, err)
+		}
+		eval, err := FromJSONLogic(rule)
+		if err != nil {
+			t.Fatalf("FromJSONLogic(%s) error = %v", rule, err)
+		}
+		got, err := eval(context.Background(), parameter)
+		want, _ := Evaluate(expression, parameter)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip of %s via %s = %v, %v want %v", expression, rule, got, err, want)
+		}
+	}
+}
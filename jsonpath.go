@@ -0,0 +1,264 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"text/scanner"
+)
+
+// JSONPath contains base and JSONPath expressions on the parameter.
+// Combine it with Full (or another language) to use operators in filter expressions.
+//
+//	$                  the parameter (root)
+//	@                  the current element inside a filter
+//	.key or ["key"]    child of an object
+//	[n]                element n of an array, negative n counts from the end
+//	[from:to]          slice of an array
+//	[a, b]             union of keys or indices
+//	.* or [*]          all children
+//	..key or ..*       recursive descent
+//	[?(expr)]          all children for which expr is true
+//
+// Paths which only select single children return the selected value,
+// all other paths return the selected values as []interface{}.
+//
+// The language can be used as sublanguage of custom extensions via Parser.ParseSublanguage.
+func JSONPath() Language {
+	return jsonPath
+}
+
+var jsonPath = NewLanguage(
+	base,
+	PrefixExtension('$', parseRootPath),
+	PrefixExtension('@', parseCurrentPath),
+)
+
+type currentPathKey struct{}
+
+func parseRootPath(c context.Context, p *Parser) (Evaluable, error) {
+	return p.parsePath(c, func(c context.Context, v interface{}) (interface{}, error) {
+		return v, nil
+	})
+}
+
+func parseCurrentPath(c context.Context, p *Parser) (Evaluable, error) {
+	return p.parsePath(c, func(c context.Context, v interface{}) (interface{}, error) {
+		if c == nil {
+			return nil, fmt.Errorf("@ used outside of a filter")
+		}
+		current, ok := c.Value(currentPathKey{}).(*interface{})
+		if !ok {
+			return nil, fmt.Errorf("@ used outside of a filter")
+		}
+		return *current, nil
+	})
+}
+
+func (p *Parser) parsePath(c context.Context, root Evaluable) (Evaluable, error) {
+	path := root
+	multi := false
+	for {
+		switch p.Scan() {
+		case '.':
+			switch p.Scan() {
+			case scanner.Ident:
+				path = p.selectFrom(path, []Evaluable{p.Const(p.TokenText())}, multi)
+			case '*':
+				path = wildcardValue(path, multi)
+				multi = true
+			case '.':
+				switch p.Scan() {
+				case scanner.Ident:
+					path = descendValue(path, p.TokenText(), multi)
+				case '*':
+					path = descendAllValue(path, multi)
+				default:
+					return nil, p.Expected("JSONPath recursive descent", scanner.Ident, '*')
+				}
+				multi = true
+			default:
+				return nil, p.Expected("JSONPath", scanner.Ident, '*', '.')
+			}
+		case '[':
+			var err error
+			path, multi, err = p.parsePathBracket(c, path, multi)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			p.Camouflage("JSONPath", '.', '[')
+			return path, nil
+		}
+	}
+}
+
+func (p *Parser) parsePathBracket(c context.Context, path Evaluable, multi bool) (Evaluable, bool, error) {
+	switch p.Scan() {
+	case '*':
+		if p.Scan() != ']' {
+			return nil, false, p.Expected("JSONPath wildcard", ']')
+		}
+		return wildcardValue(path, multi), true, nil
+	case '?':
+		if p.Scan() != '(' {
+			return nil, false, p.Expected("JSONPath filter", '(')
+		}
+		filter, err := p.ParseExpression(c)
+		if err != nil {
+			return nil, false, err
+		}
+		if p.Scan() != ')' {
+			return nil, false, p.Expected("JSONPath filter", ')')
+		}
+		if p.Scan() != ']' {
+			return nil, false, p.Expected("JSONPath filter", ']')
+		}
+		return filterValue(path, filter, multi), true, nil
+	case ':':
+		from, to, err := p.parseSliceEnd(c, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		return sliceValue(path, from, to, multi), true, nil
+	}
+	p.Camouflage("JSONPath key", '*', '?', ':')
+
+	keys := []Evaluable{}
+	for {
+		key, err := p.ParseExpression(c)
+		if err != nil {
+			return nil, false, err
+		}
+		keys = append(keys, key)
+		switch p.Scan() {
+		case ']':
+			if len(keys) == 1 {
+				return p.selectFrom(path, keys, multi), multi, nil
+			}
+			return p.unionValue(path, keys, multi), true, nil
+		case ':':
+			if len(keys) != 1 {
+				return nil, false, p.Expected("JSONPath union", ',', ']')
+			}
+			from, to, err := p.parseSliceEnd(c, key)
+			if err != nil {
+				return nil, false, err
+			}
+			return sliceValue(path, from, to, multi), true, nil
+		case ',':
+		default:
+			return nil, false, p.Expected("JSONPath key", ',', ':', ']')
+		}
+	}
+}
+
+// unionValue returns an Evaluable selecting each of the keys from the result of e.
+// Keys which are not present are skipped.
+func (p *Parser) unionValue(e Evaluable, keys []Evaluable, multi bool) Evaluable {
+	vars := p.vars()
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		o, err := e(c, v)
+		if err != nil {
+			return nil, err
+		}
+		roots := []interface{}{o}
+		if multi {
+			roots = o.([]interface{})
+		}
+		path := make([][]Evaluable, len(keys))
+		for i, key := range keys {
+			k, err := key(c, v)
+			if err != nil {
+				return nil, err
+			}
+			path[i] = []Evaluable{constant(k)}
+		}
+		r := []interface{}{}
+		for i, root := range roots {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
+			for _, key := range path {
+				if selected, ok := selectPresent(c, vars, root, key); ok {
+					r = append(r, selected)
+				}
+			}
+		}
+		return r, nil
+	}
+}
+
+// filterValue returns an Evaluable yielding the children of the result of e for which filter is true.
+// The current child is available in filter via @. Children for which filter fails, e.g. because
+// a selected key is missing, are skipped.
+func filterValue(e, filter Evaluable, multi bool) Evaluable {
+	children := wildcardValue(e, multi)
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		o, err := children(c, v)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			c = context.Background()
+		}
+		current := new(interface{})
+		fc := context.WithValue(c, currentPathKey{}, current)
+		r := []interface{}{}
+		for i, child := range o.([]interface{}) {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
+			*current = child
+			if ok, err := filter.EvalBool(fc, v); err == nil && ok {
+				r = append(r, child)
+			}
+		}
+		return r, nil
+	}
+}
+
+// descendAllValue returns an Evaluable collecting the result of e and recursively all of its children.
+func descendAllValue(e Evaluable, multi bool) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		o, err := e(c, v)
+		if err != nil {
+			return nil, err
+		}
+		roots := []interface{}{o}
+		if multi {
+			roots = o.([]interface{})
+		}
+		r := []interface{}{}
+		ancestors := map[containerKey]struct{}{}
+		for _, root := range roots {
+			if r, err = collectAll(c, ancestors, r, root); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+}
+
+// collectAll appends the children of o and recursively their children to collected.
+// Children that are ancestors of o are appended, but not descended into again.
+func collectAll(c context.Context, ancestors map[containerKey]struct{}, collected []interface{}, o interface{}) ([]interface{}, error) {
+	if container, ok := containerOf(o); ok {
+		if _, cycle := ancestors[container]; cycle {
+			return collected, nil
+		}
+		ancestors[container] = struct{}{}
+		defer delete(ancestors, container)
+	}
+	children, _ := childValues(o)
+	var err error
+	for _, child := range children {
+		collected = append(collected, child)
+		if err := CheckContext(c, len(collected)); err != nil {
+			return nil, err
+		}
+		if collected, err = collectAll(c, ancestors, collected, child); err != nil {
+			return nil, err
+		}
+	}
+	return collected, nil
+}
@@ -0,0 +1,141 @@
+package gval
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	store := map[string]interface{}{
+		"store": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"title": "Sayings", "price": 8.95, "author": "Rees"},
+				map[string]interface{}{"title": "Sword", "price": 12.99, "author": "Waugh"},
+				map[string]interface{}{"title": "Moby Dick", "price": 8.99, "isbn": "0-553"},
+			},
+			"bicycle": map[string]interface{}{"color": "red", "price": 19.95},
+		},
+		"max": 9.,
+	}
+	jp := JSONPath()
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "root",
+				expression: `$.store.bicycle.color`,
+				extension:  jp,
+				parameter:  store,
+				want:       "red",
+			},
+			{
+				name:       "bracket key and index",
+				expression: `$["store"]["book"][-1].title`,
+				extension:  jp,
+				parameter:  store,
+				want:       "Moby Dick",
+			},
+			{
+				name:       "wildcard",
+				expression: `$.store.book[*].author`,
+				extension:  jp,
+				parameter:  store,
+				want:       []interface{}{"Rees", "Waugh"},
+			},
+			{
+				name:       "dot wildcard",
+				expression: `$.store.bicycle.*`,
+				extension:  jp,
+				parameter:  store,
+				want:       []interface{}{"red", 19.95},
+			},
+			{
+				name:       "recursive descent",
+				expression: `$..price`,
+				extension:  jp,
+				parameter:  store,
+				want:       []interface{}{19.95, 8.95, 12.99, 8.99},
+			},
+			{
+				name:       "slice",
+				expression: `$.store.book[:2].title`,
+				extension:  jp,
+				parameter:  store,
+				want:       []interface{}{"Sayings", "Sword"},
+			},
+			{
+				name:       "union",
+				expression: `$.store.book[0, 2].title`,
+				extension:  jp,
+				parameter:  store,
+				want:       []interface{}{"Sayings", "Moby Dick"},
+			},
+			{
+				name:       "filter",
+				expression: `$.store.book[?(@.price < 10)].title`,
+				extension:  jp,
+				parameter:  store,
+				want:       []interface{}{"Sayings", "Moby Dick"},
+			},
+			{
+				name:       "filter with root and missing keys",
+				expression: `$..book[?(@.isbn == "0-553" || @.price > $.max)].title`,
+				extension:  jp,
+				parameter:  store,
+				want:       []interface{}{"Sword", "Moby Dick"},
+			},
+			{
+				name:       "path in expression",
+				expression: `len($.store.book[?(@.price < max)]) == 2`,
+				extension:  NewLanguage(jp, Function("len", func(a []interface{}) int { return len(a) })),
+				parameter:  store,
+				want:       true,
+			},
+			{
+				name:       "current outside of filter",
+				expression: `@.price`,
+				extension:  jp,
+				parameter:  store,
+				wantErr:    "@ used outside of a filter",
+			},
+			{
+				name:       "invalid path",
+				expression: `$.[0]`,
+				extension:  jp,
+				parameter:  store,
+				wantErr:    "while scanning JSONPath",
+			},
+		},
+		t,
+	)
+
+	t.Run("sublanguage", func(t *testing.T) {
+		lang := NewLanguage(Full(),
+			PrefixExtension('#', func(c context.Context, p *Parser) (Evaluable, error) {
+				return p.ParseSublanguage(c, NewLanguage(Full(), JSONPath()))
+			}),
+		)
+		got, err := lang.Evaluate(`#$.store.bicycle.price > 10`, store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != true {
+			t.Errorf("Evaluate() = %v, want true", got)
+		}
+	})
+}
+
+func TestJSONPathCycle(t *testing.T) {
+	node := map[string]interface{}{"id": 1.}
+	node["self"] = node
+	got, err := NewLanguage(Full(), JSONPath()).Evaluate(`$..id`, map[string]interface{}{"node": node})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids, ok := got.([]interface{}); !ok || len(ids) != 1 || ids[0] != 1. {
+		t.Errorf("Evaluate($..id) = %d values, want [1]", len(ids))
+	}
+	if got, err := NewLanguage(Full(), JSONPath()).Evaluate(`len($..*)`, map[string]interface{}{"node": node}); err != nil || got != 3. {
+		t.Errorf("Evaluate(len($..*)) = %v, %v, want 3", got, err)
+	}
+}
@@ -0,0 +1,204 @@
+package gval
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// JSONSchema is a JSON Schema describing the parameter of expressions.
+// Supported are the keywords type, properties, additionalProperties, items and description.
+// References like $ref and combinations like anyOf are not resolved, their values are of AnyType.
+type JSONSchema struct {
+	// Type is a type name like "number" or a list of type names like ["string", "null"].
+	Type interface{} `json:"type,omitempty"`
+	// Properties are the schemas of the fields of objects.
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	// AdditionalProperties false closes objects, so fields not listed in Properties are unknown.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	// Items is the schema of the elements of arrays.
+	Items       *JSONSchema `json:"items,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// ParseJSONSchema parses a JSON Schema describing the parameter of expressions.
+func ParseJSONSchema(data []byte) (*JSONSchema, error) {
+	schema := &JSONSchema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+	return schema, nil
+}
+
+// jsonSchemaTypes maps the type names of JSON Schema to Types.
+var jsonSchemaTypes = map[string]Type{
+	"number":  NumberType,
+	"integer": NumberType,
+	"string":  StringType,
+	"boolean": BoolType,
+	"array":   ArrayType,
+	"object":  ObjectType,
+}
+
+// ValueType returns the Type of values described by the schema,
+// AnyType if the schema allows values of several types.
+func (s *JSONSchema) ValueType() Type {
+	switch t := s.Type.(type) {
+	case string:
+		return jsonSchemaTypes[t]
+	case []interface{}:
+		// ["string", "null"] is a nullable string
+		found := AnyType
+		for _, name := range t {
+			if name == "null" {
+				continue
+			}
+			name, _ := name.(string)
+			if found != AnyType {
+				return AnyType
+			}
+			found = jsonSchemaTypes[name]
+		}
+		return found
+	}
+	if s.Properties != nil {
+		return ObjectType
+	}
+	return AnyType
+}
+
+// closed returns if the object has no fields but its Properties.
+func (s *JSONSchema) closed() bool {
+	return s.AdditionalProperties == false
+}
+
+// Types returns the schema of TypeCheck, the types of the properties by their dotted paths.
+func (s *JSONSchema) Types() map[string]Type {
+	types := map[string]Type{}
+	s.walk("", func(path string, property *JSONSchema) {
+		types[path] = property.ValueType()
+	})
+	return types
+}
+
+// walk calls f with the dotted paths and schemas of all properties of the object described by s.
+func (s *JSONSchema) walk(prefix string, f func(path string, property *JSONSchema)) {
+	for name, property := range s.Properties {
+		if property == nil {
+			property = &JSONSchema{}
+		}
+		f(prefix+name, property)
+		property.walk(prefix+name+".", f)
+	}
+}
+
+// CheckJSONSchema reports type mismatches of the expression like TypeCheck with the types given by the schema.
+// Additionally fields missing in objects closed by "additionalProperties": false are reported as unknown.
+// Elements of arrays are not checked.
+func (l Language) CheckJSONSchema(expression string, schema *JSONSchema) error {
+	node, err := l.Parse(expression)
+	if err != nil {
+		return err
+	}
+	tc := &typeChecker{language: l, schema: schema.Types(), expression: expression, closed: map[string]bool{}}
+	schema.walk("", func(path string, property *JSONSchema) {
+		tc.closed[path] = property.closed()
+	})
+	tc.check(node)
+	if len(tc.errs) > 0 {
+		return tc.errs
+	}
+	return nil
+}
+
+// Completion is a field or function completing an identifier of an expression.
+type Completion struct {
+	// Text is the name of the field or function
+	Text string
+	// Function is true for functions
+	Function bool
+	// Type is the type of fields
+	Type Type
+	// Description is the description of fields given by the schema
+	Description string
+}
+
+// completionPath matches the path of variable at the end of an expression,
+// e.g. order.items[0].pr with the field name pr being completed.
+var completionPath = regexp.MustCompile(`(?:[\pL_][\pL\pN_]*(?:\[[^\[\]]*\])*\.)*([\pL_][\pL\pN_]*)?$`)
+
+// CompletionsAt returns the fields and functions completing the identifier ending at the byte offset of the expression,
+// e.g. the fields of order starting with t for order.t.
+// Fields are given by the schema and listed before the functions of the Language, both sorted by name.
+// Namespaces of functions defined by Functions are completed like functions with Function false.
+// After an index like items[0]. the fields of the elements of the array are completed.
+func (l Language) CompletionsAt(expression string, offset int, schema *JSONSchema) []Completion {
+	if offset < 0 || offset > len(expression) {
+		return nil
+	}
+	before := expression[:offset]
+	match := completionPath.FindStringSubmatchIndex(before)
+	if match[0] > 0 && strings.ContainsRune(".)]\"'`0123456789", rune(before[match[0]-1])) {
+		// fields of expressions and text in strings are not completed
+		return nil
+	}
+	path, partial := before[match[0]:], ""
+	if match[2] >= 0 {
+		path, partial = before[match[0]:match[2]], before[match[2]:match[3]]
+	}
+
+	completions := []Completion{}
+	if object := schema.property(path); object != nil {
+		for name, property := range object.Properties {
+			if strings.HasPrefix(name, partial) {
+				if property == nil {
+					property = &JSONSchema{}
+				}
+				completions = append(completions, Completion{Text: name, Type: property.ValueType(), Description: property.Description})
+			}
+		}
+		sort.Slice(completions, func(i, j int) bool { return completions[i].Text < completions[j].Text })
+	}
+	// functions in the namespace path, or namespaces in it like math of math.round
+	functions := []Completion{}
+	found := map[string]bool{}
+	for name := range l.functions {
+		if !strings.HasPrefix(name, path+partial) {
+			continue
+		}
+		text, function := name[len(path):], true
+		if i := strings.IndexByte(text, '.'); i >= 0 {
+			text, function = text[:i], false
+		}
+		if !found[text] {
+			found[text] = true
+			functions = append(functions, Completion{Text: text, Function: function})
+		}
+	}
+	sort.Slice(functions, func(i, j int) bool { return functions[i].Text < functions[j].Text })
+	return append(completions, functions...)
+}
+
+// property returns the schema of the property with the path like order.items[0]. or nil if it is unknown.
+func (s *JSONSchema) property(path string) *JSONSchema {
+	if path == "" {
+		return s
+	}
+	object := s
+	for _, key := range strings.Split(strings.TrimSuffix(path, "."), ".") {
+		name := key
+		if i := strings.IndexByte(key, '['); i >= 0 {
+			name = key[:i]
+		}
+		object = object.Properties[name]
+		for ; object != nil && strings.Contains(key, "["); key = key[strings.IndexByte(key, ']')+1:] {
+			object = object.Items
+		}
+		if object == nil {
+			return nil
+		}
+	}
+	return object
+}
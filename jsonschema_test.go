@@ -0,0 +1,112 @@
+package gval
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testJSONSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "description": "name of the customer"},
+		"age": {"type": "integer"},
+		"nickname": {"type": ["string", "null"]},
+		"order": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"total": {"type": "number"},
+				"items": {
+					"type": "array",
+					"items": {"type": "object", "properties": {"price": {"type": "number"}, "product": {"type": "string"}}}
+				}
+			}
+		}
+	}
+}`
+
+func TestJSONSchema_Types(t *testing.T) {
+	schema, err := ParseJSONSchema([]byte(testJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Type{
+		"name":        StringType,
+		"age":         NumberType,
+		"nickname":    StringType,
+		"order":       ObjectType,
+		"order.total": NumberType,
+		"order.items": ArrayType,
+	}
+	if got := schema.Types(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Types() = %v, want %v", got, want)
+	}
+	if _, err := ParseJSONSchema([]byte(`{"type": `)); err == nil {
+		t.Error("ParseJSONSchema() expected error")
+	}
+}
+
+func TestLanguage_CheckJSONSchema(t *testing.T) {
+	schema, err := ParseJSONSchema([]byte(testJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := Full()
+	for _, expression := range []string{
+		`name == "x" && age >= 18`,
+		"order.total > 100",
+		"order.items[0].price > 1",
+		`(nickname ?? name) == "x"`,
+	} {
+		if err := l.CheckJSONSchema(expression, schema); err != nil {
+			t.Errorf("CheckJSONSchema(%s) = %v", expression, err)
+		}
+	}
+	for expression, want := range map[string]string{
+		"order.count > 1": "1:1: unknown field count of order",
+		"nam == 1":        "1:1: unknown variable nam",
+		`age == "18"`:     "1:1: comparing number to string",
+	} {
+		if err := l.CheckJSONSchema(expression, schema); err == nil || err.Error() != want {
+			t.Errorf("CheckJSONSchema(%s) = %v, want %s", expression, err, want)
+		}
+	}
+}
+
+func TestLanguage_CompletionsAt(t *testing.T) {
+	schema, err := ParseJSONSchema([]byte(testJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLanguage(Full(), Function("nameOf", func(x interface{}) string { return "" }),
+		Functions("str", map[string]interface{}{"upper": strings.ToUpper, "lower": strings.ToLower}))
+	texts := func(completions []Completion) []string {
+		s := []string{}
+		for _, c := range completions {
+			s = append(s, c.Text)
+		}
+		return s
+	}
+	for expression, want := range map[string][]string{
+		"str.":                  {"lower", "upper"},
+		"s":                     {"str"},
+		"na":                    {"name", "nameOf"},
+		"age > 1 && order.":     {"items", "total"},
+		"order.t":               {"total"},
+		"order.items[0].p":      {"price", "product"},
+		"order.items[i + 1].pr": {"price", "product"},
+		"order.total.":          {},
+		"unknown.":              {},
+		`"na`:                   {},
+		"(order).t":             {},
+		"date(order.items[0].p": {"price", "product"},
+	} {
+		if got := texts(l.CompletionsAt(expression, len(expression), schema)); !reflect.DeepEqual(got, want) {
+			t.Errorf("CompletionsAt(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	if got := l.CompletionsAt("name > 1", 2, schema); len(got) != 2 || got[0] != (Completion{Text: "name", Type: StringType, Description: "name of the customer"}) || !got[1].Function {
+		t.Errorf("CompletionsAt(name > 1, 2) = %v", got)
+	}
+}
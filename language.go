@@ -3,7 +3,7 @@ package gval
 import (
 	"context"
 	"fmt"
-	"text/scanner"
+	"reflect"
 	"unicode"
 
 	"github.com/shopspring/decimal"
@@ -17,6 +17,35 @@ type Language struct {
 	init            extension
 	def             extension
 	selector        func(Evaluables) Evaluable
+	options         []option
+	// functions holds the types of the functions defined by Function
+	functions map[string]reflect.Type
+	// prefixOperators holds the prefixes defined by PrefixOperatorWithPrecedence
+	prefixOperators map[interface{}]prefixOperator
+	// aliases maps the names defined by Alias to their targets
+	aliases map[string]string
+}
+
+// option is a named setting of a Language.
+// Merging Languages replaces an option with the same name, so the last given option wins.
+type option struct {
+	name string
+	// wrap is applied to every Evaluable parsed by the Language
+	wrap func(Evaluable) Evaluable
+	// wrapParsed is applied like wrap, given the expression and its number of operands and operators
+	wrapParsed func(eval Evaluable, expression string, nodes int) Evaluable
+	// value is a setting used by the Parser
+	value interface{}
+}
+
+func (l *Language) setOption(o option) {
+	for i := range l.options {
+		if l.options[i].name == o.name {
+			l.options[i] = o
+			return
+		}
+	}
+	l.options = append(l.options, o)
 }
 
 // NewLanguage returns the union of given Languages as new Language.
@@ -25,6 +54,18 @@ func NewLanguage(bases ...Language) Language {
 	for _, base := range bases {
 		for i, e := range base.prefixes {
 			l.prefixes[i] = e
+			if op, ok := base.prefixOperators[i]; ok {
+				l.prefixOperators[i] = op
+			} else {
+				delete(l.prefixOperators, i)
+			}
+			if name, ok := i.(string); ok {
+				if t, ok := base.functions[name]; ok {
+					l.functions[name] = t
+				} else {
+					delete(l.functions, name)
+				}
+			}
 		}
 		for i, e := range base.operators {
 			l.operators[i] = e.merge(l.operators[i])
@@ -42,15 +83,60 @@ func NewLanguage(bases ...Language) Language {
 		if base.selector != nil {
 			l.selector = base.selector
 		}
+		for _, o := range base.options {
+			l.setOption(o)
+		}
+		for name, target := range base.aliases {
+			l.aliases[name] = target
+		}
+	}
+	for name, target := range l.aliases {
+		l.alias(name, target)
 	}
 	return l
 }
 
+// Without returns a copy of the Language without the functions, constants, prefix and infix operators with given names,
+// e.g. Full().Without("**", "=~", "cfa") removes power, regex match and the cfa operator.
+func (l Language) Without(names ...string) Language {
+	w := NewLanguage(l)
+	for _, name := range names {
+		delete(w.prefixes, w.makePrefixKey(name))
+		delete(w.prefixOperators, w.makePrefixKey(name))
+		delete(w.functions, name)
+		delete(w.operators, name)
+		delete(w.aliases, name)
+	}
+	return w
+}
+
+// OnlyFunctions returns a copy of the Language without all functions except the ones with given names,
+// e.g. Full().OnlyFunctions("date") removes all functions but date.
+// Constants and operators are not affected.
+func (l Language) OnlyFunctions(names ...string) Language {
+	keep := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		keep[name] = struct{}{}
+	}
+	w := NewLanguage(l)
+	for name := range l.functions {
+		if _, ok := keep[name]; !ok {
+			delete(w.prefixes, name)
+			delete(w.functions, name)
+			delete(w.aliases, name)
+		}
+	}
+	return w
+}
+
 func newLanguage() Language {
 	return Language{
 		prefixes:        map[interface{}]extension{},
 		operators:       map[string]operator{},
 		operatorSymbols: map[rune]struct{}{},
+		functions:       map[string]reflect.Type{},
+		prefixOperators: map[interface{}]prefixOperator{},
+		aliases:         map[string]string{},
 	}
 }
 
@@ -61,18 +147,38 @@ func (l Language) NewEvaluable(expression string) (Evaluable, error) {
 
 // NewEvaluableWithContext returns an Evaluable for given expression in the specified language using context
 func (l Language) NewEvaluableWithContext(c context.Context, expression string) (Evaluable, error) {
-	p := newParser(expression, l)
-
-	eval, err := p.parse(c)
-	if err == nil && p.isCamouflaged() && p.lastScan != scanner.EOF {
-		err = p.camouflage
+	if reuse, _ := l.optionValue("reuseParsers").(bool); !reuse {
+		return newParser(expression, l).Evaluable(c)
 	}
-	if err != nil {
-		pos := p.scanner.Pos()
-		return nil, fmt.Errorf("parsing error: %s - %d:%d %w", p.scanner.Position, pos.Line, pos.Column, err)
+	p := parsers.Get().(*Parser)
+	p.reset(expression, l)
+	eval, err := p.Evaluable(c)
+	p.reset("", Language{})
+	parsers.Put(p)
+	return eval, err
+}
+
+// optionValue returns the value of the option with given name.
+func (l Language) optionValue(name string) interface{} {
+	for _, o := range l.options {
+		if o.name == name {
+			return o.value
+		}
 	}
+	return nil
+}
 
-	return eval, nil
+// applyOptions wraps eval of the expression with given number of nodes with the options of the Language.
+func (l Language) applyOptions(eval Evaluable, expression string, nodes int) Evaluable {
+	for _, o := range l.options {
+		if o.wrap != nil {
+			eval = o.wrap(eval)
+		}
+		if o.wrapParsed != nil {
+			eval = o.wrapParsed(eval, expression, nodes)
+		}
+	}
+	return eval
 }
 
 // Evaluate given parameter with given expression
@@ -94,31 +200,165 @@ func (l Language) EvaluateWithContext(c context.Context, expression string, para
 }
 
 // Function returns a Language with given function.
-// Function has no conversion for input types.
+// The function has any signature, optionally with a context.Context as first parameter that gets the context of the evaluation.
+// Calls with the wrong number of arguments fail while parsing.
+// Numbers given as arguments are converted to the number types of the parameters, e.g. float64 to int,
+// and arrays to slices of the element type, e.g. [1, 2] to []int.
+// func(arguments ...interface{}) (interface{}, error) and its variant with context get the arguments unconverted.
 //
 // If the function returns an error it must be the last return parameter.
 //
 // If the function has (without the error) more then one return parameter,
 // it returns them as []interface{}.
-func Function(name string, function interface{}) Language {
+//
+// Options like Pure configure the function.
+func Function(name string, function interface{}, options ...FunctionOption) Language {
+	var o functionOptions
+	o.min, o.max = functionArity(function)
+	for _, option := range options {
+		option(&o)
+	}
 	l := newLanguage()
 	l.prefixes[name] = func(c context.Context, p *Parser) (eval Evaluable, err error) {
-		args := []Evaluable{}
+		args := p.pipedArguments()
 		scan := p.Scan()
 		switch scan {
 		case '(':
-			args, err = p.parseArguments(c)
+			if o.parameters != nil {
+				args, err = p.parseNamedArguments(c, name, &o, args)
+			} else {
+				var more []Evaluable
+				more, err = p.parseArguments(c)
+				args = append(args, more...)
+			}
 			if err != nil {
 				return nil, err
 			}
 		default:
 			p.Camouflage("function call", '(')
+			if o.parameters != nil && len(args) > 0 {
+				if args, err = p.defaultArguments(name, &o, args, nil); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := checkArity(name, o.min, o.max, len(args)); err != nil {
+			return nil, err
+		}
+		if o.validate != nil {
+			if err := o.validate(args); err != nil {
+				return nil, fmt.Errorf("%s(): %w", name, err)
+			}
+		}
+		fun := toFunc(function)
+		if o.fold != nil && Evaluables(args).areConst() {
+			values := make([]interface{}, len(args))
+			for i, arg := range args {
+				if values[i], err = arg(c, nil); err != nil {
+					return nil, err
+				}
+			}
+			if f := o.fold(values); f != nil {
+				fun = f
+			}
+		}
+		call := p.callFunc(name, fun, args...)
+		if !o.pure || !Evaluables(args).areConst() {
+			return call, nil
+		}
+		v, err := call(c, nil)
+		if err != nil {
+			return nil, err
 		}
-		return p.callFunc(toFunc(function), args...), nil
+		return p.Const(v), nil
 	}
+	l.functions[name] = reflect.TypeOf(function)
 	return l
 }
 
+// Functions returns a Language with the functions of m in the namespace ns, called like ns.name(x),
+// e.g. Functions("str", map[string]interface{}{"upper": strings.ToUpper}) for str.upper("a").
+// A variable named like the namespace is still selected, e.g. str.length without a function length.
+// Namespaces can be nested like geo.utm.
+func Functions(ns string, m map[string]interface{}, options ...FunctionOption) Language {
+	functions := make([]Language, 0, len(m))
+	for name, function := range m {
+		functions = append(functions, Function(ns+"."+name, function, options...))
+	}
+	return NewLanguage(functions...)
+}
+
+// FunctionOption configures a function defined by Function.
+type FunctionOption func(*functionOptions)
+
+type functionOptions struct {
+	pure     bool
+	min, max int
+	validate func(args []Evaluable) error
+	// fold returns the function called with the constant arguments args instead of the function, if not nil
+	fold func(args []interface{}) function
+	// parameters are the names of the parameters, defaults their values if omitted
+	parameters []string
+	defaults   map[string]interface{}
+}
+
+// Pure marks a function as deterministic: its result depends on its arguments only and it has no side effects.
+// Calls of pure functions with constant arguments are evaluated once while parsing,
+// so e.g. pow(2, 10) is evaluated like the constant 1024 and failing calls fail the parsing.
+// Functions using the context like date with the location of ContextWithLocation are not pure.
+func Pure() FunctionOption {
+	return func(o *functionOptions) {
+		o.pure = true
+	}
+}
+
+// Arity limits the number of arguments of a function to min and max, without maximum if max is negative.
+// Calls with fewer or more arguments fail while parsing.
+// Arity is given for functions like func(arguments ...interface{}) (interface{}, error)
+// that check the number of their arguments themselves.
+func Arity(min, max int) FunctionOption {
+	return func(o *functionOptions) {
+		o.min, o.max = min, max
+	}
+}
+
+// Validate checks the arguments of each call of a function while parsing, e.g. that a constant layout is valid.
+// Constant arguments can be evaluated, their IsConst returns true.
+// An error of validate fails the parsing.
+func Validate(validate func(args []Evaluable) error) FunctionOption {
+	return func(o *functionOptions) {
+		o.validate = validate
+	}
+}
+
+// folding returns a FunctionOption preparing calls with constant arguments by fold while parsing,
+// e.g. to parse a constant pattern once per Evaluable.
+func folding(fold func(args []interface{}) function) FunctionOption {
+	return func(o *functionOptions) {
+		o.fold = fold
+	}
+}
+
+// Parameters names the parameters of a function, so arguments can be given by name
+// after the positional arguments like round(x, places: 2).
+// A context.Context parameter is not named.
+func Parameters(names ...string) FunctionOption {
+	return func(o *functionOptions) {
+		o.parameters = names
+	}
+}
+
+// Default gives the value of the parameter named by Parameters if it is omitted in a call,
+// e.g. Default("places", 2) calls round(x) like round(x, places: 2).
+func Default(name string, value interface{}) FunctionOption {
+	return func(o *functionOptions) {
+		if o.defaults == nil {
+			o.defaults = map[string]interface{}{}
+		}
+		o.defaults[name] = value
+	}
+}
+
 // Constant returns a Language with given constant
 func Constant(name string, value interface{}) Language {
 	l := newLanguage()
@@ -163,7 +403,8 @@ func PrefixMetaPrefix(r rune, ext func(context.Context, *Parser) (call string, a
 		if err != nil {
 			return nil, err
 		}
-		if prefix, ok := p.prefixes[l.makePrefixKey(call)]; ok {
+		// let bindings hide functions and constants with the same name
+		if prefix, ok := p.prefixes[l.makePrefixKey(call)]; ok && p.letBinding(call) == nil {
 			return prefix(c, p)
 		}
 		return alternative()
@@ -198,7 +439,24 @@ func PrefixOperator(name string, e Evaluable) Language {
 	return l
 }
 
-// PostfixOperator extends a Language.
+// PrefixOperatorWithPrecedence returns a Language with a prefix operator applied to the operations following it
+// with a higher precedence than the operator,
+// e.g. with PrefixOperatorWithPrecedence("#", 130, length) #items * 2 is #(items * 2) and #items + 1 is (#items) + 1.
+// Like PrefixOperator the name is a single rune or an identifier.
+// Following another prefix operator like ! it applies to the next operand only.
+func PrefixOperatorWithPrecedence(name string, precedence uint8, e Evaluable) Language {
+	l := PrefixOperator(name, e)
+	l.prefixOperators[l.makePrefixKey(name)] = prefixOperator{
+		operatorPrecedence: operatorPrecedence(precedence),
+		apply:              e,
+	}
+	return l
+}
+
+// PostfixOperator extends a Language with an operator following its operand.
+// ext is called by the Parser after the operator with the Evaluable of the operand
+// and may parse further input like the arguments of a method call.
+// The operand is the preceding operation with a higher precedence than the operator, set by Precedence.
 func PostfixOperator(name string, ext func(context.Context, *Parser, Evaluable) (Evaluable, error)) Language {
 	l := newLanguage()
 	l.operators[l.makeInfixKey(name)] = postfix{
@@ -209,16 +467,96 @@ func PostfixOperator(name string, ext func(context.Context, *Parser, Evaluable)
 	return l
 }
 
+// PostfixOperatorWithPrecedence returns a Language with a postfix operator applying e to the value of its operand,
+// e.g. ! as factorial or % as percent. The operand is the preceding operation with a higher precedence,
+// e.g. with PostfixOperatorWithPrecedence("%", 200, percent) 2 * 15% is 2 * (15%).
+// If the name is also an infix operator like % for modulo, the operator is infix if an operand follows it,
+// so 15% == 0.15 and 15 % 4 == 3. A following - or ( starts an operand.
+func PostfixOperatorWithPrecedence(name string, precedence uint8, e Evaluable) Language {
+	l := newLanguage()
+	l.operators[l.makeInfixKey(name)] = postfix{
+		operatorPrecedence: operatorPrecedence(precedence),
+		f: func(c context.Context, p *Parser, eval Evaluable, pre operatorPrecedence) (Evaluable, error) {
+			postfix := func(c context.Context, v interface{}) (interface{}, error) {
+				a, err := eval(c, v)
+				if err != nil {
+					return nil, err
+				}
+				return e(c, a)
+			}
+			if eval.IsConst() {
+				v, err := postfix(c, nil)
+				if err != nil {
+					return nil, err
+				}
+				return p.Const(v), nil
+			}
+			return postfix, nil
+		},
+	}
+	return l
+}
+
 // InfixOperator for two arbitrary values.
 func InfixOperator(name string, f func(a, b interface{}) (interface{}, error)) Language {
 	return newLanguageOperator(name, &infix{arbitrary: f})
 }
 
+// typedInfixOperator for operands of particular types like Version, see typedOpFunc.
+// Operators merged with it handle other operands.
+func typedInfixOperator(name string, f typedOpFunc) Language {
+	return newLanguageOperator(name, &infix{typed: []typedOpFunc{f}})
+}
+
+// InfixContextOperator for two arbitrary values with the context of the evaluation.
+// Operators looping over large inputs should call CheckContext to stop when the evaluation is cancelled.
+func InfixContextOperator(name string, f func(c context.Context, a, b interface{}) (interface{}, error)) Language {
+	return InfixEvalOperator(name, func(a, b Evaluable) (Evaluable, error) {
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			x, err := a(c, v)
+			if err != nil {
+				return nil, err
+			}
+			y, err := b(c, v)
+			if err != nil {
+				return nil, err
+			}
+			if c == nil {
+				c = context.Background()
+			}
+			return f(c, x, y)
+		}, nil
+	})
+}
+
 // InfixShortCircuit operator is called after the left operand is evaluated.
 func InfixShortCircuit(name string, f func(a interface{}) (interface{}, bool)) Language {
 	return newLanguageOperator(name, &infix{shortCircuit: f})
 }
 
+// InfixLazyOperator gets its operands unevaluated, so it controls if and in which order they are evaluated
+// with the context c and the parameter v of the evaluation, e.g. a implies b skipping b if a is false:
+//
+//	InfixLazyOperator("implies", func(c context.Context, v interface{}, a, b Evaluable) (interface{}, error) {
+//		x, err := a.EvalBool(c, v)
+//		if err != nil || !x {
+//			return !x, err
+//		}
+//		return b.EvalBool(c, v)
+//	})
+//
+// Like InfixEvalOperator it cannot be combined with operators for other operand types.
+func InfixLazyOperator(name string, f func(c context.Context, v interface{}, a, b Evaluable) (interface{}, error)) Language {
+	return InfixEvalOperator(name, func(a, b Evaluable) (Evaluable, error) {
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			if c == nil {
+				c = context.Background()
+			}
+			return f(c, v, a, b)
+		}, nil
+	})
+}
+
 // InfixTextOperator for two text values.
 func InfixTextOperator(name string, f func(a, b string) (interface{}, error)) Language {
 	return newLanguageOperator(name, &infix{text: f})
@@ -244,6 +582,31 @@ func Precedence(name string, operatorPrecendence uint8) Language {
 	return newLanguageOperator(name, operatorPrecedence(operatorPrecendence))
 }
 
+// Associativity defines how operators with the same precedence are grouped.
+type Associativity int
+
+const (
+	// LeftAssociative operators group a op b op c as (a op b) op c.
+	LeftAssociative Associativity = iota
+	// RightAssociative operators group a op b op c as a op (b op c).
+	RightAssociative
+)
+
+// InfixWordOperator returns a Language with an infix operator named by an identifier,
+// e.g. InfixWordOperator("implies", 20, RightAssociative, implies) for a implies b.
+// The operator is applied to two arbitrary values with given precedence and associativity.
+// It panics if name is not an identifier.
+func InfixWordOperator(name string, precedence uint8, associativity Associativity, f func(a, b interface{}) (interface{}, error)) Language {
+	if !isIdentifier(name) {
+		panic(fmt.Sprintf("gval: infix word operator %q is not an identifier", name))
+	}
+	return newLanguageOperator(name, &infix{
+		operatorPrecedence: operatorPrecedence(precedence),
+		arbitrary:          f,
+		rightAssociative:   associativity == RightAssociative,
+	})
+}
+
 // InfixEvalOperator operates on the raw operands.
 // Therefore it cannot be combined with operators for other operand types.
 func InfixEvalOperator(name string, f func(a, b Evaluable) (Evaluable, error)) Language {
@@ -272,6 +635,40 @@ func (l *Language) makeInfixKey(key string) string {
 	return key
 }
 
+// Alias returns a Language naming the operator, function or constant target also name,
+// e.g. Full(Alias("and", "&&"), Alias("or", "||"), Alias("not", "!")) evaluates a and not b or c like a && !b || c.
+// The alias has the implementation and precedence of the target in the Language it is merged with,
+// also if the target is defined after the alias.
+func Alias(name, target string) Language {
+	l := newLanguage()
+	l.aliases[name] = target
+	return l
+}
+
+// alias defines name like target.
+func (l *Language) alias(name, target string) {
+	if op, ok := l.operators[target]; ok {
+		l.operators[l.makeInfixKey(name)] = op.merge(nil)
+		l.operators[name].initiate(name)
+	}
+	key, aliasKey := l.makePrefixKey(target), l.makePrefixKey(name)
+	e, ok := l.prefixes[key]
+	if !ok {
+		return
+	}
+	l.prefixes[aliasKey] = e
+	if op, ok := l.prefixOperators[key]; ok {
+		l.prefixOperators[aliasKey] = op
+	} else {
+		delete(l.prefixOperators, aliasKey)
+	}
+	if t, ok := l.functions[target]; ok {
+		l.functions[name] = t
+	} else {
+		delete(l.functions, name)
+	}
+}
+
 // VariableSelector returns a Language which uses given variable selector.
 // It must be combined with a Language that uses the vatiable selector. E.g. gval.Base().
 func VariableSelector(selector func(path Evaluables) Evaluable) Language {
@@ -0,0 +1,483 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLanguage_Without(t *testing.T) {
+	l := Full(Math()).Without("**", "=~", "cfa", "abs")
+	for _, expression := range []string{"2 ** 3", `"a" =~ "a"`, `[] cfa ["a", "eq", "b"]`, "abs(1)"} {
+		if _, err := l.Evaluate(expression, nil); err == nil {
+			t.Errorf("Evaluate(%s) expected error", expression)
+		}
+	}
+	for expression, want := range map[string]interface{}{
+		"2 * 3":      6.,
+		`"a" + 1`:    "a1",
+		"-2":         -2.,
+		"floor(1.5)": 1.,
+	} {
+		got, err := l.Evaluate(expression, nil)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	if got, err := Full().Evaluate("2 ** 3", nil); err != nil || got != 8. {
+		t.Errorf("Without changed the original Language: %v, %v", got, err)
+	}
+}
+
+func TestLanguage_OnlyFunctions(t *testing.T) {
+	l := Full(Math(), Constant("pi", 3.)).OnlyFunctions("floor")
+	if _, err := l.Evaluate("abs(1)", nil); err == nil {
+		t.Error("Evaluate(abs(1)) expected error")
+	}
+	if _, err := l.Evaluate(`date("2020-01-01")`, nil); err == nil {
+		t.Error("Evaluate(date()) expected error")
+	}
+	got, err := l.Evaluate("floor(pi + 0.5) ** 2 > 1 && true", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != true {
+		t.Errorf("Evaluate() = %v, want true", got)
+	}
+
+	l = NewLanguage(Full(), Constant("date", 1.)).OnlyFunctions()
+	if got, err := l.Evaluate("date + 1", nil); err != nil || got != 2. {
+		t.Errorf("constant replacing a function is kept: %v, %v", got, err)
+	}
+}
+
+func TestInfixWordOperator(t *testing.T) {
+	implies := func(a, b interface{}) (interface{}, error) { return a != true || b == true, nil }
+	minus := func(a, b interface{}) (interface{}, error) { return a.(float64) - b.(float64), nil }
+	l := Full(
+		InfixWordOperator("implies", 10, RightAssociative, implies),
+		InfixWordOperator("minus", 120, LeftAssociative, minus),
+		InfixWordOperator("rminus", 120, RightAssociative, minus),
+	)
+	for expression, want := range map[string]interface{}{
+		"false implies false implies false":   true,
+		"(false implies false) implies false": false,
+		"a > 1 implies b && c":                true,
+		"10 minus 4 minus 3":                  3.,
+		"10 rminus 4 rminus 3":                9.,
+		"2 * 5 rminus 1 + 1":                  10.,
+	} {
+		got, err := l.Evaluate(expression, map[string]interface{}{"a": 0, "b": true, "c": false})
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	for expression, want := range map[string]string{
+		"(a implies b) implies c": "(a implies b) implies c",
+		"a implies (b implies c)": "a implies b implies c",
+		"(a rminus b) rminus c":   "(a rminus b) rminus c",
+		"a minus (b minus c)":     "a minus (b minus c)",
+	} {
+		node, err := l.Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", expression, err)
+		}
+		if got := Format(node); got != want {
+			t.Errorf("Format(%s) = %s, want %s", expression, got, want)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("InfixWordOperator(\"=>\") did not panic")
+		}
+	}()
+	InfixWordOperator("=>", 10, RightAssociative, implies)
+}
+
+func TestInfixLazyOperator(t *testing.T) {
+	calls := 0
+	l := Full(
+		InfixLazyOperator("implies", func(c context.Context, v interface{}, a, b Evaluable) (interface{}, error) {
+			x, err := a.EvalBool(c, v)
+			if err != nil || !x {
+				return !x, err
+			}
+			return b.EvalBool(c, v)
+		}),
+		InfixLazyOperator("then", func(c context.Context, v interface{}, a, b Evaluable) (interface{}, error) {
+			// evaluates the right operand first
+			y, err := b(c, v)
+			if err != nil {
+				return nil, err
+			}
+			x, err := a(c, v)
+			return []interface{}{x, y}, err
+		}),
+		Precedence("implies", 10),
+		Function("count", func() float64 {
+			calls++
+			return float64(calls)
+		}),
+		Function("fail", func() (interface{}, error) {
+			return nil, fmt.Errorf("evaluated")
+		}),
+	)
+	for expression, want := range map[string]interface{}{
+		"false implies fail()":       true,
+		"a > 1 implies b && c":       true,
+		"true implies b && c":        false,
+		"count() then count()":       []interface{}{2., 1.},
+		"(1 + 1 then 1) == [2, 1]":   true,
+		"[a then 1, a implies true]": []interface{}{[]interface{}{0, 1.}, true},
+	} {
+		got, err := l.Evaluate(expression, map[string]interface{}{"a": 0, "b": true, "c": false})
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	if _, err := l.Evaluate("true implies fail()", nil); err == nil || !strings.Contains(err.Error(), "evaluated") {
+		t.Errorf("Evaluate(true implies fail()) error = %v, want evaluated", err)
+	}
+}
+
+func TestPrefixOperatorWithPrecedence(t *testing.T) {
+	length := func(c context.Context, v interface{}) (interface{}, error) {
+		return float64(reflect.ValueOf(v).Len()), nil
+	}
+	l := Full(
+		PrefixOperatorWithPrecedence("#", 130, length),
+		PrefixOperatorWithPrecedence("+", 200, func(c context.Context, v interface{}) (interface{}, error) { return v, nil }),
+		PrefixOperatorWithPrecedence("not", 30, func(c context.Context, v interface{}) (interface{}, error) { return v != true, nil }),
+	)
+	parameter := map[string]interface{}{"items": []interface{}{1, 2, 3, 4}, "s": "abc"}
+	for expression, want := range map[string]interface{}{
+		"#items > 3":             true,
+		"#items + 1":             5.,
+		"(#items) * 2":           8.,
+		"2 * #items":             8.,
+		"+3 - 1":                 2.,
+		"#[1, 2] == 2":           true,
+		"not #items > 3 || true": true,
+		"not #items > 3":         false,
+		"not not true":           true,
+	} {
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	for expression, want := range map[string]string{
+		"#items+1":       "#items + 1",
+		"( #items ) * 2": "(#items) * 2",
+		"# s * 2":        "#s * 2",
+	} {
+		node, err := l.Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", expression, err)
+		}
+		if got := Format(node); got != want {
+			t.Errorf("Format(%s) = %s, want %s", expression, got, want)
+		}
+	}
+	if _, err := l.Evaluate("#", nil); err == nil {
+		t.Error("Evaluate(#) expected error")
+	}
+	if got, err := l.Without("#").Evaluate("# 1", nil); err == nil {
+		t.Errorf("Evaluate(# 1) without # = %v, expected error", got)
+	}
+}
+
+func TestPostfixOperatorWithPrecedence(t *testing.T) {
+	percent := func(c context.Context, v interface{}) (interface{}, error) {
+		return v.(float64) / 100, nil
+	}
+	factorial := func(c context.Context, v interface{}) (interface{}, error) {
+		n, ok := v.(float64)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("factorial of %v", v)
+		}
+		f := 1.
+		for i := 2.; i <= n; i++ {
+			f *= i
+		}
+		return f, nil
+	}
+	l := Full(
+		PostfixOperatorWithPrecedence("%", 200, percent),
+		PostfixOperatorWithPrecedence("!", 200, factorial),
+	)
+	parameter := map[string]interface{}{"rate": 15., "n": 4.}
+	for expression, want := range map[string]interface{}{
+		"15% == 0.15":     true,
+		"15 % 4":          3.,
+		"rate % n":        3.,
+		"2 * 15%":         0.3,
+		"rate% + 1":       1.15,
+		"(rate)%":         0.15,
+		"rate% in [0.15]": true,
+		"n! == 24":        true,
+		"3! * 2":          12.,
+		"-3 + 3!":         3.,
+		"n != 4":          false,
+		"!(n! > 20)":      false,
+		"(2 + 1)! + 15 %": 6.15,
+	} {
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	for expression, want := range map[string]string{
+		"rate %":  "rate%",
+		"(n+1) !": "(n + 1)!",
+		"n ! * 2": "n! * 2",
+		"15 % 4":  "15 % 4",
+	} {
+		node, err := l.Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", expression, err)
+		}
+		if got := Format(node); got != want {
+			t.Errorf("Format(%s) = %s, want %s", expression, got, want)
+		}
+	}
+	if _, err := l.Evaluate("(-1)!", nil); err == nil {
+		t.Error("Evaluate((-1)!) expected error")
+	}
+}
+
+func TestAlias(t *testing.T) {
+	l := Full(Alias("and", "&&"), Alias("or", "||"), Alias("not", "!"), Alias("≠", "!="), Alias("absolute", "abs"), Math())
+	parameter := map[string]interface{}{"a": 2, "b": false, "c": false}
+	for expression, want := range map[string]interface{}{
+		"a > 1 and not b":        true,
+		"a > 1 and b or c":       false,
+		"not c and (b or a > 1)": true,
+		"false and x.y":          false,
+		"true or x.y":            true,
+		"a ≠ 1":                  true,
+		"absolute(-a)":           2.,
+		"1 + 1 == 2 and 3 > 2":   true,
+	} {
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	if got, err := l.Without("and").Evaluate("true and true", nil); err == nil {
+		t.Errorf("Evaluate(true and true) without and = %v, expected error", got)
+	}
+	if got, err := l.OnlyFunctions("abs").Evaluate("absolute(1)", nil); err == nil {
+		t.Errorf("Evaluate(absolute(1)) with only abs = %v, expected error", got)
+	}
+	if got, err := NewLanguage(Alias("and", "&&")).Evaluate("true and true", nil); err == nil {
+		t.Errorf("Evaluate(true and true) without && = %v, expected error", got)
+	}
+}
+
+func TestPure(t *testing.T) {
+	calls := 0
+	twice := func(x float64) float64 {
+		calls++
+		return 2 * x
+	}
+	l := Full(Function("twice", twice, Pure()), Function("impure", twice))
+	eval, err := l.NewEvaluable("twice(2) + twice(1 + 2) + impure(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("parsing called twice %d times, want 2", calls)
+	}
+	for i := 0; i < 3; i++ {
+		if got, err := eval(context.Background(), nil); err != nil || got != 12. {
+			t.Errorf("eval() = %v, %v, want 12", got, err)
+		}
+	}
+	if calls != 5 {
+		t.Errorf("twice called %d times, want 5", calls)
+	}
+	if eval, err := l.NewEvaluable("twice(2)"); err != nil || !eval.IsConst() {
+		t.Errorf("twice(2) is not constant: %v", err)
+	}
+	if got, err := l.Evaluate("twice(x)", map[string]interface{}{"x": 3.}); err != nil || got != 6. {
+		t.Errorf("Evaluate(twice(x)) = %v, %v, want 6", got, err)
+	}
+	if _, err := l.NewEvaluable(`twice("a")`); err == nil {
+		t.Error(`NewEvaluable(twice("a")) expected error`)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	l := Full(
+		Functions("str", map[string]interface{}{
+			"upper": strings.ToUpper,
+			"join":  func(a, b string) string { return a + b },
+		}),
+		Functions("geo.utm", map[string]interface{}{
+			"zone": func(lon float64) float64 { return float64(int((lon+180)/6) + 1) },
+		}),
+	)
+	parameter := map[string]interface{}{
+		"str":  map[string]interface{}{"length": 3., "upper": "not called"},
+		"name": "gval",
+		"obj":  map[string]interface{}{"f": func() string { return "f" }},
+	}
+	for expression, want := range map[string]interface{}{
+		`str.upper("a")`:                "A",
+		`str.upper(name) + "!"`:         "GVAL!",
+		`str.join("a", str.upper("b"))`: "aB",
+		"str.length":                    3.,
+		"str.upper":                     "not called",
+		"geo.utm.zone(13.4)":            33.,
+		"obj.f()":                       "f",
+	} {
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	if _, err := l.Evaluate(`str.lower("A")`, parameter); err == nil {
+		t.Error(`Evaluate(str.lower("A")) expected error`)
+	}
+	node, err := l.Parse(`str.upper( name )`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Format(node); got != "str.upper(name)" {
+		t.Errorf("Format() = %s, want str.upper(name)", got)
+	}
+}
+
+func TestFunction_conversion(t *testing.T) {
+	l := Full(
+		Function("repeat", func(s string, n int) (string, error) {
+			if n < 0 {
+				return "", fmt.Errorf("negative count %d", n)
+			}
+			return strings.Repeat(s, n), nil
+		}),
+		Function("sum", func(c context.Context, first uint8, rest ...float32) float64 {
+			sum := float64(first)
+			for _, r := range rest {
+				sum += float64(r)
+			}
+			return sum
+		}),
+		Function("count", func(ids []int) int { return len(ids) }),
+	)
+	parameter := map[string]interface{}{"n": 2, "ids": []interface{}{1., 2., 3.}}
+	for expression, want := range map[string]interface{}{
+		`repeat("ab", 3)`: "ababab",
+		`repeat("x", n)`:  "xx",
+		"sum(1)":          1.,
+		"sum(1, 2.5, n)":  5.5,
+		"count(ids)":      3,
+		"count([4, 5])":   2,
+	} {
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	for _, expression := range []string{`repeat("a", 1.5)`, `repeat("a", -1)`, `repeat(1, 1)`, "sum(-1)", `count(["a"])`} {
+		if _, err := l.Evaluate(expression, parameter); err == nil {
+			t.Errorf("Evaluate(%s) expected error", expression)
+		}
+	}
+	for _, expression := range []string{`repeat("a")`, `repeat("a", 1, 2)`, "sum()"} {
+		if _, err := l.NewEvaluable(expression); err == nil || !strings.Contains(err.Error(), "expects") {
+			t.Errorf("NewEvaluable(%s) error = %v, want arity error", expression, err)
+		}
+	}
+}
+
+func TestArityAndValidate(t *testing.T) {
+	join := func(arguments ...interface{}) (interface{}, error) {
+		return fmt.Sprint(arguments...), nil
+	}
+	nonEmpty := func(args []Evaluable) error {
+		for _, arg := range args {
+			if arg.IsConst() {
+				if v, _ := arg(context.Background(), nil); v == "" {
+					return fmt.Errorf("empty string")
+				}
+			}
+		}
+		return nil
+	}
+	l := Full(Function("join", join, Arity(1, 2), Validate(nonEmpty)))
+	if got, err := l.Evaluate(`join("a", s)`, map[string]interface{}{"s": ""}); err != nil || got != "a" {
+		t.Errorf(`Evaluate(join("a", s)) = %v, %v, want a`, got, err)
+	}
+	for expression, want := range map[string]string{
+		"join()":              "join() expects at least 1 arguments but got 0",
+		`join("a", "b", "c")`: "join() expects at most 2 arguments but got 3",
+		`join("a", "")`:       "join(): empty string",
+		"date(2024)":          "date(): unexpected 2024(float64) as argument 1, expected string",
+		"now(1)":              "now() expects 0 arguments but got 1",
+	} {
+		if _, err := NewLanguage(l, Time()).NewEvaluable(expression); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("NewEvaluable(%s) error = %v, want %s", expression, err, want)
+		}
+	}
+	if _, err := l.NewEvaluable(`date("2024-01-01", "2006-01-02")`); err == nil || !strings.Contains(err.Error(), "date() expects 1 arguments but got 2") {
+		t.Errorf("NewEvaluable(date with layout) error = %v, want arity error", err)
+	}
+}
+
+func TestParameters(t *testing.T) {
+	score := func(base, weight, bonus float64) float64 { return base*weight + bonus }
+	l := Full(Math(), Function("score", score, Parameters("base", "weight", "bonus"), Default("weight", 1), Default("bonus", 0)))
+	parameter := map[string]interface{}{"x": 2.345, "places": 2}
+	for expression, want := range map[string]interface{}{
+		"round(x, places: 2)":             2.35,
+		"round(x, places: places - 1)":    2.3,
+		"round(x: x)":                     2.,
+		"score(10)":                       10.,
+		"score(10, bonus: 5)":             15.,
+		"score(bonus: 1, base: 2)":        3.,
+		"score(10, 2)":                    20.,
+		"score(10, weight: 3, bonus: 1)":  31.,
+		"clamp(x, min: 0, max: 2)":        2.,
+		"score(x > 2 ? 1 : 0, weight: 2)": 2.,
+	} {
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	for expression, want := range map[string]string{
+		"score(10, factor: 2)":      "score() has no parameter factor",
+		"score(10, base: 2)":        "score() got the argument base twice",
+		"score(bonus: 1, bonus: 2)": "score() got the argument bonus twice",
+		"score(bonus: 1, 2)":        "score() got a positional argument after named arguments",
+		"score(weight: 2)":          "score() expects the argument base",
+		"round(places: 2)":          "round() expects the argument x",
+		"score(1, 2, 3, 4)":         "score() expects 3 arguments but got 4",
+	} {
+		if _, err := l.NewEvaluable(expression); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("NewEvaluable(%s) error = %v, want %s", expression, err, want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package gval
+
+import (
+	"context"
+	"text/scanner"
+)
+
+// Let returns a Language with let bindings naming the value of an expression for the expression following it:
+//
+//	let discounted = price * 0.9; discounted > threshold ? discounted : price
+//
+// The value is evaluated once per evaluation. The name hides a parameter, a function or a constant with the same name
+// and is visible until the end of the enclosing parentheses, brackets or arguments,
+// so bindings can be chained like let a = x * 2; let b = a + 1; a * b.
+func Let() Language {
+	return let
+}
+
+var let = func() Language {
+	l := newLanguage()
+	l.prefixes[l.makePrefixKey("let")] = parseLet
+	return l
+}()
+
+// letBinding is a name bound by let. It is the context key of the bound value.
+type letBinding struct {
+	name string
+}
+
+// value returns the bound value of the evaluation.
+func (b *letBinding) value(c context.Context, v interface{}) (interface{}, error) {
+	return c.Value(b), nil
+}
+
+// letBinding returns the innermost binding of name in scope or nil.
+func (p *Parser) letBinding(name string) *letBinding {
+	for i := len(p.lets) - 1; i >= 0; i-- {
+		if p.lets[i].name == name {
+			return p.lets[i]
+		}
+	}
+	return nil
+}
+
+func parseLet(c context.Context, p *Parser) (Evaluable, error) {
+	if p.Scan() != scanner.Ident {
+		return nil, p.Expected("let", scanner.Ident)
+	}
+	binding := &letBinding{name: p.TokenText()}
+	if p.Scan() != '=' {
+		return nil, p.Expected("let", '=')
+	}
+	value, err := p.ParseExpression(c)
+	if err != nil {
+		return nil, err
+	}
+	if p.Scan() != ';' {
+		return nil, p.Expected("let", ';')
+	}
+	p.lets = append(p.lets, binding)
+	body, err := p.ParseExpression(c)
+	p.lets = p.lets[:len(p.lets)-1]
+	if err != nil {
+		return nil, err
+	}
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		x, err := value(c, v)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			c = context.Background()
+		}
+		return body(context.WithValue(c, binding, x), v)
+	}, nil
+}
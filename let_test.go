@@ -0,0 +1,90 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestLet(t *testing.T) {
+	lang := NewLanguage(Full(), Let())
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "binding",
+				expression: `let discounted = price * 0.9; discounted > threshold ? discounted : price`,
+				extension:  lang,
+				parameter:  map[string]interface{}{"price": 100, "threshold": 50},
+				want:       90.0,
+			},
+			{
+				name:       "chained",
+				expression: `let a = x * 2; let b = a + 1; a * b`,
+				extension:  lang,
+				parameter:  map[string]interface{}{"x": 3},
+				want:       42.0,
+			},
+			{
+				name:       "shadowing",
+				expression: `let x = x + 1; let x = x * 10; x`,
+				extension:  lang,
+				parameter:  map[string]interface{}{"x": 1},
+				want:       20.0,
+			},
+			{
+				name:       "path on binding",
+				expression: `let u = users[0]; u.name + " " + u["role"]`,
+				extension:  lang,
+				parameter:  map[string]interface{}{"users": []interface{}{map[string]interface{}{"name": "ada", "role": "admin"}}},
+				want:       "ada admin",
+			},
+			{
+				name:       "scoped to parentheses",
+				expression: `(let x = 5; x) + x`,
+				extension:  lang,
+				parameter:  map[string]interface{}{"x": 1},
+				want:       6.0,
+			},
+			{
+				name:       "constant",
+				expression: `let a = 2; a ** 3`,
+				extension:  lang,
+				want:       8.0,
+			},
+			{
+				name:       "hides function",
+				expression: `let floor = 3; floor + 1`,
+				extension:  NewLanguage(Full(), Math(), Let()),
+				want:       4.0,
+			},
+			{
+				name:       "hides constant",
+				expression: `let pi = 3; 2 * pi`,
+				extension:  NewLanguage(Full(), Let(), Constant("pi", 3.14159)),
+				want:       6.0,
+			},
+			{
+				name:       "function outside of binding",
+				expression: `(let floor = 3; floor + 1) + floor(2.5)`,
+				extension:  NewLanguage(Full(), Math(), Let()),
+				want:       6.0,
+			},
+			{
+				name:       "missing semicolon",
+				expression: `let a = 2 a`,
+				extension:  lang,
+				wantErr:    `expected ";"`,
+			},
+			{
+				name:       "missing name",
+				expression: `let = 2; a`,
+				extension:  lang,
+				wantErr:    "while scanning let",
+			},
+			{
+				name:       "without Let",
+				expression: `let a = 2; a`,
+				wantErr:    "parsing error",
+			},
+		},
+		t,
+	)
+}
@@ -0,0 +1,133 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Limits restrict the resources used by an expression. Zero values are not limited.
+type Limits struct {
+	// MaxNodes is the maximal number of operands and operators of an expression.
+	MaxNodes int
+	// MaxDepth is the maximal nesting depth of operands, e.g. f((a)) has a depth of 3.
+	MaxDepth int
+	// MaxStringLen is the maximal length in bytes of strings evaluated by operands and expressions.
+	MaxStringLen int
+	// MaxArrayLen is the maximal length of slices and arrays evaluated by operands and expressions.
+	MaxArrayLen int
+}
+
+// ErrLimitExceeded is matched by errors.Is for all LimitExceededErrors.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// LimitExceededError reports an expression exceeding a limit given by WithLimits.
+type LimitExceededError struct {
+	// Limit is the name of the exceeded field of Limits, e.g. MaxDepth
+	Limit string
+	// Max is the value of the exceeded limit
+	Max int
+}
+
+func (err *LimitExceededError) Error() string {
+	return fmt.Sprintf("limit exceeded: %s is %d", err.Limit, err.Max)
+}
+
+// Is returns true for ErrLimitExceeded.
+func (err *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// WithLimits returns a Language that restricts expressions to given limits.
+// Nodes and depth are checked while parsing, strings and arrays while evaluating.
+// Exceeding a limit fails with a *LimitExceededError.
+//
+// WithLimits protects against expensive user supplied expressions.
+// Use a context with timeout to restrict the duration of evaluations as well.
+func WithLimits(limits Limits) Language {
+	l := newLanguage()
+	l.setOption(option{name: "limits", value: limits})
+	return l
+}
+
+func (l Language) limits() *Limits {
+	limits, ok := l.optionValue("limits").(Limits)
+	if !ok {
+		return nil
+	}
+	return &limits
+}
+
+// parseLimitedOperand parses an operand checking its depth and size.
+func (p *Parser) parseLimitedOperand(c context.Context) (Evaluable, floatEvaluable, error) {
+	if p.limits.MaxDepth > 0 && p.depth >= p.limits.MaxDepth {
+		return nil, nil, &LimitExceededError{Limit: "MaxDepth", Max: p.limits.MaxDepth}
+	}
+	p.depth++
+	eval, float, err := p.parseOperand(c)
+	p.depth--
+	if err != nil {
+		return nil, nil, err
+	}
+	eval, err = p.limits.check(eval)
+	return eval, float, err
+}
+
+// countNode counts a parsed operand or operator and checks MaxNodes.
+func (p *Parser) countNode() error {
+	p.nodeCount++
+	if p.limits != nil && p.limits.MaxNodes > 0 && p.nodeCount > p.limits.MaxNodes {
+		return &LimitExceededError{Limit: "MaxNodes", Max: p.limits.MaxNodes}
+	}
+	return nil
+}
+
+// check returns eval checking the size of its results.
+// Constants are checked immediately.
+func (limits *Limits) check(eval Evaluable) (Evaluable, error) {
+	if limits.MaxStringLen <= 0 && limits.MaxArrayLen <= 0 {
+		return eval, nil
+	}
+	if eval.IsConst() {
+		v, err := eval(nil, nil)
+		if err != nil {
+			return eval, nil
+		}
+		return eval, limits.checkValue(v)
+	}
+	return func(c context.Context, parameter interface{}) (interface{}, error) {
+		v, err := eval(c, parameter)
+		if err != nil {
+			return nil, err
+		}
+		if err := limits.checkValue(v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}, nil
+}
+
+func (limits *Limits) checkValue(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		if limits.MaxStringLen > 0 && len(v) > limits.MaxStringLen {
+			return &LimitExceededError{Limit: "MaxStringLen", Max: limits.MaxStringLen}
+		}
+		return nil
+	case []interface{}:
+		if limits.MaxArrayLen > 0 && len(v) > limits.MaxArrayLen {
+			return &LimitExceededError{Limit: "MaxArrayLen", Max: limits.MaxArrayLen}
+		}
+		return nil
+	case nil, bool, float64:
+		return nil
+	}
+	if limits.MaxArrayLen <= 0 {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Len() > limits.MaxArrayLen {
+		return &LimitExceededError{Limit: "MaxArrayLen", Max: limits.MaxArrayLen}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package gval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithLimits(t *testing.T) {
+	limits := Limits{MaxNodes: 10, MaxDepth: 3, MaxStringLen: 8, MaxArrayLen: 3}
+	tests := []struct {
+		name       string
+		expression string
+		parameter  interface{}
+		wantLimit  string
+	}{
+		{name: "within limits", expression: `a + "bc" in [1, "xbc"]`, parameter: map[string]interface{}{"a": "x"}},
+		{name: "nodes", expression: "1 + 2 + 3 + 4 + 5 + 6", wantLimit: "MaxNodes"},
+		{name: "depth", expression: "((((1))))", wantLimit: "MaxDepth"},
+		{name: "prefix depth", expression: "----1", wantLimit: "MaxDepth"},
+		{name: "constant string", expression: `"123456789"`, wantLimit: "MaxStringLen"},
+		{name: "string parameter", expression: `a`, parameter: map[string]interface{}{"a": "123456789"}, wantLimit: "MaxStringLen"},
+		{name: "concatenation", expression: `a + a`, parameter: map[string]interface{}{"a": "12345"}, wantLimit: "MaxStringLen"},
+		{name: "array", expression: `[a, a, a, a]`, parameter: map[string]interface{}{"a": 1.}, wantLimit: "MaxArrayLen"},
+		{name: "typed slice", expression: `a`, parameter: map[string]interface{}{"a": []int{1, 2, 3, 4}}, wantLimit: "MaxArrayLen"},
+	}
+	l := Full(WithLimits(limits))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := l.Evaluate(tt.expression, tt.parameter)
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("Evaluate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("Evaluate() error = %v, want ErrLimitExceeded", err)
+			}
+			var limitErr *LimitExceededError
+			if !errors.As(err, &limitErr) || limitErr.Limit != tt.wantLimit {
+				t.Fatalf("Evaluate() error = %v, want %s exceeded", err, tt.wantLimit)
+			}
+		})
+	}
+
+	if _, err := Full().Evaluate(strings.Repeat("(", 20)+"1"+strings.Repeat(")", 20), nil); err != nil {
+		t.Errorf("Evaluate() without limits error = %v", err)
+	}
+}
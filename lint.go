@@ -0,0 +1,194 @@
+package gval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// LintIssue is a suspicious construct found by Lint.
+type LintIssue struct {
+	// Rule is the name of the rule that found the issue
+	Rule string
+	// Pos and End are the byte offsets of the construct in the expression,
+	// Line and Column the position of Pos starting at 1.
+	Pos, End     int
+	Line, Column int
+	Message      string
+}
+
+func (issue LintIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s (%s)", issue.Line, issue.Column, issue.Message, issue.Rule)
+}
+
+// LintRule finds suspicious constructs in expressions.
+// Node is called for each node of the syntax tree, SyntaxError for expressions that can not be parsed.
+// Both return a message for a suspicious construct and "" otherwise. Either may be nil.
+type LintRule struct {
+	Name        string
+	Node        func(l Language, n *Node) string
+	SyntaxError func(l Language, err *SyntaxError) string
+}
+
+// Lint returns the suspicious constructs of the expression in the Language found by the rules, DefaultLintRules if none are given.
+// The issues are ordered by their position. Lint fails with the syntax error of an expression
+// that can not be parsed unless a rule explains it, e.g. = instead of ==.
+func Lint(expression string, l Language, rules ...LintRule) ([]LintIssue, error) {
+	if len(rules) == 0 {
+		rules = DefaultLintRules
+	}
+	node, err := l.Parse(expression)
+	if err != nil {
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) {
+			return nil, err
+		}
+		issues := []LintIssue{}
+		for _, rule := range rules {
+			if rule.SyntaxError == nil {
+				continue
+			}
+			if message := rule.SyntaxError(l, syntax); message != "" {
+				issues = append(issues, LintIssue{
+					Rule: rule.Name,
+					Pos:  syntax.Offset, End: syntax.Offset + len(syntax.Token),
+					Line: syntax.Line, Column: syntax.Column,
+					Message: message,
+				})
+			}
+		}
+		if len(issues) == 0 {
+			return nil, err
+		}
+		return issues, nil
+	}
+	issues := []LintIssue{}
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		for _, rule := range rules {
+			if rule.Node == nil {
+				continue
+			}
+			if message := rule.Node(l, n); message != "" {
+				line, column := position(expression, n.Pos)
+				issues = append(issues, LintIssue{
+					Rule: rule.Name,
+					Pos:  n.Pos, End: n.End,
+					Line: line, Column: column,
+					Message: message,
+				})
+			}
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return issues, nil
+}
+
+// DefaultLintRules are the rules of Lint without given rules.
+var DefaultLintRules = []LintRule{LintAssignment, LintConstantComparison, LintUnknownFunction, LintShadowedConstant}
+
+// LintAssignment reports = used like an assignment where == compares.
+var LintAssignment = LintRule{
+	Name: "assignment",
+	SyntaxError: func(l Language, err *SyntaxError) string {
+		if err.Token != "=" {
+			return ""
+		}
+		return "= is no comparison, use =="
+	},
+}
+
+// comparisonOperators are the operators checked by LintConstantComparison.
+var comparisonOperators = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// LintConstantComparison reports comparisons that are always true or always false,
+// e.g. 1 > 2 or a == a.
+var LintConstantComparison = LintRule{
+	Name: "constant-comparison",
+	Node: func(l Language, n *Node) string {
+		if n.Kind != InfixNode || !comparisonOperators[n.Operator] {
+			return ""
+		}
+		if n.constant {
+			return fmt.Sprintf("comparison is always %v", n.Value)
+		}
+		a, b := n.Children[0], n.Children[1]
+		if a.Kind == VariableNode && b.Kind == VariableNode && strings.Join(a.Path, ".") == strings.Join(b.Path, ".") {
+			return fmt.Sprintf("comparison is always %v", n.Operator == "==" || n.Operator == "<=" || n.Operator == ">=")
+		}
+		return ""
+	},
+}
+
+// LintUnknownFunction reports calls of functions the Language does not define.
+// They are evaluated as calls of functions given by the parameter.
+var LintUnknownFunction = LintRule{
+	Name: "unknown-function",
+	Node: func(l Language, n *Node) string {
+		if n.Kind != OperandNode {
+			return ""
+		}
+		name, ok := callee(strings.TrimSpace(n.source[n.start:n.end]))
+		if !ok {
+			return ""
+		}
+		if _, ok := l.functions[name]; ok {
+			return ""
+		}
+		if _, ok := l.prefixes[name]; ok || strings.Contains(name, ".") {
+			// a.b(x) may call a method of a
+			return ""
+		}
+		return fmt.Sprintf("unknown function %s", name)
+	},
+}
+
+// LintShadowedConstant reports let bindings hiding a constant or function of the Language,
+// e.g. let pi = 3; 2 * pi.
+var LintShadowedConstant = LintRule{
+	Name: "shadowed-constant",
+	Node: func(l Language, n *Node) string {
+		text := strings.TrimSpace(n.source[n.start:n.end])
+		if _, ok := l.prefixes["let"]; !ok || n.Kind != OperandNode || len(text) < 4 || text[:3] != "let" || !unicode.IsSpace(rune(text[3])) {
+			return ""
+		}
+		fields := strings.FieldsFunc(text[3:], func(r rune) bool { return r == '=' || unicode.IsSpace(r) })
+		if len(fields) == 0 {
+			return ""
+		}
+		name := fields[0]
+		if _, ok := l.functions[name]; ok {
+			return fmt.Sprintf("let %s shadows the function %s", name, name)
+		}
+		if _, ok := l.prefixes[name]; ok {
+			return fmt.Sprintf("let %s shadows the constant %s", name, name)
+		}
+		return ""
+	},
+}
+
+// LintDeprecated returns a rule reporting the use of deprecated operators and functions,
+// given with their replacements, e.g. LintDeprecated(map[string]string{"cfa": "any"}).
+func LintDeprecated(replacements map[string]string) LintRule {
+	return LintRule{
+		Name: "deprecated",
+		Node: func(l Language, n *Node) string {
+			name := n.Operator
+			if n.Kind == OperandNode {
+				name, _ = callee(strings.TrimSpace(n.source[n.start:n.end]))
+			}
+			replacement, ok := replacements[name]
+			if !ok || name == "" {
+				return ""
+			}
+			if replacement == "" {
+				return fmt.Sprintf("%s is deprecated", name)
+			}
+			return fmt.Sprintf("%s is deprecated, use %s", name, replacement)
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package gval
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	l := Full(Math(), Let(), Constant("pi", 3.14159))
+	for expression, want := range map[string][]string{
+		"a == 1":                   {},
+		"a = 1":                    {"1:3: = is no comparison, use == (assignment)"},
+		"1 > 2 || a":               {"1:1: comparison is always false (constant-comparison)"},
+		"a.b == a.b && c":          {"1:1: comparison is always true (constant-comparison)"},
+		"a < a":                    {"1:1: comparison is always false (constant-comparison)"},
+		"abs(a) > foo(b)":          {"1:10: unknown function foo (unknown-function)"},
+		"let pi = 3; 2 * pi":       {"1:1: let pi shadows the constant pi (shadowed-constant)"},
+		"let floor = 3; floor + 1": {"1:1: let floor shadows the function floor (shadowed-constant)"},
+		"let x = 3; x + pi":        {},
+	} {
+		issues, err := Lint(expression, l)
+		if err != nil {
+			t.Errorf("Lint(%s) error = %v", expression, err)
+			continue
+		}
+		got := []string{}
+		for _, issue := range issues {
+			got = append(got, issue.String())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Lint(%s) = %v, want %v", expression, got, want)
+		}
+	}
+
+	issues, err := Lint("a cfa [1, 2] && round(a)", l, LintDeprecated(map[string]string{"cfa": "any", "round": ""}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 || issues[0].Message != "cfa is deprecated, use any" || issues[1].Message != "round is deprecated" || issues[1].Pos != 16 {
+		t.Errorf("Lint() with deprecated = %v", issues)
+	}
+	if _, err := Lint("a +", l); err == nil {
+		t.Error("Lint(a +) expected error")
+	}
+}
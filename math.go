@@ -0,0 +1,164 @@
+package gval
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// Math contains the functions abs, ceil, floor, round, sqrt, log, pow and clamp.
+//
+//	abs(x)              absolute value of x
+//	ceil(x)             least integer value greater than or equal to x
+//	floor(x)            greatest integer value less than or equal to x
+//	round(x)            x rounded half away from zero to an integer
+//	round(x, places)    x rounded half away from zero to the given decimal places
+//	sqrt(x)             square root of x
+//	log(x)              natural logarithm of x
+//	log(x, base)        logarithm of x to the given base
+//	pow(x, y)           x to the power of y
+//	clamp(x, min, max)  x limited to the range [min, max]
+//
+// The functions dispatch on the operand type. If any operand is a decimal.Decimal
+// the calculation is done in decimal arithmetic and a decimal.Decimal is returned,
+// otherwise the operands are converted to float64 like in Arithmetic.
+// sqrt, log and pow with fractional exponents have no exact decimal implementation,
+// they calculate on float64 and convert the result back to decimal.Decimal.
+// The functions are Pure, so calls with constant arguments are evaluated while parsing.
+// Arguments can be given by the parameter names above, e.g. round(x, places: 2).
+func Math() Language {
+	return mathLanguage
+}
+
+var mathLanguage = NewLanguage(
+	Function("abs", mathFunc("abs", 1, 1,
+		func(x []float64) (interface{}, error) { return math.Abs(x[0]), nil },
+		func(x []decimal.Decimal) (interface{}, error) { return x[0].Abs(), nil },
+	), Pure()),
+	Function("ceil", mathFunc("ceil", 1, 1,
+		func(x []float64) (interface{}, error) { return math.Ceil(x[0]), nil },
+		func(x []decimal.Decimal) (interface{}, error) { return x[0].Ceil(), nil },
+	), Pure()),
+	Function("floor", mathFunc("floor", 1, 1,
+		func(x []float64) (interface{}, error) { return math.Floor(x[0]), nil },
+		func(x []decimal.Decimal) (interface{}, error) { return x[0].Floor(), nil },
+	), Pure()),
+	Function("round", mathFunc("round", 1, 2,
+		func(x []float64) (interface{}, error) {
+			if len(x) == 1 {
+				return math.Round(x[0]), nil
+			}
+			places := math.Pow(10, math.Trunc(x[1]))
+			return math.Round(x[0]*places) / places, nil
+		},
+		func(x []decimal.Decimal) (interface{}, error) {
+			if len(x) == 1 {
+				return x[0].Round(0), nil
+			}
+			return x[0].Round(int32(x[1].IntPart())), nil
+		},
+	), Pure(), Parameters("x", "places")),
+	Function("sqrt", mathFunc("sqrt", 1, 1,
+		func(x []float64) (interface{}, error) { return math.Sqrt(x[0]), nil },
+		func(x []decimal.Decimal) (interface{}, error) {
+			if x[0].IsNegative() {
+				return nil, fmt.Errorf("sqrt() of negative number %s", x[0])
+			}
+			return decimal.NewFromFloat(math.Sqrt(x[0].InexactFloat64())), nil
+		},
+	), Pure()),
+	Function("log", mathFunc("log", 1, 2,
+		func(x []float64) (interface{}, error) {
+			if len(x) == 1 {
+				return math.Log(x[0]), nil
+			}
+			return math.Log(x[0]) / math.Log(x[1]), nil
+		},
+		func(x []decimal.Decimal) (interface{}, error) {
+			for _, d := range x {
+				if !d.IsPositive() {
+					return nil, fmt.Errorf("log() of non positive number %s", d)
+				}
+			}
+			l := math.Log(x[0].InexactFloat64())
+			if len(x) == 2 {
+				l /= math.Log(x[1].InexactFloat64())
+			}
+			return decimal.NewFromFloat(l), nil
+		},
+	), Pure(), Parameters("x", "base")),
+	Function("pow", mathFunc("pow", 2, 2,
+		func(x []float64) (interface{}, error) { return math.Pow(x[0], x[1]), nil },
+		func(x []decimal.Decimal) (interface{}, error) {
+			if x[1].IsInteger() {
+				return x[0].Pow(x[1]), nil
+			}
+			// decimal.Decimal.Pow drops fractional exponents
+			if x[0].IsNegative() {
+				return nil, fmt.Errorf("pow() of negative number %s to the fractional power %s", x[0], x[1])
+			}
+			return decimal.NewFromFloat(math.Pow(x[0].InexactFloat64(), x[1].InexactFloat64())), nil
+		},
+	), Pure(), Parameters("x", "y")),
+	Function("clamp", mathFunc("clamp", 3, 3,
+		func(x []float64) (interface{}, error) {
+			if x[1] > x[2] {
+				return nil, fmt.Errorf("clamp() min %v is greater than max %v", x[1], x[2])
+			}
+			return math.Max(x[1], math.Min(x[2], x[0])), nil
+		},
+		func(x []decimal.Decimal) (interface{}, error) {
+			if x[1].GreaterThan(x[2]) {
+				return nil, fmt.Errorf("clamp() min %s is greater than max %s", x[1], x[2])
+			}
+			return decimal.Max(x[1], decimal.Min(x[2], x[0])), nil
+		},
+	), Pure(), Parameters("x", "min", "max")),
+)
+
+// mathFunc creates a function that accepts between min and max number arguments.
+// It calls dec if one of the arguments is a decimal.Decimal and number otherwise.
+func mathFunc(name string, min, max int,
+	number func(x []float64) (interface{}, error),
+	dec func(x []decimal.Decimal) (interface{}, error),
+) func(arguments ...interface{}) (interface{}, error) {
+	return func(arguments ...interface{}) (interface{}, error) {
+		if len(arguments) < min || len(arguments) > max {
+			if min == max {
+				return nil, fmt.Errorf("%s() expects exactly %d number argument(s)", name, min)
+			}
+			return nil, fmt.Errorf("%s() expects %d to %d number arguments", name, min, max)
+		}
+
+		isDecimal := false
+		for _, a := range arguments {
+			if _, ok := a.(decimal.Decimal); ok {
+				isDecimal = true
+				break
+			}
+		}
+
+		if isDecimal {
+			x := make([]decimal.Decimal, len(arguments))
+			for i, a := range arguments {
+				d, ok := convertToDecimal(a)
+				if !ok {
+					return nil, fmt.Errorf("%s() unexpected %v(%T) expected number", name, a, a)
+				}
+				x[i] = d
+			}
+			return dec(x)
+		}
+
+		x := make([]float64, len(arguments))
+		for i, a := range arguments {
+			f, ok := convertToFloat(a)
+			if !ok {
+				return nil, fmt.Errorf("%s() unexpected %v(%T) expected number", name, a, a)
+			}
+			x[i] = f
+		}
+		return number(x)
+	}
+}
@@ -0,0 +1,149 @@
+package gval
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMath(t *testing.T) {
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "abs",
+				expression: "abs(-3.5)",
+				extension:  Math(),
+				want:       3.5,
+			},
+			{
+				name:       "ceil",
+				expression: "ceil(1.2)",
+				extension:  Math(),
+				want:       2.,
+			},
+			{
+				name:       "floor",
+				expression: "floor(-1.2)",
+				extension:  Math(),
+				want:       -2.,
+			},
+			{
+				name:       "round",
+				expression: "round(2.5)",
+				extension:  Math(),
+				want:       3.,
+			},
+			{
+				name:       "round places",
+				expression: "round(price * rate, 2)",
+				extension:  Math(),
+				parameter:  map[string]interface{}{"price": 10.0, "rate": 0.12345},
+				want:       1.23,
+			},
+			{
+				name:       "sqrt",
+				expression: "sqrt(16)",
+				extension:  Math(),
+				want:       4.,
+			},
+			{
+				name:       "log",
+				expression: "log(1)",
+				extension:  Math(),
+				want:       0.,
+			},
+			{
+				name:       "log base",
+				expression: "log(8, 2)",
+				extension:  Math(),
+				want:       3.,
+			},
+			{
+				name:       "pow",
+				expression: "pow(2, 10)",
+				extension:  Math(),
+				want:       1024.,
+			},
+			{
+				name:       "clamp",
+				expression: "[clamp(-1, 0, 10), clamp(5, 0, 10), clamp(11, 0, 10)]",
+				extension:  Math(),
+				want:       []interface{}{0., 5., 10.},
+			},
+			{
+				name:       "int parameter",
+				expression: "abs(x)",
+				extension:  Math(),
+				parameter:  map[string]interface{}{"x": -4},
+				want:       4.,
+			},
+			{
+				name:         "decimal round",
+				expression:   "round(x * 1.19, 2)",
+				extension:    NewLanguage(DecimalArithmetic(), Math()),
+				parameter:    map[string]interface{}{"x": decimal.RequireFromString("19.99")},
+				want:         decimal.RequireFromString("23.79"),
+				equalityFunc: decimalEqualityFunc,
+			},
+			{
+				name:         "decimal clamp",
+				expression:   "clamp(x, 0, 10)",
+				extension:    Math(),
+				parameter:    map[string]interface{}{"x": decimal.RequireFromString("12.5")},
+				want:         decimal.NewFromInt(10),
+				equalityFunc: decimalEqualityFunc,
+			},
+			{
+				name:         "decimal sqrt",
+				expression:   "sqrt(x)",
+				extension:    Math(),
+				parameter:    map[string]interface{}{"x": decimal.NewFromInt(9)},
+				want:         decimal.NewFromInt(3),
+				equalityFunc: decimalEqualityFunc,
+			},
+			{
+				name:         "decimal pow",
+				expression:   "pow(x, 3)",
+				extension:    Math(),
+				parameter:    map[string]interface{}{"x": decimal.RequireFromString("1.5")},
+				want:         decimal.RequireFromString("3.375"),
+				equalityFunc: decimalEqualityFunc,
+			},
+			{
+				name:         "decimal pow with fractional exponent",
+				expression:   "pow(x, 0.5)",
+				extension:    Math(),
+				parameter:    map[string]interface{}{"x": decimal.NewFromInt(2)},
+				want:         decimal.NewFromFloat(math.Sqrt2),
+				equalityFunc: decimalEqualityFunc,
+			},
+			{
+				name:       "decimal pow of negative number with fractional exponent",
+				expression: "pow(x, 0.5)",
+				extension:  Math(),
+				parameter:  map[string]interface{}{"x": decimal.NewFromInt(-2)},
+				wantErr:    "pow() of negative number -2 to the fractional power 0.5",
+			},
+			{
+				name:       "wrong argument count",
+				expression: "pow(2)",
+				extension:  Math(),
+				wantErr:    "pow() expects exactly 2 number argument(s)",
+			},
+			{
+				name:       "no number",
+				expression: `abs("a")`,
+				extension:  Math(),
+				wantErr:    "abs() unexpected a(string) expected number",
+			},
+			{
+				name:       "clamp inverted range",
+				expression: "clamp(1, 10, 0)",
+				extension:  Math(),
+				wantErr:    "clamp() min 10 is greater than max 0",
+			},
+		},
+		t,
+	)
+}
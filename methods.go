@@ -0,0 +1,54 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+)
+
+type allowedMethodsKey struct{}
+
+// AllowMethods returns a Language that only allows to call the given methods of parameters,
+// e.g. AllowMethods("IsActive") allows user.IsActive() but not user.Delete().
+// Without AllowMethods all exported methods can be called.
+// Functions given by the Language and func values of maps are not affected.
+func AllowMethods(names ...string) Language {
+	allowed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		allowed[name] = struct{}{}
+	}
+	l := newLanguage()
+	l.setOption(option{
+		name: "allowMethods",
+		wrap: func(eval Evaluable) Evaluable {
+			if eval.IsConst() {
+				return eval
+			}
+			return func(c context.Context, parameter interface{}) (interface{}, error) {
+				if c == nil {
+					c = context.Background()
+				}
+				return eval(context.WithValue(c, allowedMethodsKey{}, allowed), parameter)
+			}
+		},
+	})
+	return l
+}
+
+// methodByName returns the method of v named key including methods with pointer receiver.
+// It fails if the method is not allowed by AllowMethods.
+func methodByName(c context.Context, v reflect.Value, key string) (reflect.Value, bool) {
+	if c != nil {
+		if allowed, ok := c.Value(allowedMethodsKey{}).(map[string]struct{}); ok {
+			if _, ok := allowed[key]; !ok {
+				return reflect.Value{}, false
+			}
+		}
+	}
+	method := v.MethodByName(key)
+	if !method.IsValid() && v.Kind() != reflect.Ptr {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		method = p.MethodByName(key)
+	}
+	return method, method.IsValid()
+}
@@ -0,0 +1,119 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type methodUser struct {
+	Name   string
+	Active bool
+}
+
+func (u methodUser) IsActive() bool {
+	return u.Active
+}
+
+func (u *methodUser) Greeting(greeting string) string {
+	return greeting + " " + u.Name
+}
+
+func (u methodUser) Tenant(c context.Context, suffix string) (string, error) {
+	tenant, ok := c.Value(methodTenantKey{}).(string)
+	if !ok {
+		return "", fmt.Errorf("no tenant")
+	}
+	return tenant + suffix, nil
+}
+
+func (u methodUser) Initial(n int, upper bool) string {
+	initial := u.Name[:n]
+	if upper {
+		return strings.ToUpper(initial)
+	}
+	return initial
+}
+
+type methodTenantKey struct{}
+
+func TestMethods(t *testing.T) {
+	user := methodUser{Name: "ann", Active: true}
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "value receiver",
+				expression: `user.IsActive()`,
+				parameter:  map[string]interface{}{"user": user},
+				want:       true,
+			},
+			{
+				name:       "pointer receiver on value",
+				expression: `user.Greeting("hello")`,
+				parameter:  map[string]interface{}{"user": user},
+				want:       "hello ann",
+			},
+			{
+				name:       "pointer receiver on pointer",
+				expression: `user.Greeting("hi")`,
+				parameter:  map[string]interface{}{"user": &user},
+				want:       "hi ann",
+			},
+			{
+				name:       "allowed method",
+				expression: `user.IsActive()`,
+				extension:  NewLanguage(Full(), AllowMethods("IsActive")),
+				parameter:  map[string]interface{}{"user": user},
+				want:       true,
+			},
+			{
+				name:       "method not allowed",
+				expression: `user.Greeting("hi")`,
+				extension:  NewLanguage(Full(), AllowMethods("IsActive")),
+				parameter:  map[string]interface{}{"user": user},
+				wantErr:    "unknown parameter user.Greeting",
+			},
+			{
+				name:       "converted arguments",
+				expression: `user.Initial(2, true)`,
+				parameter:  map[string]interface{}{"user": user},
+				want:       "AN",
+			},
+			{
+				name:       "unconvertible argument",
+				expression: `user.Initial(1.5, true)`,
+				parameter:  map[string]interface{}{"user": user},
+				wantErr:    "failed to execute function 'user.Initial': reflect: Call using float64 as type int",
+			},
+			{
+				name:       "method of selected element",
+				expression: `users[0].Initial(1, false)`,
+				parameter:  map[string]interface{}{"users": []methodUser{user}},
+				want:       "a",
+			},
+			{
+				name:       "unconvertible argument of selected element",
+				expression: `users[0].Initial("1", false)`,
+				parameter:  map[string]interface{}{"users": []methodUser{user}},
+				wantErr:    "failed to execute function 'Initial'",
+			},
+			{
+				name:       "context without tenant",
+				expression: `user.Tenant("-eu")`,
+				parameter:  map[string]interface{}{"user": user},
+				wantErr:    "no tenant",
+			},
+		},
+		t,
+	)
+
+	c := context.WithValue(context.Background(), methodTenantKey{}, "acme")
+	got, err := EvaluateWithContext(c, `user.Tenant("-eu")`, map[string]interface{}{"user": user})
+	if err != nil {
+		t.Fatalf("EvaluateWithContext() error = %v", err)
+	}
+	if got != "acme-eu" {
+		t.Errorf("EvaluateWithContext() = %v, want acme-eu", got)
+	}
+}
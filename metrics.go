@@ -0,0 +1,57 @@
+package gval
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics receives the measurements of a Language registered by WithMetrics,
+// e.g. to export them as Prometheus counters and histograms.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Parsed is called after each expression is parsed with the duration of the parsing
+	// and the parsing error, nil if the expression is valid.
+	Parsed(duration time.Duration, err error)
+	// Evaluated is called after each evaluation with its duration and error.
+	Evaluated(duration time.Duration, err error)
+	// CacheLookup is called for each lookup in the cache with given name during evaluations.
+	// The Regex functions look up compiled patterns in the cache "regex".
+	CacheLookup(cache string, hit bool)
+}
+
+// WithMetrics returns a Language reporting the parsing and evaluation of its expressions to metrics.
+func WithMetrics(metrics Metrics) Language {
+	l := newLanguage()
+	l.setOption(option{
+		name:  "metrics",
+		value: metrics,
+		wrap: func(eval Evaluable) Evaluable {
+			return func(c context.Context, parameter interface{}) (interface{}, error) {
+				if c == nil {
+					c = context.Background()
+				}
+				start := time.Now()
+				v, err := eval(context.WithValue(c, metricsKey{}, metrics), parameter)
+				metrics.Evaluated(time.Since(start), err)
+				return v, err
+			}
+		},
+	})
+	return l
+}
+
+func (l Language) metrics() Metrics {
+	m, _ := l.optionValue("metrics").(Metrics)
+	return m
+}
+
+type metricsKey struct{}
+
+// contextMetrics returns the Metrics of the evaluation or nil.
+func contextMetrics(c context.Context) Metrics {
+	if c == nil {
+		return nil
+	}
+	m, _ := c.Value(metricsKey{}).(Metrics)
+	return m
+}
@@ -0,0 +1,75 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	mutex       sync.Mutex
+	parsed      int
+	parseErrors int
+	evaluated   int
+	evalErrors  int
+	lookups     map[bool]int
+}
+
+func (m *testMetrics) Parsed(duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.parsed++
+	if err != nil {
+		m.parseErrors++
+	}
+}
+
+func (m *testMetrics) Evaluated(duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.evaluated++
+	if err != nil {
+		m.evalErrors++
+	}
+}
+
+func (m *testMetrics) CacheLookup(cache string, hit bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if cache == "regex" {
+		m.lookups[hit]++
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	m := &testMetrics{lookups: map[bool]int{}}
+	lang := Full(Regex(), WithMetrics(m))
+
+	if _, err := lang.NewEvaluable("1 +"); err == nil {
+		t.Fatal("NewEvaluable() expected error")
+	}
+	eval, err := lang.NewEvaluable(`matches(name, "^metrics-[0-9]+$") && a / b > 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, parameter := range []interface{}{
+		map[string]interface{}{"name": "metrics-1", "a": 4, "b": 2},
+		map[string]interface{}{"name": "metrics-2", "a": 4, "b": "x"},
+	} {
+		eval(context.Background(), parameter)
+	}
+	program, err := lang.Compile(`a + 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := program.Evaluate(context.Background(), map[string]interface{}{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &testMetrics{parsed: 3, parseErrors: 1, evaluated: 3, evalErrors: 1, lookups: map[bool]int{false: 1, true: 1}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("metrics = %+v, want %+v", m, want)
+	}
+}
@@ -1,6 +1,8 @@
 package gval
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
@@ -133,3 +135,93 @@ func TestMissingFieldHandling(t *testing.T) {
 		}
 	})
 }
+
+func TestDefaultOnMissingField(t *testing.T) {
+	language := Full(WithMissingFieldBehavior(DefaultOnMissingField{
+		"order.discount": 0.,
+		"order.customer.tier": "basic",
+	}))
+	params := map[string]interface{}{
+		"order": map[string]interface{}{"total": 100.},
+	}
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "present field",
+				expression: "order.total",
+				extension:  language,
+				parameter:  params,
+				want:       100.,
+			},
+			{
+				name:       "default for missing field",
+				expression: "order.total - order.discount",
+				extension:  language,
+				parameter:  params,
+				want:       100.,
+			},
+			{
+				name:       "default for nested missing field",
+				expression: "order.customer.tier",
+				extension:  language,
+				parameter:  params,
+				want:       "basic",
+			},
+			{
+				name:       "missing field without default",
+				expression: "order.discout",
+				extension:  language,
+				parameter:  params,
+				wantErr:    "unknown parameter order.discout",
+			},
+		},
+		t,
+	)
+}
+
+func TestMissingFieldFunc(t *testing.T) {
+	var fetched []string
+	language := Full(WithMissingFieldBehavior(MissingFieldFunc(func(c context.Context, path []string) (interface{}, error) {
+		key := strings.Join(path, ".")
+		fetched = append(fetched, key)
+		if key == "customer.score" {
+			return 42., nil
+		}
+		return nil, fmt.Errorf("can not fetch %s", key)
+	})))
+	params := map[string]interface{}{
+		"customer": map[string]interface{}{"name": "ann"},
+	}
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "present field",
+				expression: `customer.name`,
+				extension:  language,
+				parameter:  params,
+				want:       "ann",
+			},
+			{
+				name:       "fetched field",
+				expression: `customer.score > 40`,
+				extension:  language,
+				parameter:  params,
+				want:       true,
+			},
+			{
+				name:       "failing fetch",
+				expression: `customer.age`,
+				extension:  language,
+				parameter:  params,
+				wantErr:    "can not fetch customer.age",
+			},
+		},
+		t,
+	)
+
+	if want := []string{"customer.score", "customer.age"}; !reflect.DeepEqual(fetched, want) {
+		t.Errorf("fetched %v, want %v", fetched, want)
+	}
+}
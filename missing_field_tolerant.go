@@ -2,9 +2,9 @@ package gval
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
@@ -117,6 +117,16 @@ func MissingFieldAsNil() Language {
 				return nil, err
 			}
 			for _, k := range keys {
+				if v, err = decoded(v, k); err != nil {
+					return nil, err
+				}
+				if r, ok, err := selectIndex(c, v, k); ok {
+					if err != nil {
+						return nil, err
+					}
+					v = r
+					continue
+				}
 				switch o := v.(type) {
 				case Selector:
 					v, err = o.SelectGVal(c, k)
@@ -125,7 +135,7 @@ func MissingFieldAsNil() Language {
 					}
 					continue
 				case map[interface{}]interface{}:
-					if val, exists := o[k]; exists {
+					if val, exists := interfaceMapValue(o, k); exists {
 						v = val
 					} else {
 						return nil, nil // Return nil instead of error for missing field
@@ -139,14 +149,14 @@ func MissingFieldAsNil() Language {
 					}
 					continue
 				case []interface{}:
-					if i, err := strconv.Atoi(k); err == nil && i >= 0 && len(o) > i {
+					if i, ok := sliceIndex(k, len(o)); ok {
 						v = o[i]
 						continue
 					}
 					return nil, nil // Return nil instead of error for missing array index
 				default:
 					var ok bool
-					v, ok = reflectSelect(k, o)
+					v, ok = reflectSelect(c, k, o)
 					if !ok {
 						return nil, nil // Return nil instead of error for missing field
 					}
@@ -230,5 +240,5 @@ func NilSafeComparison() Language {
 
 // isMissingFieldError checks if an error is due to a missing field
 func isMissingFieldError(err error) bool {
-	return err != nil && strings.Contains(err.Error(), "unknown parameter")
+	return errors.Is(err, ErrUnknownParameter) || err != nil && strings.Contains(err.Error(), "unknown parameter")
 }
@@ -0,0 +1,121 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Money is an amount of a currency, created by money(amount, currency) of MoneyArithmetic.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+}
+
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// MoneyArithmetic returns DecimalArithmetic with amounts of currencies:
+//
+//	money(amount, currency)   Money of the amount given as string or number, e.g. money("19.99", "EUR")
+//	+, -                      sum and difference of Money of the same currency
+//	*, /                      Money multiplied and divided by numbers, Money divided by Money is their ratio
+//	<, <=, >, >=, ==, !=      comparison of Money of the same currency
+//
+// Operations on Money of different currencies or on Money and numbers like money("1", "EUR") + 1 fail.
+// The amount and currency of Money m are selected as m.Amount and m.Currency.
+func MoneyArithmetic() Language {
+	return moneyArithmetic
+}
+
+var moneyArithmetic = func() Language {
+	languages := []Language{
+		decimalArithmetic,
+		Function("money", func(amount interface{}, currency string) (Money, error) {
+			var d decimal.Decimal
+			var err error
+			if s, ok := amount.(string); ok {
+				d, err = decimal.NewFromString(s)
+			} else if n, ok := convertToDecimal(amount); ok {
+				d = n
+			} else {
+				err = fmt.Errorf("unexpected amount %v(%T) expected number", amount, amount)
+			}
+			if err != nil {
+				return Money{}, err
+			}
+			if currency = strings.ToUpper(strings.TrimSpace(currency)); currency == "" {
+				return Money{}, fmt.Errorf("money() expects a currency")
+			}
+			return Money{Amount: d, Currency: currency}, nil
+		}, Pure()),
+		PrefixOperator("-", func(c context.Context, v interface{}) (interface{}, error) {
+			if m, ok := v.(Money); ok {
+				return Money{Amount: m.Amount.Neg(), Currency: m.Currency}, nil
+			}
+			d, ok := convertToDecimal(v)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %v(%T) expected number", v, v)
+			}
+			return d.Neg(), nil
+		}),
+	}
+	for name, f := range map[string]func(a, b Money) interface{}{
+		"+":  func(a, b Money) interface{} { return Money{Amount: a.Amount.Add(b.Amount), Currency: a.Currency} },
+		"-":  func(a, b Money) interface{} { return Money{Amount: a.Amount.Sub(b.Amount), Currency: a.Currency} },
+		"*":  nil,
+		"/":  func(a, b Money) interface{} { return a.Amount.Div(b.Amount) },
+		"<":  func(a, b Money) interface{} { return a.Amount.LessThan(b.Amount) },
+		"<=": func(a, b Money) interface{} { return a.Amount.LessThanOrEqual(b.Amount) },
+		">":  func(a, b Money) interface{} { return a.Amount.GreaterThan(b.Amount) },
+		">=": func(a, b Money) interface{} { return a.Amount.GreaterThanOrEqual(b.Amount) },
+		"==": func(a, b Money) interface{} { return a.Amount.Equal(b.Amount) },
+		"!=": func(a, b Money) interface{} { return !a.Amount.Equal(b.Amount) },
+	} {
+		languages = append(languages, InfixOperator(name, moneyOperator(name, f)))
+	}
+	return NewLanguage(languages...)
+}()
+
+// moneyOperator returns the operator applying f to Money of the same currency, f is nil for *.
+// Money multiplied or divided by numbers is handled by * and /. Division by zero fails.
+// Other operands fail like in DecimalArithmetic, == and != compare them deeply.
+func moneyOperator(name string, f func(a, b Money) interface{}) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		x, mx := a.(Money)
+		y, my := b.(Money)
+		switch {
+		case mx && my && f != nil:
+			if x.Currency != y.Currency {
+				return nil, fmt.Errorf("invalid operation %s %s %s of different currencies", x, name, y)
+			}
+			if name == "/" && y.Amount.IsZero() {
+				return nil, fmt.Errorf("invalid operation %s / %s division by zero", x, y)
+			}
+			return f(x, y), nil
+		case mx && !my && (name == "*" || name == "/"):
+			if n, ok := convertToDecimal(b); ok {
+				if name == "*" {
+					return Money{Amount: x.Amount.Mul(n), Currency: x.Currency}, nil
+				}
+				if n.IsZero() {
+					return nil, fmt.Errorf("invalid operation %s / %v division by zero", x, b)
+				}
+				return Money{Amount: x.Amount.Div(n), Currency: x.Currency}, nil
+			}
+		case my && !mx && name == "*":
+			if n, ok := convertToDecimal(a); ok {
+				return Money{Amount: n.Mul(y.Amount), Currency: y.Currency}, nil
+			}
+		case name == "==":
+			return reflect.DeepEqual(a, b), nil
+		case name == "!=":
+			return !reflect.DeepEqual(a, b), nil
+		}
+		return nil, fmt.Errorf("invalid operation (%T) %s (%T)", a, name, b)
+	}
+}
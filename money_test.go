@@ -0,0 +1,72 @@
+package gval
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	l := NewLanguage(PropositionalLogic(), MoneyArithmetic())
+	parameter := map[string]interface{}{
+		"price": Money{Amount: decimal.RequireFromString("19.99"), Currency: "EUR"},
+		"fee":   Money{Amount: decimal.RequireFromString("1.5"), Currency: "USD"},
+	}
+	eur := func(s string) Money { return Money{Amount: decimal.RequireFromString(s), Currency: "EUR"} }
+	for _, tt := range []struct {
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{expression: `money("19.99", "eur")`, want: eur("19.99")},
+		{expression: `price + money(0.01, "EUR")`, want: eur("20")},
+		{expression: `price - money("9.99", "EUR")`, want: eur("10")},
+		{expression: `price * 3`, want: eur("59.97")},
+		{expression: `2 * price / 4`, want: eur("9.995")},
+		{expression: `-price`, want: eur("-19.99")},
+		{expression: `price / money("10", "EUR")`, want: decimal.RequireFromString("1.999")},
+		{expression: `price > money(10, "EUR") && price == money("19.990", "EUR") && price != money(1, "EUR")`, want: true},
+		{expression: `price.Currency + " " + price.Amount`, wantErr: "invalid operation"},
+		{expression: `price == nil`, want: false},
+		{expression: `1.5 + 2`, want: decimal.RequireFromString("3.5")},
+		{expression: `price + fee`, wantErr: "invalid operation 19.99 EUR + 1.5 USD of different currencies"},
+		{expression: `price > fee`, wantErr: "of different currencies"},
+		{expression: `price == fee`, wantErr: "of different currencies"},
+		{expression: `price + 1`, wantErr: "invalid operation (gval.Money) + (decimal.Decimal)"},
+		{expression: `price * fee`, wantErr: "invalid operation (gval.Money) * (gval.Money)"},
+		{expression: `money("10", "EUR") / 0`, wantErr: "invalid operation 10 EUR / 0 division by zero"},
+		{expression: `money("10", "EUR") / money("0.00", "EUR")`, wantErr: "invalid operation 10 EUR / 0 EUR division by zero"},
+		{expression: `1 / 0`, wantErr: "invalid operation 1 / 0 division by zero"},
+		{expression: `price.Amount / 0`, wantErr: "invalid operation 19.99 / 0 division by zero"},
+		{expression: `5 % 0`, wantErr: "invalid operation 5 % 0 division by zero"},
+		{expression: `money("x", "EUR")`, wantErr: "can't convert x to decimal"},
+		{expression: `money(1, " ")`, wantErr: "money() expects a currency"},
+	} {
+		got, err := l.Evaluate(tt.expression, parameter)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Evaluate(%s) = %v, %v, want error %s", tt.expression, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", tt.expression, err)
+			continue
+		}
+		switch want := tt.want.(type) {
+		case Money:
+			if m, ok := got.(Money); !ok || m.Currency != want.Currency || !m.Amount.Equal(want.Amount) {
+				t.Errorf("Evaluate(%s) = %v, want %v", tt.expression, got, want)
+			}
+		case decimal.Decimal:
+			if d, ok := got.(decimal.Decimal); !ok || !d.Equal(want) {
+				t.Errorf("Evaluate(%s) = %v, want %v", tt.expression, got, want)
+			}
+		default:
+			if got != want {
+				t.Errorf("Evaluate(%s) = %v, want %v", tt.expression, got, want)
+			}
+		}
+	}
+}
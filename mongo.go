@@ -0,0 +1,215 @@
+package gval
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ToMongoFilter returns the MongoDB query filter of a syntax tree created by Language.Parse,
+// so documents can be filtered by the database instead of fetching and evaluating all of them.
+// The filter is a map[string]interface{}, the type underlying bson.M.
+//
+// Supported are comparisons of variables with constants by ==, !=, <, <=, >, >=, in, between,
+// regular expressions (=~ and !~), the text operators sw, co, ew, eqi, swi, coi and ewi,
+// the custom filters cfa and cfm with constant arguments and their combinations by &&, || and !.
+// A variable alone matches documents where it is true.
+// Regular expressions are passed to MongoDB unchanged, so they are interpreted by its PCRE engine.
+func ToMongoFilter(ast *Node) (map[string]interface{}, error) {
+	if ast == nil {
+		return nil, fmt.Errorf("no syntax tree")
+	}
+	return ast.mongoFilter()
+}
+
+// mongoComparisons maps comparison operators to MongoDB query operators.
+var mongoComparisons = map[string]string{
+	"==": "$eq", "!=": "$ne", "<": "$lt", "<=": "$lte", ">": "$gt", ">=": "$gte",
+}
+
+// mirroredComparisons maps comparison operators to the operator of swapped operands.
+var mirroredComparisons = map[string]string{
+	"==": "==", "!=": "!=", "<": ">", "<=": ">=", ">": "<", ">=": "<=",
+}
+
+func (n *Node) mongoFilter() (map[string]interface{}, error) {
+	if n.constant {
+		if n.Value == true {
+			return map[string]interface{}{}, nil
+		}
+		return nil, fmt.Errorf("%s can not be converted to a MongoDB filter", n.Text())
+	}
+	switch n.Kind {
+	case VariableNode:
+		return map[string]interface{}{strings.Join(n.Path, "."): true}, nil
+	case OperandNode:
+		if len(n.Children) == 1 && strings.HasPrefix(n.Text(), "!") {
+			f, err := n.Children[0].mongoFilter()
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"$nor": []interface{}{f}}, nil
+		}
+	case InfixNode:
+		switch n.Operator {
+		case "&&", "||":
+			return n.mongoLogical()
+		}
+		a, b := n.Children[0], n.Children[1]
+		if v, ok := a.constantValue(); ok && b.Kind == VariableNode && mongoComparisons[n.Operator] != "" {
+			return mongoCondition(b, mirroredComparisons[n.Operator], v, a.Text())
+		}
+		if v, ok := b.constantValue(); ok && a.Kind == VariableNode {
+			return mongoCondition(a, n.Operator, v, b.Text())
+		}
+	}
+	return nil, fmt.Errorf("%s can not be converted to a MongoDB filter", n.Text())
+}
+
+// mongoLogical returns the filter of a chain of && or || operations.
+func (n *Node) mongoLogical() (map[string]interface{}, error) {
+	op := "$and"
+	if n.Operator == "||" {
+		op = "$or"
+	}
+	filters := []interface{}{}
+	for _, child := range n.Children {
+		f, err := child.mongoFilter()
+		if err != nil {
+			return nil, err
+		}
+		// a && b && c is written as one $and
+		if nested, ok := f[op].([]interface{}); ok && len(f) == 1 && child.Kind == InfixNode && child.Operator == n.Operator {
+			filters = append(filters, nested...)
+			continue
+		}
+		filters = append(filters, f)
+	}
+	return map[string]interface{}{op: filters}, nil
+}
+
+// constantValue returns the value of a constant or of an array of constants.
+func (n *Node) constantValue() (interface{}, bool) {
+	if n.constant {
+		return n.Value, true
+	}
+	if n.Kind != OperandNode || !strings.HasPrefix(n.Text(), "[") {
+		return nil, false
+	}
+	values := make([]interface{}, len(n.Children))
+	for i, child := range n.Children {
+		v, ok := child.constantValue()
+		if !ok {
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
+
+// mongoCondition returns the filter comparing field with the constant v by operator.
+// text is the source of v for error messages.
+func mongoCondition(field *Node, operator string, v interface{}, text string) (map[string]interface{}, error) {
+	name := strings.Join(field.Path, ".")
+	var condition interface{}
+	var err error
+	switch operator {
+	case "in":
+		values, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("in expects an array but got %s", text)
+		}
+		condition = map[string]interface{}{"$in": values}
+	case "between":
+		bounds, ok := v.([]interface{})
+		if !ok || len(bounds) != 2 {
+			return nil, fmt.Errorf("between expects [min, max] but got %s", text)
+		}
+		condition = map[string]interface{}{"$gte": bounds[0], "$lte": bounds[1]}
+	case "=~", "!~":
+		pattern, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s expects a string pattern but got %s", operator, text)
+		}
+		condition = map[string]interface{}{"$regex": pattern}
+		if operator == "!~" {
+			condition = map[string]interface{}{"$not": condition}
+		}
+	case "cfa":
+		// cfa matches arrays with an element matching [value, operator]
+		args, ok := v.([]interface{})
+		if !ok || len(args) < 2 {
+			return nil, fmt.Errorf("cfa expects [value, operator] but got %s", text)
+		}
+		target, _ := args[0].(string)
+		op, _ := args[1].(string)
+		condition, err = mongoTextCondition(op, target)
+		if err == nil {
+			condition = map[string]interface{}{"$elemMatch": condition}
+		}
+	case "cfm":
+		// cfm matches arrays of objects with an object whose field matches [field, operator, value]
+		args, ok := v.([]interface{})
+		if !ok || len(args) < 3 {
+			return nil, fmt.Errorf("cfm expects [field, operator, value] but got %s", text)
+		}
+		key, _ := args[0].(string)
+		op, _ := args[1].(string)
+		target, _ := args[2].(string)
+		condition, err = mongoTextCondition(op, target)
+		if err == nil {
+			condition = map[string]interface{}{"$elemMatch": map[string]interface{}{key: condition}}
+		}
+	default:
+		if op, ok := mongoComparisons[operator]; ok {
+			condition = v
+			if op != "$eq" {
+				condition = map[string]interface{}{op: v}
+			}
+			break
+		}
+		target, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("operator %s can not be converted to a MongoDB filter", operator)
+		}
+		condition, err = mongoTextCondition(operator, target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{name: condition}, nil
+}
+
+// mongoTextCondition returns the query operator matching strings by operator like matchesCondition.
+func mongoTextCondition(operator, target string) (map[string]interface{}, error) {
+	quoted := regexp.QuoteMeta(target)
+	regex := func(pattern string, ignoreCase bool) map[string]interface{} {
+		if ignoreCase {
+			return map[string]interface{}{"$regex": pattern, "$options": "i"}
+		}
+		return map[string]interface{}{"$regex": pattern}
+	}
+	switch operator {
+	case "equal", "eq", "==":
+		return map[string]interface{}{"$eq": target}, nil
+	case "notequal", "ne", "!=":
+		return map[string]interface{}{"$ne": target}, nil
+	case "startswith", "sw":
+		return regex("^"+quoted, false), nil
+	case "endswith", "ew":
+		return regex(quoted+"$", false), nil
+	case "contains", "co":
+		return regex(quoted, false), nil
+	case "equalignorecase", "eqi":
+		return regex("^"+quoted+"$", true), nil
+	case "notequalignorecase", "nei":
+		return map[string]interface{}{"$not": regex("^"+quoted+"$", true)}, nil
+	case "startswithignorecase", "swi":
+		return regex("^"+quoted, true), nil
+	case "endswithignorecase", "ewi":
+		return regex(quoted+"$", true), nil
+	case "containsignorecase", "coi":
+		return regex(quoted, true), nil
+	}
+	return nil, fmt.Errorf("operator %s can not be converted to a MongoDB filter", operator)
+}
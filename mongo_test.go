@@ -0,0 +1,50 @@
+package gval
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToMongoFilter(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+		wantErr    bool
+	}{
+		{expression: `status == "active"`, want: `{"status":"active"}`},
+		{expression: `10 < age`, want: `{"age":{"$gt":10}}`},
+		{expression: `a.b != nil`, want: `{"a.b":{"$ne":null}}`},
+		{expression: `age >= 18 && age < 65 && country in ["DE", "AT"]`, want: `{"$and":[{"age":{"$gte":18}},{"age":{"$lt":65}},{"country":{"$in":["DE","AT"]}}]}`},
+		{expression: `vip || (score between [1, 5] || !blocked)`, want: `{"$or":[{"vip":true},{"score":{"$gte":1,"$lte":5}},{"$nor":[{"blocked":true}]}]}`},
+		{expression: `name =~ "^a.*z$"`, want: `{"name":{"$regex":"^a.*z$"}}`},
+		{expression: `name !~ "x"`, want: `{"name":{"$not":{"$regex":"x"}}}`},
+		{expression: `name sw "a.b"`, want: `{"name":{"$regex":"^a\\.b"}}`},
+		{expression: `name coi "x"`, want: `{"name":{"$options":"i","$regex":"x"}}`},
+		{expression: `tags cfa ["go", "==" ]`, want: `{"tags":{"$elemMatch":{"$eq":"go"}}}`},
+		{expression: `packages cfm ["name", "ew", "Plan"]`, want: `{"packages":{"$elemMatch":{"name":{"$regex":"Plan$"}}}}`},
+		{expression: `true`, want: `{}`},
+		{expression: `a == b`, wantErr: true},
+		{expression: `a + 1 > 2`, wantErr: true},
+		{expression: `tags cfa ["go", "unknown"]`, wantErr: true},
+		{expression: `date(a)`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			node, err := Full().Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%s) error = %v", tt.expression, err)
+			}
+			filter, err := ToMongoFilter(node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToMongoFilter(%s) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := json.Marshal(filter)
+			if string(got) != tt.want {
+				t.Errorf("ToMongoFilter(%s) = %s, want %s", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package gval
+
+import (
+	"context"
+	"sync"
+)
+
+// MutableLanguage is a Language whose functions, constants and operators can be changed at runtime.
+// It can be used and changed concurrently.
+//
+// Changes create a new Language and never modify a Language in use,
+// so every expression is parsed by a consistent snapshot.
+// Evaluables parsed before a change keep the functions and operators they were parsed with.
+type MutableLanguage struct {
+	mutex    sync.RWMutex
+	language Language
+}
+
+// NewMutableLanguage returns a MutableLanguage starting with the union of given Languages.
+func NewMutableLanguage(bases ...Language) *MutableLanguage {
+	return &MutableLanguage{language: NewLanguage(bases...)}
+}
+
+// Language returns a snapshot of the current Language.
+// The snapshot is not affected by later changes.
+func (m *MutableLanguage) Language() Language {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.language
+}
+
+// Add adds the functions, constants and operators of given Languages.
+// They replace existing ones with the same name.
+func (m *MutableLanguage) Add(extensions ...Language) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.language = NewLanguage(append([]Language{m.language}, extensions...)...)
+}
+
+// Remove removes the functions, constants and operators with given names.
+func (m *MutableLanguage) Remove(names ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.language = m.language.Without(names...)
+}
+
+// NewEvaluable returns an Evaluable for given expression in the current Language.
+func (m *MutableLanguage) NewEvaluable(expression string) (Evaluable, error) {
+	return m.Language().NewEvaluable(expression)
+}
+
+// NewEvaluableWithContext returns an Evaluable for given expression in the current Language using context.
+func (m *MutableLanguage) NewEvaluableWithContext(c context.Context, expression string) (Evaluable, error) {
+	return m.Language().NewEvaluableWithContext(c, expression)
+}
+
+// Evaluate given parameter with given expression in the current Language.
+func (m *MutableLanguage) Evaluate(expression string, parameter interface{}) (interface{}, error) {
+	return m.Language().Evaluate(expression, parameter)
+}
+
+// EvaluateWithContext given parameter with given expression in the current Language using context.
+func (m *MutableLanguage) EvaluateWithContext(c context.Context, expression string, parameter interface{}) (interface{}, error) {
+	return m.Language().EvaluateWithContext(c, expression, parameter)
+}
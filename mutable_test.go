@@ -0,0 +1,78 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestMutableLanguage(t *testing.T) {
+	m := NewMutableLanguage(Full())
+
+	if _, err := m.Evaluate("tenant()", nil); err == nil {
+		t.Fatal("Evaluate(tenant()) expected error for unknown function")
+	}
+
+	m.Add(Function("tenant", func() string { return "a" }), Constant("limit", 10.))
+	snapshot := m.Language()
+	got, err := m.Evaluate(`tenant() + limit`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "a10" {
+		t.Errorf("Evaluate() = %v, want a10", got)
+	}
+
+	eval, err := m.NewEvaluable("tenant()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Add(Function("tenant", func() string { return "b" }))
+	if got, _ := m.Evaluate("tenant()", nil); got != "b" {
+		t.Errorf("Evaluate() after replacing function = %v, want b", got)
+	}
+	if got, _ := eval(context.Background(), nil); got != "a" {
+		t.Errorf("Evaluable parsed before change = %v, want a", got)
+	}
+
+	m.Remove("tenant", "**")
+	if _, err := m.Evaluate("tenant()", nil); err == nil {
+		t.Error("Evaluate(tenant()) expected error after removing function")
+	}
+	if _, err := m.Evaluate("2 ** 3", nil); err == nil {
+		t.Error("Evaluate(2 ** 3) expected error after removing operator")
+	}
+	if got, _ := m.Evaluate("2 * 3", nil); got != 6. {
+		t.Errorf("Evaluate(2 * 3) = %v, want 6", got)
+	}
+	if got, _ := snapshot.Evaluate("tenant() + 2 ** 3", nil); got != "a8" {
+		t.Errorf("snapshot.Evaluate() = %v, want a8", got)
+	}
+}
+
+func TestMutableLanguage_concurrent(t *testing.T) {
+	m := NewMutableLanguage(Full())
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			m.Add(Constant(fmt.Sprintf("c%d", i), float64(i)))
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Evaluate("1 + 2", nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	got, err := m.Evaluate("c0 + c9", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 9. {
+		t.Errorf("Evaluate(c0 + c9) = %v, want 9", got)
+	}
+}
@@ -0,0 +1,80 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrParameterMutated is matched by errors.Is for all MutationErrors.
+var ErrParameterMutated = errors.New("parameter mutated")
+
+// MutationError reports the node of an expression that modified the parameter while it was evaluated.
+type MutationError struct {
+	// Node is the innermost node whose evaluation modified the parameter,
+	// e.g. the infix node of a cfa ["x", "eq"] for an operator changing a in place.
+	Node *Node
+}
+
+func (err *MutationError) Error() string {
+	switch err.Node.Kind {
+	case InfixNode, PostfixNode:
+		return fmt.Sprintf("operator %s of %s modified the parameter", err.Node.Operator, err.Node.Text())
+	}
+	return fmt.Sprintf("%s modified the parameter", err.Node.Text())
+}
+
+// Is returns true for ErrParameterMutated.
+func (err *MutationError) Is(target error) bool {
+	return target == ErrParameterMutated
+}
+
+// DetectMutations returns a Language whose Evaluables fail with a *MutationError
+// if an operator or function modifies the arrays or maps of the parameter, e.g. to certify
+// custom operators as free of side effects in tests before they are used concurrently.
+// The parameter is copied and compared before and after each node is evaluated,
+// which makes evaluations much slower. Modifications of structs and pointers are not detected.
+func DetectMutations() Language {
+	l := WithObserver(nil)
+	l.setOption(option{
+		name: "detectMutations",
+		wrap: func(eval Evaluable) Evaluable {
+			return func(c context.Context, parameter interface{}) (interface{}, error) {
+				if c == nil {
+					c = context.Background()
+				}
+				d := &mutationDetector{parameter: parameter}
+				v, err := eval(ContextWithObserver(c, d), parameter)
+				if d.err != nil {
+					return nil, d.err
+				}
+				return v, err
+			}
+		},
+	})
+	return l
+}
+
+// mutationDetector is the EvaluationObserver comparing the parameter before and after each node.
+// The last snapshot is the copy of the parameter before the node evaluated currently.
+type mutationDetector struct {
+	parameter interface{}
+	snapshots []interface{}
+	err       error
+}
+
+func (d *mutationDetector) OnEnterNode(c context.Context, n *Node) {
+	d.snapshots = append(d.snapshots, deepCopy(d.parameter))
+}
+
+func (d *mutationDetector) OnExitNode(c context.Context, n *Node, value interface{}, err error) {
+	snapshot := d.snapshots[len(d.snapshots)-1]
+	d.snapshots = d.snapshots[:len(d.snapshots)-1]
+	if d.err == nil && !reflect.DeepEqual(snapshot, d.parameter) {
+		d.err = &MutationError{Node: n}
+	}
+}
+
+func (d *mutationDetector) OnFunctionCall(c context.Context, name string, arguments []interface{}, value interface{}, err error) {
+}
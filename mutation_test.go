@@ -0,0 +1,37 @@
+package gval
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDetectMutations(t *testing.T) {
+	appendTo := Function("appendTo", func(m map[string]interface{}, key string) bool {
+		m[key] = true
+		return true
+	})
+	l := Full(appendTo, DetectMutations())
+	newParameter := func() map[string]interface{} {
+		return map[string]interface{}{
+			"pairs": [][]interface{}{{"a"}, {"b"}},
+			"m":     map[string]interface{}{},
+			"n":     1.,
+		}
+	}
+	for expression, want := range map[string]string{
+		`pairs cfa ["b", "eq"]`:         `operator cfa of pairs cfa ["b", "eq"] modified the parameter`,
+		`n > 0 && appendTo(m, "x")`:     `appendTo(m, "x") modified the parameter`,
+		`[1, appendTo(m, "x") ? 2 : 3]`: `appendTo(m, "x") modified the parameter`,
+	} {
+		_, err := l.Evaluate(expression, newParameter())
+		var m *MutationError
+		if !errors.Is(err, ErrParameterMutated) || !errors.As(err, &m) || m.Error() != want {
+			t.Errorf("Evaluate(%s) error = %v, want %s", expression, err, want)
+		}
+	}
+	for _, expression := range []string{`pairs cfa ["a", "eq"]`, `n + 1 > 1 && len(m) == 0`, `{"m": m, "x": pairs[0]}`} {
+		if _, err := l.Evaluate(expression, newParameter()); err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package gval
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+)
+
+// Network returns a Language with IP addresses and networks:
+//
+//	ip(s)                         net.IP of the address s like "10.1.2.3" or "::1"
+//	cidr(s)                       *net.IPNet of the network s like "10.0.0.0/8"
+//	a in cidr(s)                  true iff the network contains address a
+//	a in [cidr(s), cidr(t)]       true iff one of the networks contains address a
+//	isPrivate(a), isLoopback(a)   true iff a is a private (RFC 1918, RFC 4193) or loopback address
+//	<, <=, >, >=, ==, !=          comparison of addresses
+//
+// Addresses are net.IP values or strings, which are parsed if the other operand is an address or network.
+// ip and cidr are variables unless they are called, so ip in cidr("10.0.0.0/8") tests the variable ip.
+//
+// The values are of package net rather than net/netip, which requires Go 1.18 while this module supports Go 1.15.
+// netip.Addr parameters are not recognized as addresses, but their strings are.
+func Network() Language {
+	return network
+}
+
+var network = func() Language {
+	languages := []Language{
+		builtin(Function("ip", func(s string) (net.IP, error) {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("ip() invalid address %s", s)
+			}
+			return ip, nil
+		}, Pure()), "ip"),
+		builtin(Function("cidr", func(s string) (*net.IPNet, error) {
+			_, n, err := net.ParseCIDR(s)
+			return n, err
+		}, Pure()), "cidr"),
+		Function("isPrivate", func(a interface{}) (bool, error) {
+			ip, ok := toIP(a)
+			if !ok {
+				return false, fmt.Errorf("isPrivate() unexpected %v(%T) expected address", a, a)
+			}
+			for _, n := range privateNetworks {
+				if n.Contains(ip) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}, Pure()),
+		Function("isLoopback", func(a interface{}) (bool, error) {
+			ip, ok := toIP(a)
+			if !ok {
+				return false, fmt.Errorf("isLoopback() unexpected %v(%T) expected address", a, a)
+			}
+			return ip.IsLoopback(), nil
+		}, Pure()),
+		InfixContextOperator("in", func(c context.Context, a, b interface{}) (interface{}, error) {
+			if n, ok := b.(*net.IPNet); ok {
+				ip, ok := toIP(a)
+				return ok && n.Contains(ip), nil
+			}
+			if networks, ok := b.([]interface{}); ok {
+				if ip, ok := toIP(a); ok {
+					for i, n := range networks {
+						if err := CheckContext(c, i); err != nil {
+							return nil, err
+						}
+						if n, ok := n.(*net.IPNet); ok && n.Contains(ip) {
+							return true, nil
+						}
+					}
+				}
+			}
+			return inArray(c, a, b)
+		}),
+	}
+	for name, f := range comparisons {
+		languages = append(languages, typedInfixOperator(name, ipComparison(name, f)))
+	}
+	return NewLanguage(languages...)
+}()
+
+var privateNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, s := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"} {
+		_, n, _ := net.ParseCIDR(s)
+		networks = append(networks, n)
+	}
+	return networks
+}()
+
+// toIP returns the address of a net.IP or a string.
+func toIP(a interface{}) (net.IP, bool) {
+	switch a := a.(type) {
+	case net.IP:
+		return a, a != nil
+	case string:
+		ip := net.ParseIP(a)
+		return ip, ip != nil
+	}
+	return nil, false
+}
+
+// ipComparison returns the comparison of operands if one is a net.IP.
+// Other operands than addresses fail, but are compared deeply by == and !=.
+func ipComparison(name string, f func(cmp int) bool) typedOpFunc {
+	return func(a, b interface{}) (interface{}, bool, error) {
+		_, ipa := a.(net.IP)
+		_, ipb := b.(net.IP)
+		if !ipa && !ipb {
+			return nil, false, nil
+		}
+		x, okx := toIP(a)
+		y, oky := toIP(b)
+		if !okx || !oky {
+			if name == "==" || name == "!=" {
+				return nil, false, nil
+			}
+			return nil, true, fmt.Errorf("invalid operation (%T) %s (%T)", a, name, b)
+		}
+		return f(bytes.Compare(x.To16(), y.To16())), true, nil
+	}
+}
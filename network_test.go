@@ -0,0 +1,28 @@
+package gval
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetwork(t *testing.T) {
+	l := NewLanguage(Full(), Network())
+	parameter := map[string]interface{}{"ip": "10.1.2.3", "addr": net.ParseIP("192.168.0.1"), "cidr": "variable"}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "in cidr", expression: `ip in cidr("10.0.0.0/8") && !(ip in cidr("10.2.0.0/16"))`, extension: l, parameter: parameter, want: true},
+			{name: "in networks", expression: `addr in [cidr("10.0.0.0/8"), cidr("192.168.0.0/24")]`, extension: l, parameter: parameter, want: true},
+			{name: "ipv6", expression: `"fd00::1" in cidr("fc00::/7") && !("::1" in cidr("fc00::/7"))`, extension: l, want: true},
+			{name: "in array", expression: `2 in [1, 2] && !("x" in cidr("10.0.0.0/8"))`, extension: l, want: true},
+			{name: "private", expression: `[isPrivate(ip), isPrivate("8.8.8.8"), isPrivate(addr), isLoopback("127.0.0.1")]`, extension: l, parameter: parameter, want: []interface{}{true, false, true, true}},
+			{name: "comparison", expression: `ip("10.0.0.9") < "10.0.0.10" && ip(ip) >= "10.1.2.3" && addr == "192.168.0.1" && ip("::ffff:10.1.2.3") == ip(ip)`, extension: l, parameter: parameter, want: true},
+			{name: "string comparison", expression: `"10.0.0.9" < "10.0.0.10"`, extension: l, want: false},
+			{name: "comparison with other types", expression: `addr == 1 || addr != 1 && 1 == 1`, extension: l, parameter: parameter, want: true},
+			{name: "variables named like functions", expression: `cidr + "!"`, extension: l, parameter: parameter, want: "variable!"},
+			{name: "invalid address", expression: `ip("10.0.0.256")`, extension: l, wantErr: "ip() invalid address 10.0.0.256"},
+			{name: "invalid network", expression: `cidr("10.0.0.0/33")`, extension: l, wantErr: "invalid CIDR address"},
+			{name: "invalid comparison", expression: `addr < 1`, extension: l, parameter: parameter, wantErr: "invalid operation (net.IP) < (float64)"},
+		},
+		t,
+	)
+}
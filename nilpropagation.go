@@ -0,0 +1,40 @@
+package gval
+
+// nilPropagatingOperators are the operators of NilPropagation() without arguments.
+var nilPropagatingOperators = []string{"+", "-", "*", "/", "%", "**", "<", "<=", ">", ">="}
+
+// NilPropagation returns a Language whose given infix operators evaluate to nil instead of failing
+// if an operand is nil, so sparse parameters can be defaulted downstream, e.g. (price * quantity) ?? 0.
+// Without operators it applies to the arithmetic operators +, -, *, /, %, ** and the comparisons <, <=, > and >=.
+// == and != are not affected by default, so x == nil still tests for nil.
+func NilPropagation(operators ...string) Language {
+	if len(operators) == 0 {
+		operators = nilPropagatingOperators
+	}
+	propagating := make(map[string]struct{}, len(operators))
+	for _, op := range operators {
+		propagating[op] = struct{}{}
+	}
+	l := newLanguage()
+	l.setOption(option{name: "nilPropagation", value: propagating})
+	return l
+}
+
+// propagatesNil returns if the operator op evaluates to nil for nil operands.
+func (l Language) propagatesNil(op string) bool {
+	propagating, _ := l.optionValue("nilPropagation").(map[string]struct{})
+	_, ok := propagating[op]
+	return ok
+}
+
+// propagatingNil returns a copy of op evaluating to nil if an operand is nil.
+func (op infix) propagatingNil() *infix {
+	f := op.f
+	op.setF(func(a, b interface{}) (interface{}, error) {
+		if a == nil || b == nil {
+			return nil, nil
+		}
+		return f(a, b)
+	})
+	return &op
+}
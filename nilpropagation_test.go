@@ -0,0 +1,42 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestNilPropagation(t *testing.T) {
+	parameter := map[string]interface{}{"price": 2., "quantity": nil, "name": nil}
+	l := Full(NilPropagation(), WithMissingFieldBehavior(NilOnMissingField))
+	for expression, want := range map[string]interface{}{
+		"price * quantity":               nil,
+		"(price * quantity) ?? 0":        0.,
+		"price * 3 ?? 0":                 6.,
+		"missing.field + 1 ?? -1":        -1.,
+		"quantity > 1":                   nil,
+		"(quantity > 1) ?? false":        false,
+		"1 < quantity < 3":               nil,
+		"quantity == nil":                true,
+		"quantity != price":              true,
+		"price ** 2 - quantity ?? price": 2.,
+		`"a" + name ?? "none"`:           "none",
+		"price / 0 > 1":                  true,
+	} {
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+
+	l = Full(NilPropagation("*"))
+	if got, err := l.Evaluate("quantity * 2", parameter); err != nil || got != nil {
+		t.Errorf("Evaluate(quantity * 2) = %v, %v, want nil", got, err)
+	}
+	if _, err := l.Evaluate("quantity + 2", parameter); err == nil {
+		t.Error("Evaluate(quantity + 2) expected error")
+	}
+	if _, err := Full().Evaluate("quantity * 2", parameter); err == nil {
+		t.Error("Evaluate(quantity * 2) without NilPropagation expected error")
+	}
+}
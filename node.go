@@ -0,0 +1,293 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// NodeKind is the kind of a Node.
+type NodeKind int
+
+const (
+	// OperandNode is any operand parsed by a prefix extension, e.g. a function call, an array or a prefix operator.
+	// Its Children are the expressions parsed inside the operand.
+	OperandNode NodeKind = iota
+	// ConstantNode is a literal or a named constant like true. Value holds its value.
+	ConstantNode
+	// VariableNode is a variable with a constant path like a.b[0]. Path holds the keys.
+	VariableNode
+	// InfixNode is an infix operation. Children holds both operands.
+	InfixNode
+	// PostfixNode is a postfix operation like the ternary operator.
+	// Children holds the operand followed by the expressions parsed by the operator.
+	PostfixNode
+)
+
+// Node is a node of the syntax tree of an expression.
+// Nodes are created by Language.Parse.
+type Node struct {
+	Kind NodeKind
+	// Operator of an infix or postfix node
+	Operator string
+	// Value of a constant node
+	Value interface{}
+	// Path of a variable node
+	Path []string
+	// Children in the order of their appearance
+	Children []*Node
+	// Pos and End are the byte offsets of the node in the expression.
+	// They include enclosing parentheses.
+	Pos, End int
+
+	source string
+	// start and end are the offsets of the node without enclosing parentheses
+	start, end    int
+	precedence    operatorPrecedence
+	constant      bool
+	parenthesized bool
+	// rightAssociative is true for infix operators grouping a op b op c as a op (b op c)
+	rightAssociative bool
+	// chain is true for the && node of a chained comparison like 1 < x < 10
+	chain bool
+	// eval evaluates the node as parsed
+	eval Evaluable
+}
+
+// IsConst returns if the node evaluates to a constant value.
+// Value holds the value of constant nodes of any kind.
+func (n *Node) IsConst() bool {
+	return n.constant
+}
+
+// Text returns the text of the node in the parsed expression.
+func (n *Node) Text() string {
+	return n.source[n.Pos:n.End]
+}
+
+// ErrNoSyntaxTree is returned by Language.Parse for Languages like Interpolation,
+// whose Init extension parses the expression into something else than a syntax tree.
+var ErrNoSyntaxTree = errors.New("language has no syntax tree")
+
+// Parse parses the expression into a syntax tree.
+// It fails with ErrNoSyntaxTree for Languages with an Init extension.
+func (l Language) Parse(expression string) (*Node, error) {
+	return l.ParseWithContext(context.Background(), expression)
+}
+
+// ParseWithContext parses the expression into a syntax tree using context.
+func (l Language) ParseWithContext(c context.Context, expression string) (*Node, error) {
+	if l.init != nil {
+		return nil, ErrNoSyntaxTree
+	}
+	node, _, _, err := l.parseNodes(c, expression)
+	if err == nil && node == nil {
+		return nil, ErrNoSyntaxTree
+	}
+	return node, err
+}
+
+// parseNodes returns the syntax tree, the Evaluable and the number of nodes of the expression.
+func (l Language) parseNodes(c context.Context, expression string) (*Node, Evaluable, int, error) {
+	p := newParser(expression, l)
+	if p.nodes == nil {
+		p.nodes = &nodeRecorder{source: expression}
+		p.nodes.push()
+	}
+	eval, err := p.parseAll(c)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return p.nodes.take(), eval, p.nodeCount, nil
+}
+
+// nodeRecorder builds the syntax tree while parsing.
+// Every prefix extension gets a frame collecting the nodes of the expressions it parses.
+type nodeRecorder struct {
+	source string
+	frames []*nodeFrame
+	// instrument wraps the Evaluable of each node that is no constant
+	instrument func(Evaluable, *Node) Evaluable
+	// observer is the observer given by WithObserver if the nodes are observed
+	observer EvaluationObserver
+}
+
+type nodeFrame struct {
+	children []*Node
+	path     []string
+	variable bool
+	enclosed bool
+}
+
+func (r *nodeRecorder) push() {
+	r.frames = append(r.frames, &nodeFrame{})
+}
+
+func (r *nodeRecorder) pop() *nodeFrame {
+	f := r.frames[len(r.frames)-1]
+	r.frames = r.frames[:len(r.frames)-1]
+	return f
+}
+
+func (r *nodeRecorder) top() *nodeFrame {
+	return r.frames[len(r.frames)-1]
+}
+
+func (r *nodeRecorder) add(n *Node) {
+	if r == nil || len(r.frames) == 0 {
+		return
+	}
+	f := r.top()
+	f.children = append(f.children, n)
+}
+
+func (r *nodeRecorder) instrumentation() func(Evaluable, *Node) Evaluable {
+	if r == nil {
+		return nil
+	}
+	return r.instrument
+}
+
+// instrumented returns eval of n instrumented if the recorder instruments nodes.
+// Typed Evaluables are dropped as they would skip the instrumentation.
+func (r *nodeRecorder) instrumented(eval Evaluable, float floatEvaluable, n *Node) (Evaluable, floatEvaluable) {
+	if r == nil || r.instrument == nil || eval.IsConst() {
+		return eval, float
+	}
+	return r.instrument(eval, n), nil
+}
+
+// take removes the last node of the current frame.
+func (r *nodeRecorder) take() *Node {
+	if r == nil || len(r.frames) == 0 {
+		return nil
+	}
+	f := r.top()
+	if len(f.children) == 0 {
+		return nil
+	}
+	n := f.children[len(f.children)-1]
+	f.children = f.children[:len(f.children)-1]
+	return n
+}
+
+// variable marks the current operand as variable if all keys are constant.
+func (r *nodeRecorder) variable(keys []Evaluable) {
+	if r == nil {
+		return
+	}
+	path := make([]string, len(keys))
+	for i, key := range keys {
+		if !key.IsConst() {
+			return
+		}
+		k, err := key.EvalString(nil, nil)
+		if err != nil {
+			return
+		}
+		path[i] = k
+	}
+	f := r.top()
+	f.path, f.variable = path, true
+}
+
+// enclose marks the current operand as parentheses around its only child.
+func (r *nodeRecorder) enclose() {
+	if r != nil {
+		r.top().enclosed = true
+	}
+}
+
+// selection marks the current operand as selection from its first child,
+// so parentheses around the child do not enclose the operand.
+func (r *nodeRecorder) selection() {
+	if r != nil {
+		r.top().enclosed = false
+	}
+}
+
+func (r *nodeRecorder) operand(f *nodeFrame, pos, end int, eval Evaluable) *Node {
+	end = pos + len(strings.TrimRightFunc(r.source[pos:end], unicode.IsSpace))
+	if f.enclosed && len(f.children) == 1 {
+		n := f.children[0]
+		n.Pos, n.End, n.parenthesized = pos, end, true
+		return n
+	}
+	n := &Node{
+		Kind:       OperandNode,
+		Children:   f.children,
+		Pos:        pos,
+		End:        end,
+		source:     r.source,
+		start:      pos,
+		end:        end,
+		precedence: ^operatorPrecedence(0),
+		eval:       eval,
+	}
+	if eval.IsConst() {
+		n.constant = true
+		n.Value, _ = eval(nil, nil)
+		if len(f.children) == 0 {
+			n.Kind = ConstantNode
+		}
+	} else if f.variable {
+		n.Kind = VariableNode
+		n.Path = f.path
+		n.Children = nil
+	}
+	return n
+}
+
+// infix returns the infix node operating on a and b.
+func (a *Node) infix(operator string, pre operatorPrecedence, b *Node) *Node {
+	return &Node{
+		Kind:       InfixNode,
+		Operator:   operator,
+		Children:   []*Node{a, b},
+		Pos:        a.Pos,
+		End:        b.End,
+		source:     a.source,
+		start:      a.Pos,
+		end:        b.End,
+		precedence: pre,
+	}
+}
+
+// prefixed returns the node of a prefix operator with precedence starting at pos applied to n.
+func (n *Node) prefixed(pre operatorPrecedence, pos int) *Node {
+	return &Node{
+		Kind:       OperandNode,
+		Children:   []*Node{n},
+		Pos:        pos,
+		End:        n.End,
+		source:     n.source,
+		start:      pos,
+		end:        n.End,
+		precedence: pre,
+	}
+}
+
+func (r *nodeRecorder) postfix(operator string, pre operatorPrecedence, a *Node, f *nodeFrame, end int, eval Evaluable) *Node {
+	end = a.Pos + len(strings.TrimRightFunc(r.source[a.Pos:end], unicode.IsSpace))
+	return &Node{
+		Kind:       PostfixNode,
+		Operator:   operator,
+		Children:   append([]*Node{a}, f.children...),
+		Pos:        a.Pos,
+		End:        end,
+		source:     r.source,
+		start:      a.Pos,
+		end:        end,
+		precedence: pre,
+		eval:       eval,
+	}
+}
+
+// offset returns the byte offset after the last token consumed by the parser.
+func (p *Parser) offset() int {
+	if p.isCamouflaged() {
+		return p.scanner.Position.Offset
+	}
+	return p.scanner.Pos().Offset
+}
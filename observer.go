@@ -0,0 +1,120 @@
+package gval
+
+import (
+	"context"
+)
+
+// EvaluationObserver is notified about the evaluation of expressions,
+// e.g. to trace evaluations, measure the coverage of rules or collect metrics.
+//
+// Observers are notified by Evaluables of Languages created with WithObserver.
+// Nodes are the nodes of the syntax tree as returned by Language.Parse,
+// constants are not notified.
+type EvaluationObserver interface {
+	// OnEnterNode is called before node n is evaluated.
+	OnEnterNode(c context.Context, n *Node)
+	// OnExitNode is called with the result of node n.
+	OnExitNode(c context.Context, n *Node, value interface{}, err error)
+	// OnFunctionCall is called with the arguments and result of each call of a function defined by Function.
+	OnFunctionCall(c context.Context, name string, arguments []interface{}, value interface{}, err error)
+}
+
+// WithObserver returns a Language whose Evaluables notify observer
+// and the observers given by ContextWithObserver.
+// Observer may be nil to notify the observers of the context only.
+//
+// Observed Evaluables are slower, Evaluables of other Languages ignore observers.
+func WithObserver(observer EvaluationObserver) Language {
+	l := newLanguage()
+	l.setOption(option{name: "observer", value: observerOption{observer}})
+	return l
+}
+
+// observerOption is the option value of WithObserver, it is set for a nil observer as well.
+type observerOption struct {
+	observer EvaluationObserver
+}
+
+func (l Language) observer() (EvaluationObserver, bool) {
+	o, ok := l.optionValue("observer").(observerOption)
+	return o.observer, ok
+}
+
+// observedNodes returns a nodeRecorder observing the nodes of expression
+// or nil if the Language is not observed.
+func (l Language) observedNodes(expression string) *nodeRecorder {
+	observer, ok := l.observer()
+	if !ok {
+		return nil
+	}
+	r := &nodeRecorder{source: expression, instrument: observeNode(observer), observer: observer}
+	r.push()
+	return r
+}
+
+// parseObserved returns the syntax tree and the Evaluable of the expression notifying
+// the observers given by ContextWithObserver.
+func (l Language) parseObserved(c context.Context, expression string) (*Node, Evaluable, error) {
+	p := newParser(expression, l)
+	if p.nodes == nil {
+		p.nodes = &nodeRecorder{source: expression, instrument: observeNode(nil)}
+		p.nodes.push()
+	}
+	eval, err := p.Evaluable(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.nodes.take(), eval, nil
+}
+
+type observersKey struct{}
+
+// ContextWithObserver returns a context notifying observer about evaluations
+// by Evaluables of Languages created with WithObserver.
+func ContextWithObserver(c context.Context, observer EvaluationObserver) context.Context {
+	observers, _ := c.Value(observersKey{}).([]EvaluationObserver)
+	return context.WithValue(c, observersKey{}, append(observers[:len(observers):len(observers)], observer))
+}
+
+// observers returns observer followed by the observers of the context.
+func observers(c context.Context, observer EvaluationObserver) []EvaluationObserver {
+	var observers []EvaluationObserver
+	if c != nil {
+		observers, _ = c.Value(observersKey{}).([]EvaluationObserver)
+	}
+	if observer != nil {
+		observers = append([]EvaluationObserver{observer}, observers...)
+	}
+	return observers
+}
+
+// observeNode returns a function notifying observer and the observers of the context about evaluations of nodes.
+func observeNode(observer EvaluationObserver) func(Evaluable, *Node) Evaluable {
+	return func(eval Evaluable, n *Node) Evaluable {
+		return func(c context.Context, parameter interface{}) (interface{}, error) {
+			observers := observers(c, observer)
+			if len(observers) == 0 {
+				return eval(c, parameter)
+			}
+			for _, o := range observers {
+				o.OnEnterNode(c, n)
+			}
+			v, err := eval(c, parameter)
+			for _, o := range observers {
+				o.OnExitNode(c, n, v, err)
+			}
+			return v, err
+		}
+	}
+}
+
+// observeFunction returns fun notifying observer and the observers of the context about its calls.
+func observeFunction(name string, fun function, observer EvaluationObserver) function {
+	return func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		v, err := fun(c, arguments...)
+		for _, o := range observers(c, observer) {
+			o.OnFunctionCall(c, name, arguments, v, err)
+		}
+		return v, err
+	}
+}
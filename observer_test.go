@@ -0,0 +1,87 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnEnterNode(c context.Context, n *Node) {
+	o.events = append(o.events, "enter "+n.Text())
+}
+
+func (o *recordingObserver) OnExitNode(c context.Context, n *Node, value interface{}, err error) {
+	o.events = append(o.events, fmt.Sprintf("exit %s = %v", n.Text(), value))
+}
+
+func (o *recordingObserver) OnFunctionCall(c context.Context, name string, arguments []interface{}, value interface{}, err error) {
+	o.events = append(o.events, fmt.Sprintf("call %s%v = %v", name, arguments, value))
+}
+
+func TestWithObserver(t *testing.T) {
+	double := Function("double", func(x float64) float64 { return 2 * x })
+	parameter := map[string]interface{}{"a": 2., "b": false}
+	const expression = `b && a > 1 || double(a) > 3`
+	want := []string{
+		"enter " + expression,
+		"enter b && a > 1",
+		"enter b",
+		"exit b = false",
+		"exit b && a > 1 = false",
+		"enter double(a) > 3",
+		"enter double(a)",
+		"enter a",
+		"exit a = 2",
+		"call double[2] = 4",
+		"exit double(a) = 4",
+		"exit double(a) > 3 = true",
+		"exit " + expression + " = true",
+	}
+
+	t.Run("language", func(t *testing.T) {
+		o := &recordingObserver{}
+		eval, err := Full(double, WithObserver(o)).NewEvaluable(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := eval(context.Background(), parameter); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(o.events, want) {
+			t.Errorf("events = %q, want %q", o.events, want)
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		o := &recordingObserver{}
+		program, err := Full(double, WithObserver(nil)).Compile(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := program.Evaluate(ContextWithObserver(context.Background(), o), parameter); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(o.events, want) {
+			t.Errorf("events = %q, want %q", o.events, want)
+		}
+	})
+
+	t.Run("not observed", func(t *testing.T) {
+		o := &recordingObserver{}
+		eval, err := Full(double).NewEvaluable(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := eval(ContextWithObserver(context.Background(), o), parameter); err != nil {
+			t.Fatal(err)
+		}
+		if len(o.events) != 0 {
+			t.Errorf("events = %q, want none", o.events)
+		}
+	})
+}
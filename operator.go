@@ -2,10 +2,13 @@ package gval
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
+	"text/scanner"
 
 	"github.com/shopspring/decimal"
 )
@@ -14,31 +17,107 @@ type stage struct {
 	Evaluable
 	infixBuilder
 	operatorPrecedence
+	// op is the infix operator of infixBuilder if it is no directInfix
+	op *infix
+	// float is the typed form of Evaluable if it is the result of a number operator
+	float floatEvaluable
+	// source is the parsed expression and pos and end the offsets of the operand in it
+	source   string
+	pos, end int
+	// node and operator are only set if the parser records the syntax tree
+	node     *Node
+	operator string
+	// instrument wraps the Evaluables of nodes if the parser instruments them
+	instrument func(Evaluable, *Node) Evaluable
+	// chains is true if the operator is a chained comparison.
+	// chained is the right operand of the comparison the Evaluable is the result of,
+	// if the operator continues the chain, e.g. x in 1 < x < 10.
+	chains      bool
+	chained     Evaluable
+	chainedNode *Node
+	// rightAssociative is true if the operator groups to the right
+	rightAssociative bool
+	// prefix is true for the stage of a prefix operator defined by PrefixOperatorWithPrecedence,
+	// its infixBuilder ignores the left operand
+	prefix bool
 }
 
 type stageStack []stage //operatorPrecedence in stacktStage is continuously, monotone ascending
 
 func (s *stageStack) push(b stage) error {
-	for len(*s) > 0 && s.peek().operatorPrecedence >= b.operatorPrecedence {
+	for len(*s) > 0 && s.peek().reducedBefore(b) {
 		a := s.pop()
-		eval, err := a.infixBuilder(a.Evaluable, b.Evaluable)
-		if err != nil {
-			return err
+		mid, midNode := b.Evaluable, b.node
+		var eval Evaluable
+		var float floatEvaluable
+		ok := false
+		if a.chained != nil {
+			right, err := a.infixBuilder(a.chained, b.Evaluable)
+			if err != nil {
+				return err
+			}
+			eval, ok = chainComparisons(a.Evaluable, right), true
+		} else if a.instrument == nil {
+			// instrumented operations must not be skipped by the typed fast path
+			eval, float, ok = a.buildFloat(b)
+		}
+		if !ok {
+			var err error
+			eval, err = a.infixBuilder(a.Evaluable, b.Evaluable)
+			if err != nil {
+				return err
+			}
+		}
+		if a.prefix {
+			if b.node != nil {
+				b.node = b.node.prefixed(a.operatorPrecedence, a.pos)
+				b.node.eval = eval
+			}
+		} else if a.node != nil {
+			if a.chained != nil {
+				b.node = a.node.infix("&&", 0, a.chainedNode.infix(a.operator, a.operatorPrecedence, b.node))
+				b.node.chain = true
+			} else {
+				b.node = a.node.infix(a.operator, a.operatorPrecedence, b.node)
+				b.node.rightAssociative = a.rightAssociative
+			}
+			b.node.eval = eval
+		}
+		b.chained, b.chainedNode = nil, nil
+		if a.chains && b.chains {
+			b.chained, b.chainedNode = mid, midNode
 		}
 		if a.IsConst() && b.IsConst() {
 			v, err := eval(nil, nil)
 			if err != nil {
 				return err
 			}
-			b.Evaluable = constant(v)
+			b.Evaluable, b.float = constant(v), nil
+			if b.node != nil {
+				b.node.constant, b.node.Value = true, v
+			}
+			b.pos = a.pos
 			continue
 		}
-		b.Evaluable = eval
+		if a.instrument != nil {
+			eval, float = a.instrument(eval, b.node), nil
+		}
+		b.Evaluable, b.float = evalErrors(eval, float, b.source, a.pos, b.end)
+		b.pos = a.pos
 	}
 	*s = append(*s, b)
 	return nil
 }
 
+// reducedBefore returns if the operation of a is applied before the operator of b,
+// e.g. a * b + c or, if the operators are left associative, a + b + c.
+func (a stage) reducedBefore(b stage) bool {
+	if a.operatorPrecedence == b.operatorPrecedence {
+		return !b.rightAssociative
+	}
+	return a.operatorPrecedence > b.operatorPrecedence
+}
+
 func (s *stageStack) peek() stage {
 	return (*s)[len(*s)-1]
 }
@@ -51,6 +130,33 @@ func (s *stageStack) pop() stage {
 
 type infixBuilder func(a, b Evaluable) (Evaluable, error)
 
+// prefixOperator is a prefix operator with precedence.
+type prefixOperator struct {
+	operatorPrecedence
+	apply Evaluable
+}
+
+// stage returns the stage of the prefix operator at offset pos.
+func (op prefixOperator) stage(p *Parser, pos int) stage {
+	return stage{
+		Evaluable: p.Const(nil),
+		infixBuilder: func(_, b Evaluable) (Evaluable, error) {
+			return func(c context.Context, v interface{}) (interface{}, error) {
+				x, err := b(c, v)
+				if err != nil {
+					return nil, err
+				}
+				return op.apply(c, x)
+			}, nil
+		},
+		operatorPrecedence: op.operatorPrecedence,
+		source:             p.expression,
+		pos:                pos,
+		instrument:         p.nodes.instrumentation(),
+		prefix:             true,
+	}
+}
+
 func (l Language) isSymbolOperation(r rune) bool {
 	_, in := l.operatorSymbols[r]
 	return in
@@ -65,6 +171,24 @@ func (l Language) isOperatorPrefix(op string) bool {
 	return false
 }
 
+// operandFollows reports whether the next token may start an operand.
+// An operator that is infix and postfix like % for modulo and percent is infix if it does.
+func (p *Parser) operandFollows() bool {
+	scan := p.Scan()
+	defer p.Camouflage("operator")
+	switch scan {
+	case scanner.EOF, ')', ']', '}', ',', ':', ';':
+		return false
+	case scanner.Ident:
+		_, isOperator := p.operators[p.TokenText()]
+		return !isOperator
+	}
+	if _, isPrefix := p.prefixes[scan]; isPrefix || !p.isSymbolOperation(scan) {
+		return true
+	}
+	return false
+}
+
 func (op *infix) initiate(name string) {
 	f := func(a, b interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("invalid operation (%T) %s (%T)", a, name, b)
@@ -73,6 +197,10 @@ func (op *infix) initiate(name string) {
 		f = op.arbitrary
 	}
 	for _, typeConvertion := range []bool{true, false} {
+		if !typeConvertion && len(op.typed) > 0 {
+			// operands of particular types are handled before others are converted
+			f = getTypedOpFunc(op.typed, f)
+		}
 		if op.text != nil && (!typeConvertion || op.arbitrary == nil) {
 			f = getStringOpFunc(op.text, f, typeConvertion)
 		}
@@ -86,6 +214,12 @@ func (op *infix) initiate(name string) {
 			f = getDecimalOpFunc(op.decimal, f, typeConvertion)
 		}
 	}
+	op.setF(f)
+}
+
+// setF sets the function applying op to evaluated operands and the builder of its operations.
+func (op *infix) setF(f opFunc) {
+	op.f = f
 	if op.shortCircuit == nil {
 		op.builder = func(a, b Evaluable) (Evaluable, error) {
 			return func(c context.Context, x interface{}) (interface{}, error) {
@@ -123,6 +257,22 @@ func (op *infix) initiate(name string) {
 
 type opFunc func(a, b interface{}) (interface{}, error)
 
+// typedOpFunc applies an operator to operands of particular types like Version.
+// It returns false for operands it does not handle.
+type typedOpFunc func(a, b interface{}) (interface{}, bool, error)
+
+// getTypedOpFunc returns the opFunc applying the first of typed handling the operands, otherwise f.
+func getTypedOpFunc(typed []typedOpFunc, f opFunc) opFunc {
+	return func(a, b interface{}) (interface{}, error) {
+		for _, t := range typed {
+			if r, ok, err := t(a, b); ok {
+				return r, err
+			}
+		}
+		return f(a, b)
+	}
+}
+
 func getStringOpFunc(s func(a, b string) (interface{}, error), f opFunc, typeConversion bool) opFunc {
 	if typeConversion {
 		return func(a, b interface{}) (interface{}, error) {
@@ -205,6 +355,14 @@ func convertToFloat(o interface{}) (float64, bool) {
 	if i, ok := o.(float64); ok {
 		return i, true
 	}
+	if b, ok := o.(*big.Int); ok && b != nil {
+		f, _ := new(big.Float).SetInt(b).Float64()
+		return f, true
+	}
+	if n, ok := o.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
 	v := reflect.ValueOf(o)
 	for o != nil && v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -258,6 +416,11 @@ func convertToDecimal(o interface{}) (decimal.Decimal, bool) {
 	if i, ok := o.(float64); ok {
 		return decimal.NewFromFloat(i), true
 	}
+	if n, ok := o.(json.Number); ok {
+		// json.Number keeps the precision of the decoded number
+		d, err := decimal.NewFromString(string(n))
+		return d, err == nil
+	}
 	v := reflect.ValueOf(o)
 	for o != nil && v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -340,14 +503,24 @@ type infix struct {
 	text         func(a, b string) (interface{}, error)
 	arbitrary    func(a, b interface{}) (interface{}, error)
 	shortCircuit func(a interface{}) (interface{}, bool)
-	builder      infixBuilder
+	// float is the number operation of operators defined by infixFloatOperator
+	float   func(a, b float64) float64
+	builder infixBuilder
+	// f applies the operator to evaluated operands
+	f opFunc
+	// rightAssociative operators group a op b op c as a op (b op c)
+	rightAssociative bool
+	// typed are the operations on operands of particular types like Version, the last merged first
+	typed []typedOpFunc
+	// postfix is the postfix operator with the same name, e.g. % for percent besides modulo
+	postfix *postfix
 }
 
 func (op infix) merge(op2 operator) operator {
 	switch op2 := op2.(type) {
 	case *infix:
 		if op.number == nil {
-			op.number = op2.number
+			op.number, op.float = op2.number, op2.float
 		}
 		if op.decimal == nil {
 			op.decimal = op2.decimal
@@ -361,9 +534,17 @@ func (op infix) merge(op2 operator) operator {
 		if op.arbitrary == nil {
 			op.arbitrary = op2.arbitrary
 		}
+		op.typed = append(append([]typedOpFunc{}, op.typed...), op2.typed...)
 		if op.shortCircuit == nil {
 			op.shortCircuit = op2.shortCircuit
 		}
+		op.rightAssociative = op.rightAssociative || op2.rightAssociative
+		if op.postfix == nil {
+			op.postfix = op2.postfix
+		}
+	case postfix:
+		op.postfix = &op2
+		return &op
 	}
 	if op2 != nil && op2.precedence() > op.operatorPrecedence {
 		op.operatorPrecedence = op2.precedence()
@@ -400,6 +581,10 @@ func (op postfix) merge(op2 operator) operator {
 		if op2.f != nil {
 			op.f = op2.f
 		}
+	case *infix:
+		in := *op2
+		in.postfix = &op
+		return &in
 	}
 	if op2 != nil && op2.precedence() > op.operatorPrecedence {
 		op.operatorPrecedence = op2.precedence()
@@ -143,13 +143,13 @@ func Test_stageStack_push(t *testing.T) {
 			}
 			stack := stageStack{}
 			for _, pre := range tt.pres {
-				if err := stack.push(stage{p.Const(string(rune(X))), op, pre}); err != nil {
+				if err := stack.push(stage{Evaluable: p.Const(string(rune(X))), infixBuilder: op, operatorPrecedence: pre}); err != nil {
 					t.Fatal(err)
 				}
 				X++
 			}
 
-			if err := stack.push(stage{p.Const(string(rune(X))), nil, 0}); err != nil {
+			if err := stack.push(stage{Evaluable: p.Const(string(rune(X)))}); err != nil {
 				t.Fatal(err)
 			}
 
@@ -0,0 +1,272 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Optimize returns the canonical text (see Format) of the expression with
+//
+//	constant sub-expressions evaluated: 2*3+x becomes 6 + x
+//	short circuits with constant operands folded: false && x becomes false
+//	ternary operators with constant conditions folded: true ? x : y becomes x
+//	double negations removed where the result is used as bool: !!a && b becomes a && b
+//
+// Constants are only replaced if their value can be written as literal of the Language.
+func (l Language) Optimize(expression string) (string, error) {
+	node, err := l.Parse(expression)
+	if err != nil {
+		return "", err
+	}
+	return Format(l.optimize(node, false)), nil
+}
+
+// optimize returns an optimized copy of n. If boolean is true, the value of n is only used as bool.
+// The copy takes the place of n, so it keeps the position of n.
+func (l Language) optimize(n *Node, boolean bool) *Node {
+	if n == nil {
+		return nil
+	}
+	o := l.optimizeNode(n, boolean)
+	if o == n {
+		return n
+	}
+	m := *o
+	m.Pos, m.End, m.parenthesized = n.Pos, n.End, n.parenthesized
+	return &m
+}
+
+func (l Language) optimizeNode(n *Node, boolean bool) *Node {
+	if n.constant {
+		if lit, ok := l.literalNode(n, n.Value); ok {
+			return lit
+		}
+		return n
+	}
+	switch n.Kind {
+	case InfixNode:
+		return l.optimizeInfix(n)
+	case PostfixNode:
+		return l.optimizePostfix(n)
+	case OperandNode:
+		x, negation := negated(n)
+		if negation {
+			if y, ok := negated(x); ok && (boolean || isBooleanNode(l, y)) {
+				return l.optimize(y, boolean)
+			}
+		}
+		m := *n
+		m.Children = make([]*Node, len(n.Children))
+		constants := len(n.Children) > 0
+		for i, child := range n.Children {
+			m.Children[i] = l.optimize(child, negation)
+			constants = constants && m.Children[i].constant
+		}
+		if constants {
+			if lit, ok := l.evalOperand(&m); ok {
+				return lit
+			}
+		}
+		return &m
+	}
+	return n
+}
+
+// evalOperand evaluates an operand with constant children, e.g. -x after x is replaced by a constant.
+func (l Language) evalOperand(n *Node) (*Node, bool) {
+	eval, err := l.NewEvaluable(Format(n))
+	if err != nil || !eval.IsConst() {
+		return nil, false
+	}
+	v, err := eval(context.Background(), nil)
+	if err != nil {
+		return nil, false
+	}
+	return l.literalNode(n, v)
+}
+
+func (l Language) optimizeInfix(n *Node) *Node {
+	boolean := l.isBooleanOperator(n.Operator)
+	a := l.optimize(n.Children[0], boolean)
+	op, _ := l.operators[n.Operator].(*infix)
+	if a.constant && op != nil && op.shortCircuit != nil {
+		if r, ok := op.shortCircuit(a.Value); ok {
+			if lit, ok := l.literalNode(n, r); ok {
+				return lit
+			}
+		}
+	}
+	b := l.optimize(n.Children[1], boolean)
+	if a.constant && b.constant {
+		if v, err := l.evalInfix(n.Operator, a.Value, b.Value); err == nil {
+			if lit, ok := l.literalNode(n, v); ok {
+				return lit
+			}
+		}
+	}
+	m := *n
+	m.Children = []*Node{a, b}
+	return &m
+}
+
+func (l Language) optimizePostfix(n *Node) *Node {
+	cond := l.optimize(n.Children[0], false)
+	if isTernary(n) && cond.constant {
+		branch := 1
+		if v := reflect.ValueOf(cond.Value); cond.Value == nil || v.IsZero() {
+			branch = 2
+		}
+		if branch < len(n.Children) {
+			return l.optimize(n.Children[branch], false)
+		}
+		if lit, ok := l.literalNode(n, nil); ok {
+			return lit
+		}
+	}
+	m := *n
+	m.Children = make([]*Node, len(n.Children))
+	m.Children[0] = cond
+	for i, child := range n.Children[1:] {
+		m.Children[i+1] = l.optimize(child, false)
+	}
+	return &m
+}
+
+func (l Language) evalInfix(operator string, a, b interface{}) (interface{}, error) {
+	var builder infixBuilder
+	switch op := l.operators[operator].(type) {
+	case *infix:
+		builder = op.builder
+	case directInfix:
+		builder = op.infixBuilder
+	}
+	if builder == nil {
+		return nil, fmt.Errorf("unknown operator %s", operator)
+	}
+	eval, err := builder(constant(a), constant(b))
+	if err != nil {
+		return nil, err
+	}
+	return eval(context.Background(), nil)
+}
+
+// isBooleanOperator returns true if the operator converts its operands to bool like && and ||.
+func (l Language) isBooleanOperator(operator string) bool {
+	op, ok := l.operators[operator].(*infix)
+	return ok && op.boolean != nil && op.number == nil && op.decimal == nil && op.text == nil && op.arbitrary == nil && len(op.typed) == 0
+}
+
+// booleanResults are the infix operators that evaluate to a bool even if they do not convert their operands to bool.
+var booleanResults = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true, "in": true, "&&": true, "||": true,
+}
+
+// isBooleanNode returns true if n certainly evaluates to a bool.
+func isBooleanNode(l Language, n *Node) bool {
+	if _, ok := negated(n); ok {
+		return true
+	}
+	if n.Kind != InfixNode {
+		return false
+	}
+	switch l.operators[n.Operator].(type) {
+	case *infix, directInfix:
+		if booleanResults[n.Operator] {
+			return true
+		}
+	}
+	return l.isBooleanOperator(n.Operator)
+}
+
+// negated returns the operand of a negation !x.
+func negated(n *Node) (*Node, bool) {
+	if n.Kind != OperandNode || len(n.Children) != 1 {
+		return nil, false
+	}
+	x := n.Children[0]
+	if strings.TrimSpace(n.source[n.start:x.Pos]) != "!" || strings.TrimSpace(n.source[x.End:n.end]) != "" {
+		return nil, false
+	}
+	return x, true
+}
+
+func isTernary(n *Node) bool {
+	if n.Operator != "?" || len(n.Children) < 2 {
+		return false
+	}
+	if len(n.Children) == 3 {
+		return strings.TrimSpace(n.source[n.Children[1].End:n.Children[2].Pos]) == ":"
+	}
+	return true
+}
+
+// literalNode returns a constant node in place of n with the literal of value as text.
+// It fails if the literal does not evaluate to value in the Language.
+func (l Language) literalNode(n *Node, value interface{}) (*Node, bool) {
+	text, ok := literal(value)
+	if !ok {
+		return nil, false
+	}
+	eval, err := l.NewEvaluable(text)
+	if err != nil {
+		return nil, false
+	}
+	if v, err := eval(context.Background(), nil); err != nil || !reflect.DeepEqual(v, value) {
+		return nil, false
+	}
+	return &Node{
+		Kind:       ConstantNode,
+		Value:      value,
+		Pos:        n.Pos,
+		End:        n.End,
+		source:     text,
+		start:      0,
+		end:        len(text),
+		precedence: ^operatorPrecedence(0),
+		constant:   true,
+	}, true
+}
+
+// literal returns the expression text of value
+func literal(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "nil", true
+	case bool:
+		return strconv.FormatBool(v), true
+	case string:
+		return strconv.Quote(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case []interface{}:
+		elements := make([]string, len(v))
+		for i, e := range v {
+			s, ok := literal(e)
+			if !ok {
+				return "", false
+			}
+			elements[i] = s
+		}
+		return "[" + strings.Join(elements, ", ") + "]", true
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		elements := make([]string, len(keys))
+		for i, k := range keys {
+			s, ok := literal(v[k])
+			if !ok {
+				return "", false
+			}
+			elements[i] = strconv.Quote(k) + ":" + s
+		}
+		return "{" + strings.Join(elements, ", ") + "}", true
+	}
+	return "", false
+}
@@ -0,0 +1,48 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestLanguage_Optimize(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+		wantErr    bool
+	}{
+		{name: "constant", expression: "2*3+x", want: "6 + x"},
+		{name: "constant right", expression: "x * (1 + 2)", want: "x * 3"},
+		{name: "constant in function", expression: `date("2020-" + "01-01")`, want: `date("2020-01-01")`},
+		{name: "constant array", expression: `x in [1+1, "a" + "b"]`, want: `x in [2, "ab"]`},
+		{name: "short circuit and", expression: "false && x", want: "false"},
+		{name: "short circuit or", expression: "x > 1 && (1 == 1 || y)", want: "x > 1 && true"},
+		{name: "short circuit not taken", expression: "true && x", want: "true && x"},
+		{name: "ternary true", expression: "1 < 2 ? a : b", want: "a"},
+		{name: "ternary false", expression: `"" ? a : b + 1`, want: "b + 1"},
+		{name: "ternary without else", expression: `false ? a`, want: "nil"},
+		{name: "ternary folded to constant", expression: `(true ? 1 : x) + 2`, want: "3"},
+		{name: "ternary inside prefix", expression: `-(true ? a + b : c)`, want: "-(a + b)"},
+		{name: "double negation in condition", expression: "!!a && b", want: "a && b"},
+		{name: "double negation of boolean", expression: "!!(a || b)", want: "a || b"},
+		{name: "double negation of comparison", expression: "!!(a > b)", want: "a > b"},
+		{name: "double negation of equality", expression: "!!(a == b) || c", want: "a == b || c"},
+		{name: "double negation of in", expression: "!!(a in [1, 2])", want: "a in [1, 2]"},
+		{name: "double negation of arithmetic kept", expression: "!!(a + b)", want: "!!(a + b)"},
+		{name: "double negation kept", expression: "!!a", want: "!!a"},
+		{name: "triple negation", expression: "!!!a", want: "!a"},
+		{name: "negative number", expression: "x - (0 - 1)", want: "x - -1"},
+		{name: "parse error", expression: "1 +", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Full().Optimize(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Optimize(%s) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Optimize(%s) = %s, want %s", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
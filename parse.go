@@ -2,40 +2,127 @@ package gval
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 	"text/scanner"
 	"regexp"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 // ParseExpression scans an expression into an Evaluable.
 func (p *Parser) ParseExpression(c context.Context) (eval Evaluable, err error) {
+	eval, _, err = p.parseExpressionTyped(c)
+	return eval, err
+}
+
+// parseExpressionTyped scans an expression into an Evaluable and its typed form if it is a number operation.
+func (p *Parser) parseExpressionTyped(c context.Context) (eval Evaluable, float floatEvaluable, err error) {
 	stack := stageStack{}
 	for {
-		eval, err = p.ParseNextExpression(c)
+		pos := p.offset()
+		if op, ok := p.scanPrefixOperator(); ok {
+			if err := p.countNode(); err != nil {
+				return nil, nil, err
+			}
+			// prefix operators have no left operand to reduce
+			stack = append(stack, op.stage(p, pos))
+			continue
+		}
+		eval, float, err = p.parseNextExpressionTyped(c)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		eval, float = evalErrors(eval, float, p.expression, pos, p.offset())
 
-		if stage, err := p.parseOperator(c, &stack, eval); err != nil {
-			return nil, err
+		if stage, err := p.parseOperator(c, &stack, eval, float, pos); err != nil {
+			return nil, nil, err
 		} else if err = stack.push(stage); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if stack.peek().infixBuilder == nil {
-			return stack.pop().Evaluable, nil
+			st := stack.pop()
+			p.nodes.add(st.node)
+			if p.limits != nil {
+				eval, err := p.limits.check(st.Evaluable)
+				return eval, st.float, err
+			}
+			return st.Evaluable, st.float, nil
 		}
 	}
 }
 
+// scanPrefixOperator scans the next token if it is a prefix operator with precedence.
+func (p *Parser) scanPrefixOperator() (prefixOperator, bool) {
+	if len(p.prefixOperators) == 0 {
+		return prefixOperator{}, false
+	}
+	scan := p.Scan()
+	var key interface{} = scan
+	if scan == scanner.Ident {
+		key = p.TokenText()
+	}
+	op, ok := p.prefixOperators[key]
+	if !ok {
+		p.Camouflage("prefix")
+	}
+	return op, ok
+}
+
 // ParseNextExpression scans the expression ignoring following operators
 func (p *Parser) ParseNextExpression(c context.Context) (eval Evaluable, err error) {
+	eval, _, err = p.parseNextExpressionTyped(c)
+	return eval, err
+}
+
+// parseNextExpressionTyped scans the expression ignoring following operators.
+// It returns the typed form of parenthesized number operations as well.
+func (p *Parser) parseNextExpressionTyped(c context.Context) (eval Evaluable, float floatEvaluable, err error) {
+	if err := p.countNode(); err != nil {
+		return nil, nil, err
+	}
+	if p.limits != nil {
+		return p.parseLimitedOperand(c)
+	}
+	return p.parseOperand(c)
+}
+
+func (p *Parser) parseOperand(c context.Context) (eval Evaluable, float floatEvaluable, err error) {
 	scan := p.Scan()
+	if p.nodes == nil {
+		eval, err = p.parseNextExpression(c, scan)
+		return eval, p.takeFloat(), err
+	}
+	pos := p.scanner.Position.Offset
+	p.nodes.push()
+	eval, err = p.parseNextExpression(c, scan)
+	float = p.takeFloat()
+	f := p.nodes.pop()
+	if err != nil {
+		return nil, nil, err
+	}
+	n := p.nodes.operand(f, pos, p.offset(), eval)
+	p.nodes.add(n)
+	if !n.parenthesized {
+		eval, float = p.nodes.instrumented(eval, float, n)
+	}
+	return eval, float, nil
+}
+
+// takeFloat returns and resets the typed form of the last parsed parentheses.
+func (p *Parser) takeFloat() floatEvaluable {
+	float := p.float
+	p.float = nil
+	return float
+}
+
+func (p *Parser) parseNextExpression(c context.Context, scan rune) (Evaluable, error) {
+	p.float = nil
 	ex, ok := p.prefixes[scan]
 	if !ok {
 		if scan != scanner.EOF && p.def != nil {
@@ -43,7 +130,11 @@ func (p *Parser) ParseNextExpression(c context.Context) (eval Evaluable, err err
 		}
 		return nil, p.Expected("extensions")
 	}
-	return ex(c, p)
+	eval, err := ex(c, p)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseOperandSelectors(c, eval)
 }
 
 // ParseSublanguage sets the next language for this parser to parse and calls
@@ -70,6 +161,29 @@ func (p *Parser) ParseSublanguage(c context.Context, l Language) (Evaluable, err
 	return p.parse(c)
 }
 
+// parseAll parses the whole expression.
+func (p *Parser) parseAll(c context.Context) (Evaluable, error) {
+	if m := p.metrics(); m != nil {
+		start := time.Now()
+		eval, err := p.parseAllExpression(c)
+		m.Parsed(time.Since(start), err)
+		return eval, err
+	}
+	return p.parseAllExpression(c)
+}
+
+func (p *Parser) parseAllExpression(c context.Context) (Evaluable, error) {
+	eval, err := p.parse(c)
+	if err == nil && p.isCamouflaged() && p.lastScan != scanner.EOF {
+		err = p.camouflage
+	}
+	if err != nil {
+		pos := p.scanner.Pos()
+		return nil, fmt.Errorf("parsing error: %s - %d:%d %w", p.scanner.Position, pos.Line, pos.Column, err)
+	}
+	return eval, nil
+}
+
 func (p *Parser) parse(c context.Context) (Evaluable, error) {
 	if p.init != nil {
 		return p.init(c, p)
@@ -124,20 +238,33 @@ func parseDecimal(c context.Context, p *Parser) (Evaluable, error) {
 }
 
 func parseParentheses(c context.Context, p *Parser) (Evaluable, error) {
-	eval, err := p.ParseExpression(c)
+	eval, float, err := p.parseExpressionTyped(c)
 	if err != nil {
 		return nil, err
 	}
 	switch p.Scan() {
 	case ')':
+		p.nodes.enclose()
+		p.float = float
 		return eval, nil
 	default:
 		return nil, p.Expected("parentheses", ')')
 	}
 }
 
-func (p *Parser) parseOperator(c context.Context, stack *stageStack, eval Evaluable) (st stage, err error) {
+// parseOperator parses the operator following the operand eval starting at pos.
+func (p *Parser) parseOperator(c context.Context, stack *stageStack, eval Evaluable, float floatEvaluable, pos int) (st stage, err error) {
+	node := p.nodes.take()
 	for {
+		operand := stage{
+			Evaluable:  eval,
+			float:      float,
+			source:     p.expression,
+			pos:        pos,
+			end:        p.offset(),
+			node:       node,
+			instrument: p.nodes.instrumentation(),
+		}
 		scan := p.Scan()
 		op := p.TokenText()
 		mustOp := false
@@ -151,38 +278,62 @@ func (p *Parser) parseOperator(c context.Context, stack *stageStack, eval Evalua
 			}
 		} else if scan != scanner.Ident {
 			p.Camouflage("operator")
-			return stage{Evaluable: eval}, nil
+			return operand, nil
 		}
-		switch operator := p.operators[op].(type) {
+		if _, ok := p.operators[op]; ok {
+			if err := p.countNode(); err != nil {
+				return stage{}, err
+			}
+		}
+		operator := p.operators[op]
+		if in, ok := operator.(*infix); ok && in.postfix != nil && !p.operandFollows() {
+			operator = *in.postfix
+		}
+		switch operator := operator.(type) {
 		case *infix:
-			return stage{
-				Evaluable:          eval,
-				infixBuilder:       operator.builder,
-				operatorPrecedence: operator.operatorPrecedence,
-			}, nil
+			operator = p.modeOperator(op, operator)
+			operand.infixBuilder = operator.builder
+			operand.operatorPrecedence = operator.operatorPrecedence
+			operand.op, operand.operator = operator, op
+			operand.chains = p.chainsComparison(op)
+			operand.rightAssociative = operator.rightAssociative
+			return operand, nil
 		case directInfix:
-			return stage{
-				Evaluable:          eval,
-				infixBuilder:       operator.infixBuilder,
-				operatorPrecedence: operator.operatorPrecedence,
-			}, nil
+			operand.infixBuilder = operator.infixBuilder
+			operand.operatorPrecedence = operator.operatorPrecedence
+			operand.operator = op
+			operand.chains = p.chainsComparison(op)
+			return operand, nil
 		case postfix:
-			if err = stack.push(stage{
-				operatorPrecedence: operator.operatorPrecedence,
-				Evaluable:          eval,
-			}); err != nil {
+			operand.operatorPrecedence = operator.operatorPrecedence
+			if err = stack.push(operand); err != nil {
 				return stage{}, err
 			}
-			eval, err = operator.f(c, p, stack.pop().Evaluable, operator.operatorPrecedence)
+			operand := stack.pop()
+			pos = operand.pos
+			if p.nodes == nil {
+				eval, err = operator.f(c, p, operand.Evaluable, operator.operatorPrecedence)
+				if err != nil {
+					return
+				}
+				eval, float = evalErrors(eval, nil, p.expression, pos, p.offset())
+				continue
+			}
+			p.nodes.push()
+			eval, err = operator.f(c, p, operand.Evaluable, operator.operatorPrecedence)
+			f := p.nodes.pop()
 			if err != nil {
 				return
 			}
+			node = p.nodes.postfix(op, operator.operatorPrecedence, operand.node, f, p.offset(), eval)
+			eval, float = p.nodes.instrumented(eval, nil, node)
+			eval, float = evalErrors(eval, float, p.expression, pos, p.offset())
 			continue
 		}
 
 		if !mustOp {
 			p.Camouflage("operator")
-			return stage{Evaluable: eval}, nil
+			return operand, nil
 		}
 		return stage{}, fmt.Errorf("unknown operator %s", op)
 	}
@@ -192,45 +343,162 @@ func parseIdent(c context.Context, p *Parser) (call string, alternative func() (
 	token := p.TokenText()
 	return token,
 		func() (Evaluable, error) {
-			fullname := token
+			// dotted is the name of a namespaced function like math.round while the path has identifiers only
+			dotted := token
 
+			var base Evaluable
 			keys := []Evaluable{p.Const(token)}
-			for {
-				scan := p.Scan()
-				switch scan {
-				case '.':
-					scan = p.Scan()
-					switch scan {
-					case scanner.Ident:
-						token = p.TokenText()
-						keys = append(keys, p.Const(token))
-					default:
-						return nil, p.Expected("field", scanner.Ident)
-					}
-				case '(':
-					args, err := p.parseArguments(c)
-					if err != nil {
-						return nil, err
-					}
-					return p.callEvaluable(fullname, p.Var(keys...), args...), nil
-				case '[':
-					key, err := p.ParseExpression(c)
-					if err != nil {
-						return nil, err
-					}
-					switch p.Scan() {
-					case ']':
-						keys = append(keys, key)
-					default:
-						return nil, p.Expected("array key", ']')
+			if b := p.letBinding(token); b != nil {
+				base, keys, dotted = b.value, nil, ""
+			}
+			return p.parseSelectors(c, token, dotted, base, keys)
+		}, nil
+
+}
+
+// parseOperandSelectors parses the selectors following the operand eval like lookup(id).status or [1, 2][0]
+// unless the Language defines . or [ as operator.
+func (p *Parser) parseOperandSelectors(c context.Context, eval Evaluable) (Evaluable, error) {
+	scan := p.lastScan
+	if !p.isCamouflaged() {
+		scan = p.Scan()
+		p.Camouflage("operator")
+	}
+	if scan != '.' && scan != '[' || p.optionValue("selectors") != true {
+		return eval, nil
+	}
+	if _, ok := p.operators[string(scan)]; ok {
+		return eval, nil
+	}
+	p.float = nil
+	p.nodes.selection()
+	return p.parseSelectors(c, "", "", eval, nil)
+}
+
+// parseSelectors parses the fields .a, keys [k], slices [from:to], wildcards [*], recursive descents ..a
+// and calls (arguments) following an operand. It returns the selection of keys from base,
+// from the parameter if base is nil.
+func (p *Parser) parseSelectors(c context.Context, fullname, dotted string, base Evaluable, keys []Evaluable) (Evaluable, error) {
+	// multi is true if base yields multiple values e.g. after a wildcard
+	multi := false
+	for {
+		scan := p.Scan()
+		switch scan {
+		case '.':
+			scan = p.Scan()
+			switch scan {
+			case scanner.Ident:
+				token := p.TokenText()
+				keys = append(keys, p.Const(token))
+				if dotted != "" {
+					dotted += "." + token
+				}
+				if fullname == "" {
+					// name of the first field selected from an operand in error messages
+					fullname = token
+				}
+			case '.':
+				dotted = ""
+				if p.Scan() != scanner.Ident {
+					return nil, p.Expected("recursive descent", scanner.Ident)
+				}
+				base = descendValue(p.selectFrom(base, keys, multi), p.TokenText(), multi)
+				keys = nil
+				multi = true
+			default:
+				return nil, p.Expected("field", scanner.Ident)
+			}
+		case '(':
+			if _, ok := p.functions[dotted]; ok && dotted != fullname {
+				p.Camouflage("function call", '(')
+				return p.prefixes[dotted](c, p)
+			}
+			args, err := p.parseArguments(c)
+			if err != nil {
+				return nil, err
+			}
+			// name of the called function or method in error messages
+			name := dotted
+			if name == "" {
+				name = fullname
+				if len(keys) > 0 && keys[len(keys)-1].IsConst() {
+					if method, err := keys[len(keys)-1].EvalString(c, nil); err == nil {
+						name = method
 					}
+				}
+			}
+			fun := p.selectFrom(base, keys, multi)
+			if !multi && len(keys) > 0 {
+				receiver := p.selectFrom(base, keys[:len(keys)-1], false)
+				if base == nil && len(keys) == 1 {
+					receiver = func(c context.Context, v interface{}) (interface{}, error) { return v, nil }
+				}
+				fun = methodValue(fun, receiver, keys[len(keys)-1])
+			}
+			return p.callEvaluable(name, fun, args...), nil
+		case '[':
+			dotted = ""
+			var from Evaluable
+			switch p.Scan() {
+			case '*':
+				if p.Scan() != ']' {
+					return nil, p.Expected("wildcard", ']')
+				}
+				base = wildcardValue(p.selectFrom(base, keys, multi), multi)
+				keys = nil
+				multi = true
+				continue
+			case ':':
+			default:
+				p.Camouflage("array key", ':')
+				key, err := p.ParseExpression(c)
+				if err != nil {
+					return nil, err
+				}
+				switch p.Scan() {
+				case ']':
+					keys = append(keys, key)
+					continue
+				case ':':
+					from = key
 				default:
-					p.Camouflage("variable", '.', '(', '[')
-					return p.Var(keys...), nil
+					return nil, p.Expected("array key", ']')
 				}
 			}
-		}, nil
+			from, to, err := p.parseSliceEnd(c, from)
+			if err != nil {
+				return nil, err
+			}
+			base = sliceValue(p.selectFrom(base, keys, multi), from, to, multi)
+			keys = nil
+		default:
+			p.Camouflage("variable", '.', '(', '[')
+			if base == nil {
+				p.nodes.variable(keys)
+			}
+			return p.selectFrom(base, keys, multi), nil
+		}
+	}
+}
 
+// parseSliceEnd parses the end of a slice expression [from:to] after the colon.
+func (p *Parser) parseSliceEnd(c context.Context, from Evaluable) (Evaluable, Evaluable, error) {
+	if from == nil {
+		from = p.Const(nil)
+	}
+	to := p.Const(nil)
+	if p.Scan() == ']' {
+		return from, to, nil
+	}
+	p.Camouflage("slice", ']')
+	to, err := p.ParseExpression(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.Scan() != ']' {
+		return nil, nil, p.Expected("slice", ']')
+	}
+	return from, to, nil
 }
 
 func (p *Parser) parseArguments(c context.Context) (args []Evaluable, err error) {
@@ -254,19 +522,104 @@ func (p *Parser) parseArguments(c context.Context) (args []Evaluable, err error)
 	}
 }
 
-func inArray(a, b interface{}) (interface{}, error) {
+func inArray(c context.Context, a, b interface{}) (interface{}, error) {
 	col, ok := b.([]interface{})
 	if !ok {
+		found := false
+		if ok, err := iterate(c, b, func(v interface{}) bool {
+			found = inEqual(a, v)
+			return !found
+		}); ok {
+			return found, err
+		}
 		return nil, fmt.Errorf("expected type []interface{} for in operator but got %T", b)
 	}
-	for _, value := range col {
-		if reflect.DeepEqual(a, value) {
+	for i, value := range col {
+		if err := CheckContext(c, i); err != nil {
+			return nil, err
+		}
+		if inEqual(a, value) {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
+// inEqual returns if a equals the element e for the in operator.
+// Numbers of different types like int64, float64 and json.Number are equal if their values are, like for ==.
+func inEqual(a, e interface{}) bool {
+	if reflect.DeepEqual(a, e) {
+		return true
+	}
+	x, k := inNumber(a)
+	y, l := inNumber(e)
+	return k && l && x == y
+}
+
+// inNumber converts numbers and json.Number, but no strings, to float64.
+func inNumber(o interface{}) (float64, bool) {
+	if n, ok := o.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return strictFloat(o)
+}
+
+func between(a, b interface{}) (interface{}, error) {
+	bounds, ok := b.([]interface{})
+	if !ok || len(bounds) != 2 {
+		return nil, fmt.Errorf("expected [min, max] for between operator but got %v (%T)", b, b)
+	}
+	min, max := bounds[0], bounds[1]
+
+	_, aIsDecimal := a.(decimal.Decimal)
+	_, minIsDecimal := min.(decimal.Decimal)
+	_, maxIsDecimal := max.(decimal.Decimal)
+	if aIsDecimal || minIsDecimal || maxIsDecimal {
+		x, k := convertToDecimal(a)
+		lo, l := convertToDecimal(min)
+		hi, m := convertToDecimal(max)
+		if k && l && m {
+			return x.GreaterThanOrEqual(lo) && x.LessThanOrEqual(hi), nil
+		}
+	}
+
+	if t, ok := a.(time.Time); ok {
+		lo, l := convertToTime(min, t.Location())
+		hi, m := convertToTime(max, t.Location())
+		if l && m {
+			return !t.Before(lo) && !t.After(hi), nil
+		}
+		return nil, fmt.Errorf("expected time bounds for between operator but got %v (%T) and %v (%T)", min, min, max, max)
+	}
+
+	x, k := convertToFloat(a)
+	lo, l := convertToFloat(min)
+	hi, m := convertToFloat(max)
+	if k && l && m {
+		return x >= lo && x <= hi, nil
+	}
+
+	s, k := a.(string)
+	sLo, l := min.(string)
+	sHi, m := max.(string)
+	if k && l && m {
+		return s >= sLo && s <= sHi, nil
+	}
+	return nil, fmt.Errorf("invalid operation (%T) between [%T, %T]", a, min, max)
+}
+
+func convertToTime(o interface{}, loc *time.Location) (time.Time, bool) {
+	switch o := o.(type) {
+	case time.Time:
+		return o, true
+	case string:
+		t, err := parseDate(o, loc)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
 func parseIf(c context.Context, p *Parser, e Evaluable) (Evaluable, error) {
 	a, err := p.ParseExpression(c)
 	if err != nil {
@@ -283,20 +636,74 @@ func parseIf(c context.Context, p *Parser, e Evaluable) (Evaluable, error) {
 	default:
 		return nil, p.Expected("<> ? <> : <>", ':', scanner.EOF)
 	}
+	truthy := p.conditionTest("?")
 	return func(c context.Context, v interface{}) (interface{}, error) {
 		x, err := e(c, v)
 		if err != nil {
 			return nil, err
 		}
-		if valX := reflect.ValueOf(x); x == nil || valX.IsZero() {
+		ok, err := truthy(x)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
 			return b(c, v)
 		}
 		return a(c, v)
 	}, nil
 }
 
+// parseElvis parses the alternative of a ?: b, which is a if it is true, otherwise b.
+func parseElvis(c context.Context, p *Parser, e Evaluable) (Evaluable, error) {
+	b, err := p.ParseExpression(c)
+	if err != nil {
+		return nil, err
+	}
+	truthy := p.conditionTest("?:")
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		x, err := e(c, v)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := truthy(x)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return b(c, v)
+		}
+		return x, nil
+	}, nil
+}
+
+// conditionTest returns the test of the condition of the ternary operator op.
+// In a Strict Language conditions must be bool, otherwise all values but nil and zero values are true.
+func (p *Parser) conditionTest(op string) func(x interface{}) (bool, error) {
+	if !p.isStrict() {
+		return func(x interface{}) (bool, error) {
+			return isTruthy(x), nil
+		}
+	}
+	return func(x interface{}) (bool, error) {
+		b, ok := x.(bool)
+		if !ok {
+			return false, &TypeMismatchError{Operator: op, Operands: []interface{}{x}}
+		}
+		return b, nil
+	}
+}
+
+// isTruthy returns if the ternary operator takes its first branch for condition x.
+func isTruthy(x interface{}) bool {
+	return x != nil && !reflect.ValueOf(x).IsZero()
+}
+
 func parseJSONArray(c context.Context, p *Parser) (Evaluable, error) {
-	evals := []Evaluable{}
+	type element struct {
+		Evaluable
+		spread bool
+	}
+	evals := []element{}
 	for {
 		switch p.Scan() {
 		default:
@@ -305,17 +712,37 @@ func parseJSONArray(c context.Context, p *Parser) (Evaluable, error) {
 			if err != nil {
 				return nil, err
 			}
-			evals = append(evals, eval)
+			evals = append(evals, element{eval, false})
+		case '.':
+			if p.Scan() != '.' || p.Scan() != '.' {
+				return nil, p.Expected("spread", '.')
+			}
+			eval, err := p.ParseExpression(c)
+			if err != nil {
+				return nil, err
+			}
+			evals = append(evals, element{eval, true})
 		case ',':
 		case ']':
 			return func(c context.Context, v interface{}) (interface{}, error) {
-				vs := make([]interface{}, len(evals))
+				vs := make([]interface{}, 0, len(evals))
 				for i, e := range evals {
-					eval, err := e(c, v)
+					if err := CheckContext(c, i); err != nil {
+						return nil, err
+					}
+					eval, err := e.Evaluable(c, v)
 					if err != nil {
 						return nil, err
 					}
-					vs[i] = eval
+					if !e.spread {
+						vs = append(vs, eval)
+						continue
+					}
+					s, ok := convertToSlice(eval)
+					if !ok {
+						return nil, fmt.Errorf("can not spread %v (%T) expected array", eval, eval)
+					}
+					vs = append(vs, s...)
 				}
 
 				return vs, nil
@@ -352,7 +779,10 @@ func parseJSONObject(c context.Context, p *Parser) (Evaluable, error) {
 		case '}':
 			return func(c context.Context, v interface{}) (interface{}, error) {
 				vs := map[string]interface{}{}
-				for _, e := range evals {
+				for i, e := range evals {
+					if err := CheckContext(c, i); err != nil {
+						return nil, err
+					}
 					value, err := e.value(c, v)
 					if err != nil {
 						return nil, err
@@ -381,10 +811,70 @@ func endsWithOp(a, b string) (interface{}, error) {
 	return strings.HasSuffix(a, b), nil
 }
 
+func equalsIgnoreCaseOp(a, b string) (interface{}, error) {
+	return strings.EqualFold(a, b), nil
+}
+
+func startsWithIgnoreCaseOp(a, b string) (interface{}, error) {
+	return strings.HasPrefix(strings.ToLower(a), strings.ToLower(b)), nil
+}
+
+func containsIgnoreCaseOp(a, b string) (interface{}, error) {
+	return strings.Contains(strings.ToLower(a), strings.ToLower(b)), nil
+}
+
+func endsWithIgnoreCaseOp(a, b string) (interface{}, error) {
+	return strings.HasSuffix(strings.ToLower(a), strings.ToLower(b)), nil
+}
+
 func matchOp(a, b string) (interface{}, error) {
-	matched, err := regexp.MatchString(b, a)
+	return RegexLimits{}.match(context.Background(), b, a)
+}
+
+func likeOp(a, b string) (interface{}, error) {
+	re, err := regexps.compile(likePattern(b, false))
 	if err != nil {
 		return nil, err
 	}
-	return matched, nil
+	return re.MatchString(a), nil
+}
+
+func ilikeOp(a, b string) (interface{}, error) {
+	re, err := regexps.compile(likePattern(b, true))
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString(a), nil
+}
+
+// likePattern translates a SQL LIKE pattern into an anchored regular expression.
+// % matches any sequence of characters, _ matches a single character and
+// a backslash escapes the following character.
+func likePattern(pattern string, caseInsensitive bool) string {
+	re := strings.Builder{}
+	if caseInsensitive {
+		re.WriteString("(?i)")
+	}
+	re.WriteString("(?s)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			re.WriteString(".*")
+		case r == '_':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if escaped {
+		re.WriteString(regexp.QuoteMeta("\\"))
+	}
+	re.WriteString("$")
+	return re.String()
 }
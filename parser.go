@@ -2,30 +2,101 @@ package gval
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"text/scanner"
 	"unicode"
 )
 
 // Parser parses expressions in a Language into an Evaluable
+//
+// Parsers of a Language with ReuseParsers are reused after parsing an expression,
+// so extensions of such a Language must not keep the Parser in the Evaluables they return.
 type Parser struct {
 	scanner scanner.Scanner
+	reader  strings.Reader
+	// expression is the parsed expression, the source of EvalErrors
+	expression string
 	Language
 	lastScan   rune
 	camouflage error
+	// nodes records the syntax tree if the parser is created by Language.Parse
+	nodes *nodeRecorder
+	// float is the typed form of the expression in the parentheses parsed last
+	float floatEvaluable
+	// limits are set by WithLimits, depth tracks their use
+	// and nodeCount counts the parsed operands and operators
+	limits    *Limits
+	nodeCount int
+	depth     int
+	// lets are the names bound by let in scope, the innermost last
+	lets []*letBinding
+	// piped is the operand of |> passed as first argument to the function following it
+	piped Evaluable
+}
+
+// parsers holds Parsers with their scanner buffers for reuse by Language.NewEvaluable of Languages with ReuseParsers
+var parsers = sync.Pool{
+	New: func() interface{} {
+		return &Parser{}
+	},
 }
 
 func newParser(expression string, l Language) *Parser {
-	sc := scanner.Scanner{}
-	sc.Init(strings.NewReader(expression))
-	sc.Error = func(*scanner.Scanner, string) {}
-	sc.Filename = expression + "\t"
-	p := &Parser{scanner: sc, Language: l}
-	p.resetScannerProperties()
+	p := &Parser{}
+	p.reset(expression, l)
 	return p
 }
 
+// ReuseParsers returns a Language whose NewEvaluable reuses Parsers and their scanner buffers,
+// which saves allocations for services that parse many short expressions.
+// Extensions of the Language must not keep the Parser in the Evaluables they return.
+func ReuseParsers() Language {
+	l := newLanguage()
+	l.setOption(option{name: "reuseParsers", value: true})
+	return l
+}
+
+// NewParser returns a Parser for the Language that can parse many expressions one after another.
+// Set the expression to parse with Reset.
+func (l Language) NewParser() *Parser {
+	return newParser("", l)
+}
+
+// Reset sets the expression to parse and clears the state of the Parser.
+func (p *Parser) Reset(expression string) {
+	p.reset(expression, p.Language)
+}
+
+func (p *Parser) reset(expression string, l Language) {
+	p.reader.Reset(expression)
+	p.expression = expression
+	p.scanner.Init(&p.reader)
+	p.scanner.Error = func(*scanner.Scanner, string) {}
+	p.scanner.Filename = expression + "\t"
+	p.Language = l
+	p.lastScan = 0
+	p.camouflage = nil
+	p.nodes = l.observedNodes(expression)
+	p.float = nil
+	p.limits = l.limits()
+	p.nodeCount, p.depth = 0, 0
+	p.lets = nil
+	p.piped = nil
+	p.resetScannerProperties()
+}
+
+// Evaluable parses the whole expression given by Reset into an Evaluable.
+func (p *Parser) Evaluable(c context.Context) (Evaluable, error) {
+	eval, err := p.parseAll(c)
+	if err != nil {
+		return nil, err
+	}
+	return p.Language.applyOptions(eval, p.expression, p.nodeCount), nil
+}
+
 func (p *Parser) resetScannerProperties() {
 	p.scanner.Whitespace = scanner.GoWhitespace
 	p.scanner.Mode = scanner.GoTokens
@@ -113,20 +184,42 @@ func (p *Parser) TokenText() string {
 	return p.scanner.TokenText()
 }
 
-// Expected returns an error signaling an unexpected Scan() result
+// Expected returns a *SyntaxError signaling an unexpected Scan() result
 func (p *Parser) Expected(unit string, expected ...rune) error {
-	return unexpectedRune{unit, expected, p.lastScan}
+	pos := p.scanner.Position
+	return &SyntaxError{
+		Offset:   pos.Offset,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Token:    p.scanner.TokenText(),
+		Got:      p.lastScan,
+		Unit:     unit,
+		Expected: expected,
+	}
 }
 
-type unexpectedRune struct {
-	unit     string
-	expected []rune
-	got      rune
+// SyntaxError is the error of an expression that can not be parsed.
+// Errors returned by the Language are wrapped,
+// use errors.As to get the SyntaxError.
+type SyntaxError struct {
+	// Offset is the byte offset of the offending token in the expression,
+	// Line and Column its position starting at 1.
+	Offset       int
+	Line, Column int
+	// Token is the text of the offending token, empty at the end of the expression.
+	Token string
+	// Got is the offending token as returned by Parser.Scan(), e.g. scanner.Ident or '+'.
+	Got rune
+	// Unit is the part of the expression that was scanned, e.g. "parentheses".
+	Unit string
+	// Expected are the tokens that would have been valid.
+	// Use scanner.TokenString to print them.
+	Expected []rune
 }
 
-func (err unexpectedRune) Error() string {
+func (err *SyntaxError) Error() string {
 	exp := bytes.Buffer{}
-	runes := err.expected
+	runes := err.Expected
 	switch len(runes) {
 	default:
 		for _, r := range runes[:len(runes)-2] {
@@ -141,7 +234,7 @@ func (err unexpectedRune) Error() string {
 	case 1:
 		exp.WriteString(scanner.TokenString(runes[len(runes)-1]))
 	case 0:
-		return fmt.Sprintf("unexpected %s while scanning %s", scanner.TokenString(err.got), err.unit)
+		return fmt.Sprintf("unexpected %s while scanning %s", scanner.TokenString(err.Got), err.Unit)
 	}
-	return fmt.Sprintf("unexpected %s while scanning %s expected %s", scanner.TokenString(err.got), err.unit, exp.String())
+	return fmt.Sprintf("unexpected %s while scanning %s expected %s", scanner.TokenString(err.Got), err.Unit, exp.String())
 }
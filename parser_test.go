@@ -1,8 +1,13 @@
 package gval
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 	"text/scanner"
+	"time"
 	"unicode"
 )
 
@@ -146,3 +151,157 @@ func TestParser_Scan(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_Reset(t *testing.T) {
+	p := Full(WithLocation(time.UTC)).NewParser()
+	tests := []struct {
+		expression string
+		want       interface{}
+		wantErr    bool
+	}{
+		{expression: "1 + 2", want: 3.},
+		{expression: "1 +", wantErr: true},
+		{expression: `"a" + "b"`, want: "ab"},
+		{expression: "(1", wantErr: true},
+		{expression: `date("2020-01-02 03:04:05") == date("2020-01-02T03:04:05Z")`, want: true},
+		{expression: "false ? 1 : 2", want: 2.},
+	}
+	for _, tt := range tests {
+		p.Reset(tt.expression)
+		eval, err := p.Evaluable(context.Background())
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("Parser.Evaluable(%s) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		got, err := eval(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("Evaluate(%s) error = %v", tt.expression, err)
+		}
+		if got != tt.want {
+			t.Errorf("Evaluate(%s) = %v, want %v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestParser_ReusedSelector(t *testing.T) {
+	lang := NewLanguage(Full(), ReuseParsers(), VariableSelector(func(path Evaluables) Evaluable {
+		eval := variable(path)
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			r, err := eval(c, v)
+			if s, ok := r.(string); ok {
+				return s + "!", err
+			}
+			return r, err
+		}
+	}))
+	parameter := map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}},
+	}
+	eval, err := lang.NewEvaluable(`items[*].name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// parse another expression so the pooled Parser is reused
+	if _, err := Full(ReuseParsers()).NewEvaluable("1 + 2"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := eval(context.Background(), parameter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"a!", "b!"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Evaluate() = %v, want %v", got, want)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       SyntaxError
+	}{
+		{
+			expression: "(1 + 2",
+			want:       SyntaxError{Offset: 6, Line: 1, Column: 7, Got: scanner.EOF, Unit: "parentheses", Expected: []rune{')'}},
+		},
+		{
+			expression: "foo(1\n 2)",
+			want:       SyntaxError{Offset: 7, Line: 2, Column: 2, Token: "2", Got: scanner.Int, Unit: "arguments", Expected: []rune{')', ','}},
+		},
+		{
+			expression: "true ? 1 ]",
+			want:       SyntaxError{Offset: 9, Line: 1, Column: 10, Token: "]", Got: ']', Unit: "<> ? <> : <>", Expected: []rune{':', scanner.EOF}},
+		},
+	}
+	for _, tt := range tests {
+		_, err := Full().NewEvaluable(tt.expression)
+		var got *SyntaxError
+		if !errors.As(err, &got) {
+			t.Fatalf("NewEvaluable(%q) error = %v, want *SyntaxError", tt.expression, err)
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("NewEvaluable(%q) error = %#v, want %#v", tt.expression, *got, tt.want)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "line comments",
+				expression: "age >= 18 // adults only\n && country == \"DE\" // see audit 42",
+				parameter:  map[string]interface{}{"age": 20, "country": "DE"},
+				want:       true,
+			},
+			{
+				name:       "block comments",
+				expression: "/* base */ 2 * /* factor */ 3 /* unused: 4 */",
+				want:       6.0,
+			},
+			{
+				name:       "comment markers in strings",
+				expression: `"http://example.com/*" + "*/"`,
+				want:       "http://example.com/**/",
+			},
+			{
+				name:       "division",
+				expression: "6 / /* by */ 2",
+				want:       3.0,
+			},
+		},
+		t,
+	)
+}
+
+func TestParser_KeptParser(t *testing.T) {
+	// the extension keeps the Parser, which is allowed unless the Language reuses Parsers
+	lang := NewLanguage(Full(), PrefixExtension('@', func(c context.Context, p *Parser) (Evaluable, error) {
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			return p.Var(p.Const("name"))(c, v)
+		}, nil
+	}), VariableSelector(func(path Evaluables) Evaluable {
+		eval := variable(path)
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			r, err := eval(c, v)
+			return fmt.Sprintf("<%v>", r), err
+		}
+	}))
+	eval, err := lang.NewEvaluable(`@`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := Full(ReuseParsers()).NewEvaluable("1 + 2"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := lang.NewEvaluable(`"other"`); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := eval(context.Background(), map[string]interface{}{"name": "a"})
+	if err != nil || got != "<a>" {
+		t.Errorf("Evaluate(@) = %v, %v, want <a>", got, err)
+	}
+}
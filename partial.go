@@ -0,0 +1,49 @@
+package gval
+
+import (
+	"context"
+)
+
+// PartialEvaluate replaces the variables of the expression that are selectable from known by their values
+// and returns the optimized residual expression (see Language.Optimize).
+// E.g. with known {"config": {"limit": 10}} the expression config.limit < 2 * 3 + amount
+// becomes 10 < 6 + amount.
+//
+// Known values without literal representation in lang are not replaced.
+func PartialEvaluate(expression string, known map[string]interface{}, lang Language) (string, error) {
+	node, err := lang.Parse(expression)
+	if err != nil {
+		return "", err
+	}
+	return Format(lang.optimize(lang.substitute(node, known), false)), nil
+}
+
+// substitute returns a copy of n with known variables replaced by constants.
+func (l Language) substitute(n *Node, known map[string]interface{}) *Node {
+	if n == nil {
+		return nil
+	}
+	if n.Kind == VariableNode {
+		if _, ok := known[n.Path[0]]; !ok {
+			return n
+		}
+		v, err := selectKeys(context.Background(), known, n.Path, true)
+		if err != nil {
+			return n
+		}
+		if lit, ok := l.literalNode(n, v); ok {
+			lit.parenthesized = n.parenthesized
+			return lit
+		}
+		return n
+	}
+	if len(n.Children) == 0 {
+		return n
+	}
+	m := *n
+	m.Children = make([]*Node, len(n.Children))
+	for i, child := range n.Children {
+		m.Children[i] = l.substitute(child, known)
+	}
+	return &m
+}
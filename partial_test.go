@@ -0,0 +1,48 @@
+package gval
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartialEvaluate(t *testing.T) {
+	known := map[string]interface{}{
+		"config": map[string]interface{}{
+			"limit":   10.,
+			"enabled": true,
+			"regions": []interface{}{"eu", "us"},
+		},
+		"deployedAt": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+	}{
+		{name: "substitute and fold", expression: "config.limit < 2 * 3 + amount", want: "10 < 6 + amount"},
+		{name: "fully known", expression: "config.limit * 2", want: "20"},
+		{name: "short circuit", expression: "!config.enabled && amount > config.limit", want: "false"},
+		{name: "short circuit not taken", expression: "config.enabled && amount > config.limit", want: "true && amount > 10"},
+		{name: "array", expression: `region in config.regions`, want: `region in ["eu", "us"]`},
+		{name: "array element", expression: `config.regions[1] == region`, want: `"us" == region`},
+		{name: "ternary", expression: `config.enabled ? amount : 0`, want: `amount`},
+		{name: "unknown nested key", expression: `config.missing ?? 1`, want: `config.missing ?? 1`},
+		{name: "no literal", expression: `deployedAt`, want: `deployedAt`},
+		{name: "prefix", expression: `-config.limit + amount`, want: `-10 + amount`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PartialEvaluate(tt.expression, known, Full())
+			if err != nil {
+				t.Fatalf("PartialEvaluate(%s) error = %v", tt.expression, err)
+			}
+			if got != tt.want {
+				t.Errorf("PartialEvaluate(%s) = %s, want %s", tt.expression, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := PartialEvaluate("config.limit <", known, Full()); err == nil {
+		t.Errorf("PartialEvaluate() expected parse error")
+	}
+}
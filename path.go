@@ -0,0 +1,298 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// selectFrom returns an Evaluable selecting the path keys from the result of base.
+// If base is nil the keys are selected from the parameter.
+// If multi is true base yields []interface{} and the keys are selected from each element.
+// Elements which do not contain the path are skipped.
+func (p *Parser) selectFrom(base Evaluable, keys []Evaluable, multi bool) Evaluable {
+	if base == nil {
+		return p.Var(keys...)
+	}
+	if len(keys) == 0 {
+		return base
+	}
+	vars := p.vars()
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		b, err := base(c, v)
+		if err != nil {
+			return nil, err
+		}
+		path := make([]Evaluable, len(keys))
+		for i, key := range keys {
+			k, err := key(c, v)
+			if err != nil {
+				return nil, err
+			}
+			path[i] = constant(k)
+		}
+		if !multi {
+			return vars(path...)(c, b)
+		}
+		values := b.([]interface{})
+		r := make([]interface{}, 0, len(values))
+		for i, value := range values {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
+			if s, ok := selectPresent(c, vars, value, path); ok {
+				r = append(r, s)
+			}
+		}
+		return r, nil
+	}
+}
+
+// selectPresent selects path from value key by key.
+// It returns false if a map does not contain a key, an index is out of the range of an array or the selection fails.
+func selectPresent(c context.Context, vars func(...Evaluable) Evaluable, value interface{}, path []Evaluable) (interface{}, bool) {
+	for _, key := range path {
+		if k, err := key.EvalString(c, nil); err == nil {
+			if _, ok := mapValue(value, k); !ok && isMap(value) {
+				return nil, false
+			}
+			if a, ok := value.([]interface{}); ok {
+				if _, ok := sliceIndex(k, len(a)); !ok {
+					return nil, false
+				}
+			}
+		}
+		var err error
+		value, err = vars(key)(c, value)
+		if err != nil {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// sliceValue returns an Evaluable slicing the result of e from index from to index to.
+// Negative indices count from the end, nil indices default to the start or the end.
+// Arrays, slices and strings can be sliced.
+// If multi is true e yields []interface{} and each element is sliced.
+func sliceValue(e, from, to Evaluable, multi bool) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		o, err := e(c, v)
+		if err != nil {
+			return nil, err
+		}
+		var bounds [2]interface{}
+		for i, b := range [2]Evaluable{from, to} {
+			bounds[i], err = b(c, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !multi {
+			return slice(o, bounds[0], bounds[1])
+		}
+		values := o.([]interface{})
+		r := make([]interface{}, 0, len(values))
+		for i, value := range values {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
+			if s, err := slice(value, bounds[0], bounds[1]); err == nil {
+				r = append(r, s)
+			}
+		}
+		return r, nil
+	}
+}
+
+func slice(o, from, to interface{}) (interface{}, error) {
+	s, isString := o.(string)
+	runes := []rune(s)
+	length := len(runes)
+	elements, isSlice := convertToSlice(o)
+	if isSlice {
+		length = len(elements)
+	} else if !isString {
+		return nil, fmt.Errorf("can not slice %v (%T)", o, o)
+	}
+
+	bounds := [2]int{0, length}
+	for i, x := range [2]interface{}{from, to} {
+		if x == nil {
+			continue
+		}
+		f, ok := convertToFloat(x)
+		if !ok {
+			return nil, fmt.Errorf("unexpected slice index %v (%T) expected number", x, x)
+		}
+		n := int(f)
+		if n < 0 {
+			n += length
+		}
+		if n < 0 {
+			n = 0
+		}
+		if n > length {
+			n = length
+		}
+		bounds[i] = n
+	}
+	if bounds[1] < bounds[0] {
+		bounds[1] = bounds[0]
+	}
+
+	if isString {
+		return string(runes[bounds[0]:bounds[1]]), nil
+	}
+	return append([]interface{}{}, elements[bounds[0]:bounds[1]]...), nil
+}
+
+// wildcardValue returns an Evaluable yielding all children of the result of e as []interface{}.
+// Map values are ordered by key.
+// If multi is true e yields []interface{} and the children of all elements are returned.
+func wildcardValue(e Evaluable, multi bool) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		o, err := e(c, v)
+		if err != nil {
+			return nil, err
+		}
+		if !multi {
+			children, ok := childValues(o)
+			if !ok {
+				return nil, fmt.Errorf("can not select [*] on %v (%T)", o, o)
+			}
+			return children, nil
+		}
+		r := []interface{}{}
+		for i, value := range o.([]interface{}) {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
+			children, _ := childValues(value)
+			r = append(r, children...)
+		}
+		return r, nil
+	}
+}
+
+// descendValue returns an Evaluable collecting the values of all keys named key
+// in the result of e and recursively in all of its children as []interface{}.
+// If multi is true e yields []interface{} and the values are collected from each element.
+func descendValue(e Evaluable, key string, multi bool) Evaluable {
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		o, err := e(c, v)
+		if err != nil {
+			return nil, err
+		}
+		roots := []interface{}{o}
+		if multi {
+			roots = o.([]interface{})
+		}
+		r := []interface{}{}
+		visited := 0
+		ancestors := map[containerKey]struct{}{}
+		for _, root := range roots {
+			if r, err = collectKey(c, &visited, ancestors, r, root, key); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+}
+
+// collectKey appends the values of all keys named key in o and its children to collected.
+// visited counts the values visited so far to check the context periodically.
+// ancestors are the maps and slices o is contained in, they are not descended into again.
+func collectKey(c context.Context, visited *int, ancestors map[containerKey]struct{}, collected []interface{}, o interface{}, key string) ([]interface{}, error) {
+	*visited++
+	if err := CheckContext(c, *visited); err != nil {
+		return nil, err
+	}
+	if container, ok := containerOf(o); ok {
+		if _, cycle := ancestors[container]; cycle {
+			return collected, nil
+		}
+		ancestors[container] = struct{}{}
+		defer delete(ancestors, container)
+	}
+	if value, ok := mapValue(o, key); ok {
+		collected = append(collected, value)
+	}
+	children, _ := childValues(o)
+	var err error
+	for _, child := range children {
+		if collected, err = collectKey(c, visited, ancestors, collected, child, key); err != nil {
+			return nil, err
+		}
+	}
+	return collected, nil
+}
+
+// containerKey identifies a map or slice to detect cycles of parameters containing themselves.
+type containerKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// containerOf returns the containerKey of a non-empty map or slice o.
+func containerOf(o interface{}) (containerKey, bool) {
+	v := resolvePotentialPointer(reflect.ValueOf(o))
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		if v.Len() > 0 {
+			return containerKey{v.Type(), v.Pointer(), v.Len()}, true
+		}
+	}
+	return containerKey{}, false
+}
+
+func mapValue(o interface{}, key string) (interface{}, bool) {
+	switch o := o.(type) {
+	case map[string]interface{}:
+		value, ok := o[key]
+		return value, ok
+	case map[interface{}]interface{}:
+		return interfaceMapValue(o, key)
+	}
+	vv := resolvePotentialPointer(reflect.ValueOf(o))
+	if vv.Kind() != reflect.Map || vv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	value := vv.MapIndex(reflect.ValueOf(key).Convert(vv.Type().Key()))
+	if !value.IsValid() {
+		return nil, false
+	}
+	return value.Interface(), true
+}
+
+func isMap(o interface{}) bool {
+	return resolvePotentialPointer(reflect.ValueOf(o)).Kind() == reflect.Map
+}
+
+// childValues returns the elements of slices and arrays and the values of maps ordered by key.
+func childValues(o interface{}) ([]interface{}, bool) {
+	if s, ok := convertToSlice(o); ok {
+		return s, true
+	}
+	vv := resolvePotentialPointer(reflect.ValueOf(o))
+	if vv.Kind() != reflect.Map {
+		return nil, false
+	}
+	keys := sortedMapKeys(vv)
+	values := make([]interface{}, len(keys))
+	for i, k := range keys {
+		values[i] = vv.MapIndex(k).Interface()
+	}
+	return values, true
+}
+
+// sortedMapKeys returns the keys of the map m ordered by their text.
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
@@ -0,0 +1,238 @@
+package gval
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPathSelectors(t *testing.T) {
+	orders := map[string]interface{}{
+		"orders": []interface{}{
+			map[string]interface{}{"id": "a", "total": 10., "items": []interface{}{"x", "y"}},
+			map[string]interface{}{"id": "b", "total": 20., "items": []interface{}{"z"}},
+			map[string]interface{}{"id": "c"},
+		},
+		"payload": map[string]interface{}{
+			"id": 1.,
+			"customer": map[string]interface{}{
+				"id":   2.,
+				"tags": []interface{}{map[string]interface{}{"id": 3.}},
+			},
+			"typed": map[string]int{"id": 4},
+		},
+		"prices": map[string]interface{}{"b": 2., "a": 1.},
+	}
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "wildcard",
+				expression: `orders[*].total`,
+				parameter:  orders,
+				want:       []interface{}{10., 20.},
+			},
+			{
+				name:       "wildcard elements",
+				expression: `orders[1:][*].id`,
+				parameter:  orders,
+				want:       []interface{}{"b", "c"},
+			},
+			{
+				name:       "wildcard map ordered by key",
+				expression: `prices[*]`,
+				parameter:  orders,
+				want:       []interface{}{1., 2.},
+			},
+			{
+				name:       "nested wildcard",
+				expression: `orders[*].items[*]`,
+				parameter:  orders,
+				want:       []interface{}{"x", "y", "z"},
+			},
+			{
+				name:       "wildcard index",
+				expression: `orders[*].items[0]`,
+				parameter:  orders,
+				want:       []interface{}{"x", "z"},
+			},
+			{
+				name:       "wildcard slice",
+				expression: `orders[*].items[-1:]`,
+				parameter:  orders,
+				want:       []interface{}{[]interface{}{"y"}, []interface{}{"z"}},
+			},
+			{
+				name:       "wildcard with in",
+				expression: `"b" in orders[*].id`,
+				parameter:  orders,
+				want:       true,
+			},
+			{
+				name:       "recursive descent",
+				expression: `payload..id`,
+				parameter:  orders,
+				want:       []interface{}{1., 2., 3., 4},
+			},
+			{
+				name:       "recursive descent after wildcard",
+				expression: `orders[*]..id`,
+				parameter:  orders,
+				want:       []interface{}{"a", "b", "c"},
+			},
+			{
+				name:       "recursive descent on nested path",
+				expression: `payload.customer..id`,
+				parameter:  orders,
+				want:       []interface{}{2., 3.},
+			},
+			{
+				name:       "wildcard on scalar",
+				expression: `payload.id[*]`,
+				parameter:  orders,
+				wantErr:    "can not select [*] on 1 (float64)",
+			},
+			{
+				name:       "incomplete wildcard",
+				expression: `orders[*`,
+				parameter:  orders,
+				wantErr:    "unexpected EOF while scanning wildcard expected \"]\"",
+			},
+			{
+				name:       "incomplete recursive descent",
+				expression: `orders..`,
+				parameter:  orders,
+				wantErr:    "unexpected EOF while scanning recursive descent expected Ident",
+			},
+		},
+		t,
+	)
+}
+
+func TestRecursiveDescentCycle(t *testing.T) {
+	node := map[string]interface{}{"id": 1.}
+	list := []interface{}{node, nil}
+	list[1] = list
+	node["self"] = node
+	node["list"] = list
+	shared := map[string]interface{}{"id": 2.}
+	parameter := map[string]interface{}{"node": node, "list": list, "shared": []interface{}{shared, shared}}
+
+	for expression, want := range map[string]interface{}{
+		"node..id":   []interface{}{1.},
+		"list..id":   []interface{}{1.},
+		"node..self": []interface{}{node},
+		"shared..id": []interface{}{2., 2.},
+	} {
+		got, err := Evaluate(expression, parameter)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			// the values contain themselves and can not be printed
+			values, _ := got.([]interface{})
+			t.Errorf("Evaluate(%s) = %d values, %v, want %d values", expression, len(values), err, len(want.([]interface{})))
+		}
+	}
+}
+
+func TestOperandSelectors(t *testing.T) {
+	lookup := Function("lookup", func(id string) map[string]interface{} {
+		return map[string]interface{}{
+			"status": "open",
+			"items":  []interface{}{map[string]interface{}{"price": 2.}, map[string]interface{}{"price": 3.}},
+			"label":  func(prefix string) string { return prefix + id },
+		}
+	})
+	split := Function("split", strings.Split)
+	parameter := map[string]interface{}{"name": "Ada Lovelace", "a": map[string]interface{}{"b": []interface{}{1., 2.}}}
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "index of call result",
+				expression: `split(name, " ")[0]`,
+				extension:  split,
+				parameter:  parameter,
+				want:       "Ada",
+			},
+			{
+				name:       "slice of call result",
+				expression: `split(name, " ")[1:]`,
+				extension:  split,
+				parameter:  parameter,
+				want:       []interface{}{"Lovelace"},
+			},
+			{
+				name:       "field of call result",
+				expression: `lookup("x").status`,
+				extension:  lookup,
+				want:       "open",
+			},
+			{
+				name:       "path of call result",
+				expression: `-lookup("x").items[1].price * 2`,
+				extension:  lookup,
+				want:       -6.,
+			},
+			{
+				name:       "wildcard of call result",
+				expression: `lookup("x").items[*].price`,
+				extension:  lookup,
+				want:       []interface{}{2., 3.},
+			},
+			{
+				name:       "method of call result",
+				expression: `lookup("x").label("#")`,
+				extension:  lookup,
+				want:       "#x",
+			},
+			{
+				name:       "index of parentheses",
+				expression: `(a).b[1] + (a.b)[0]`,
+				parameter:  parameter,
+				want:       3.,
+			},
+			{
+				name:       "index of array literal",
+				expression: `[10, 20, 30][1]`,
+				want:       20.,
+			},
+			{
+				name:       "field of object literal",
+				expression: `{"a": {"b": 1}}.a.b`,
+				want:       1.,
+			},
+			{
+				name:       "missing field of call result",
+				expression: `lookup("x").label.x`,
+				extension:  lookup,
+				wantErr:    "unknown parameter",
+			},
+		},
+		t,
+	)
+}
+
+func TestDynamicKeys(t *testing.T) {
+	config := map[string]interface{}{
+		"prod": map[string]interface{}{"threshold": 5., "regions": []interface{}{"eu", "us"}},
+		"dev":  map[string]interface{}{"threshold": 1.},
+	}
+	configOf := Function("config", func() interface{} { return config })
+	parameter := map[string]interface{}{"env": "prod", "i": 1., "config": config, "payload": `{"prod": {"x": 3}}`}
+
+	testEvaluate(
+		[]evaluationTest{
+			{name: "variable key", expression: `config[env].threshold`, parameter: parameter, want: 5.},
+			{name: "variable keys", expression: `config[env].regions[i] + config[env]["regions"][i - 1]`, parameter: parameter, want: "useu"},
+			{name: "variable key of call result", expression: `config()[env].threshold`, extension: configOf, parameter: parameter, want: 5.},
+			{name: "variable key of builtin result", expression: `jsonDecode(payload)[env].x`, parameter: parameter, want: 3.},
+			{name: "variable key of literal", expression: `{"prod": {"x": 2}, "dev": {"x": 1}}[env].x`, parameter: parameter, want: 2.},
+			{name: "variable key of parentheses", expression: `(env == "prod" ? config : {})[env].threshold`, parameter: parameter, want: 5.},
+			{name: "variable key of pipeline", expression: `config |> keys()[i]`, parameter: parameter, want: "prod"},
+			{name: "expression key", expression: `config[env == "prod" ? "dev" : "prod"].threshold`, parameter: parameter, want: 1.},
+			{name: "nested variable keys", expression: `config[keys(config)[i]].threshold`, parameter: parameter, want: 5.},
+			{name: "variable key in operations", expression: `-config[env].threshold * 2 < config()[env]["threshold"]`, extension: configOf, parameter: parameter, want: true},
+			{name: "missing variable key", expression: `config["test"].threshold`, parameter: parameter, wantErr: "unknown parameter"},
+		},
+		t,
+	)
+}
@@ -0,0 +1,86 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/scanner"
+)
+
+// Pipeline returns a Language with the pipeline operator |> calling the function on its right
+// with the value on its left as first argument, e.g. name |> trim |> startsWith("prem") is startsWith(trim(name), "prem").
+// The parentheses of calls without further arguments can be omitted.
+// The operand of |> is the preceding operation with a higher precedence, e.g. a + b |> round is round(a + b).
+func Pipeline() Language {
+	return pipeline
+}
+
+var pipeline = NewLanguage(
+	PostfixOperator("|>", parsePipe),
+	Precedence("|>", 10),
+)
+
+func parsePipe(c context.Context, p *Parser, eval Evaluable) (Evaluable, error) {
+	if p.Scan() != scanner.Ident {
+		return nil, p.Expected("pipeline", scanner.Ident)
+	}
+	name, pos := p.TokenText(), p.scanner.Position.Offset
+	// namespaced functions like math.round
+	for p.Peek() == '.' && p.isFunctionPrefix(name+".") {
+		p.Next()
+		if p.Scan() != scanner.Ident {
+			return nil, p.Expected("pipeline", scanner.Ident)
+		}
+		name += "." + p.TokenText()
+	}
+	t, ok := p.functions[name]
+	if !ok {
+		return nil, fmt.Errorf("|> expects a function but got %s", name)
+	}
+	if t == nil {
+		// prefixes like exists that are called like functions
+		return nil, fmt.Errorf("%s() can not be called by |>", name)
+	}
+	p.piped = eval
+	if p.nodes == nil {
+		return p.pipeCall(c, name)
+	}
+	// the call is recorded as operand to be formatted like a function call
+	p.nodes.push()
+	call, err := p.pipeCall(c, name)
+	f := p.nodes.pop()
+	if err != nil {
+		return nil, err
+	}
+	p.nodes.add(p.nodes.operand(f, pos, p.offset(), call))
+	return call, nil
+}
+
+// pipeCall parses the call of the function name and the selectors following it like keys()[0].
+func (p *Parser) pipeCall(c context.Context, name string) (Evaluable, error) {
+	call, err := p.prefixes[name](c, p)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseOperandSelectors(c, call)
+}
+
+// isFunctionPrefix returns if a function name starts with prefix.
+func (p *Parser) isFunctionPrefix(prefix string) bool {
+	for name := range p.functions {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// pipedArguments returns the arguments of a function call starting with the value piped into it by |>.
+func (p *Parser) pipedArguments() []Evaluable {
+	if p.piped == nil {
+		return []Evaluable{}
+	}
+	args := []Evaluable{p.piped}
+	p.piped = nil
+	return args
+}
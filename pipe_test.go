@@ -0,0 +1,39 @@
+package gval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	l := NewLanguage(Full(),
+		Function("trim", strings.TrimSpace, Pure()),
+		Function("lower", strings.ToLower, Pure()),
+		Function("startsWith", strings.HasPrefix, Pure()),
+		Functions("str", map[string]interface{}{"repeat": strings.Repeat}),
+		Function("pad", func(s string, width int, fill string) string {
+			for len(s) < width {
+				s = fill + s
+			}
+			return s
+		}, Parameters("s", "width", "fill"), Default("width", 4), Default("fill", "0")),
+	)
+	parameter := map[string]interface{}{"name": "  Premium Gold ", "tags": []interface{}{"a", "b"}}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "chain", expression: `name |> trim |> lower |> startsWith("prem")`, extension: l, parameter: parameter, want: true},
+			{name: "parentheses", expression: `name |> trim() |> lower()`, extension: l, parameter: parameter, want: "premium gold"},
+			{name: "operand", expression: `"a" + "b" |> str.repeat(2)`, extension: l, want: "abab"},
+			{name: "following operator", expression: `name |> trim == "Premium Gold" && true`, extension: l, parameter: parameter, want: true},
+			{name: "builtin", expression: `tags |> len`, extension: l, parameter: parameter, want: 2.},
+			{name: "defaults", expression: `"7" |> pad`, extension: l, want: "0007"},
+			{name: "named", expression: `"7" |> pad(fill: "x")`, extension: l, want: "xxx7"},
+			{name: "argument", expression: `startsWith(name |> trim, "P")`, extension: l, parameter: parameter, want: true},
+			{name: "unknown function", expression: `name |> nope`, extension: l, wantErr: "|> expects a function but got nope"},
+			{name: "no function", expression: `name |> 1`, extension: l, wantErr: "unexpected Int while scanning pipeline expected Ident"},
+			{name: "too many arguments", expression: `name |> trim(1)`, extension: l, wantErr: "trim() expects 1 arguments but got 2"},
+			{name: "presence", expression: `name |> exists`, extension: l, wantErr: "exists() can not be called by |>"},
+		},
+		t,
+	)
+}
@@ -0,0 +1,243 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Program is an expression compiled to bytecode by Language.Compile.
+// A Program is evaluated by a small stack machine instead of a tree of closures
+// and can be evaluated concurrently.
+//
+// Constants, variables and infix operators are executed by the machine.
+// Other operands like function calls, postfix operators and operators defined by InfixEvalOperator
+// are evaluated by their Evaluable.
+type Program struct {
+	code      []instruction
+	constants []interface{}
+	paths     [][]string
+	evals     []Evaluable
+	infixes   []*infix
+	// nodes are the nodes of the instructions, the source of EvalErrors
+	nodes []*Node
+	// depth is the number of values pushed by the code, an upper bound of the stack size
+	depth  int
+	stacks sync.Pool
+	eval   Evaluable
+}
+
+type opcode uint8
+
+const (
+	// opConst pushes constants[arg]
+	opConst opcode = iota
+	// opVar pushes the variable paths[arg] of the parameter
+	opVar
+	// opEval pushes the result of evals[arg]
+	opEval
+	// opInfix replaces the two topmost values by the result of infixes[arg]
+	opInfix
+	// opShortCircuit replaces the topmost value by the short circuit of infixes[arg]
+	// and continues at jump if the operator short circuits
+	opShortCircuit
+)
+
+var opcodeNames = [...]string{
+	opConst:        "const",
+	opVar:          "var",
+	opEval:         "eval",
+	opInfix:        "infix",
+	opShortCircuit: "short",
+}
+
+type instruction struct {
+	op   opcode
+	arg  int
+	jump int
+}
+
+// Compile compiles the expression into a Program.
+func (l Language) Compile(expression string) (*Program, error) {
+	return l.CompileWithContext(context.Background(), expression)
+}
+
+// CompileWithContext compiles the expression into a Program using context.
+func (l Language) CompileWithContext(c context.Context, expression string) (*Program, error) {
+	node, eval, nodes, err := l.parseNodes(c, expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &Program{}
+	if _, observed := l.observer(); node == nil || l.init != nil || l.limits() != nil || observed {
+		// the init extension may do anything with the parsed expressions
+		// and limits are checked and observers notified by the Evaluables
+		p.emit(opEval, p.addEval(eval), nil)
+	} else {
+		p.compile(l, node)
+	}
+	p.stacks.New = func() interface{} {
+		stack := make([]interface{}, 0, p.depth)
+		return &stack
+	}
+	p.eval = p.run
+	if len(p.code) == 1 && p.code[0].op == opConst {
+		p.eval = constant(p.constants[0])
+	}
+	p.eval = l.applyOptions(p.eval, expression, nodes)
+	return p, nil
+}
+
+// Evaluate evaluates the Program with given parameter.
+func (p *Program) Evaluate(c context.Context, parameter interface{}) (interface{}, error) {
+	return p.eval(c, parameter)
+}
+
+// Evaluable returns the Program as Evaluable.
+func (p *Program) Evaluable() Evaluable {
+	return p.eval
+}
+
+// String returns the bytecode of the Program, one instruction per line.
+func (p *Program) String() string {
+	b := &strings.Builder{}
+	for i, in := range p.code {
+		fmt.Fprintf(b, "%d\t%s\t", i, opcodeNames[in.op])
+		switch in.op {
+		case opConst:
+			fmt.Fprintf(b, "%#v", p.constants[in.arg])
+		case opVar:
+			writePath(b, p.paths[in.arg])
+		case opEval, opInfix:
+			fmt.Fprintf(b, "%d", in.arg)
+		case opShortCircuit:
+			fmt.Fprintf(b, "%d -> %d", in.arg, in.jump)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// compile appends the code of n.
+func (p *Program) compile(l Language, n *Node) {
+	if n.constant {
+		p.emit(opConst, p.addConstant(n.Value), n)
+		return
+	}
+	switch n.Kind {
+	case VariableNode:
+		if l.selector == nil {
+			p.emit(opVar, p.addPath(n.Path), n)
+			return
+		}
+	case InfixNode:
+		op, ok := l.operators[n.Operator].(*infix)
+		if !ok || op.f == nil || n.chain {
+			// chained comparisons are no && operations of their own
+			break
+		}
+		op = l.modeOperator(n.Operator, op)
+		if a := n.Children[0]; a.constant && op.shortCircuit != nil {
+			if r, ok := op.shortCircuit(a.Value); ok {
+				p.emit(opConst, p.addConstant(r), n)
+				return
+			}
+		}
+		arg := len(p.infixes)
+		p.infixes = append(p.infixes, op)
+		p.compile(l, n.Children[0])
+		short := -1
+		if op.shortCircuit != nil {
+			short = p.emit(opShortCircuit, arg, n)
+		}
+		p.compile(l, n.Children[1])
+		p.emit(opInfix, arg, n)
+		if short >= 0 {
+			p.code[short].jump = len(p.code)
+		}
+		return
+	}
+	p.emit(opEval, p.addEval(n.eval), n)
+}
+
+func (p *Program) emit(op opcode, arg int, n *Node) int {
+	p.code = append(p.code, instruction{op: op, arg: arg})
+	p.nodes = append(p.nodes, n)
+	if op != opInfix && op != opShortCircuit {
+		p.depth++
+	}
+	return len(p.code) - 1
+}
+
+func (p *Program) addConstant(v interface{}) int {
+	p.constants = append(p.constants, v)
+	return len(p.constants) - 1
+}
+
+func (p *Program) addPath(path []string) int {
+	p.paths = append(p.paths, path)
+	return len(p.paths) - 1
+}
+
+func (p *Program) addEval(eval Evaluable) int {
+	p.evals = append(p.evals, eval)
+	return len(p.evals) - 1
+}
+
+func (p *Program) run(c context.Context, parameter interface{}) (interface{}, error) {
+	s := p.stacks.Get().(*[]interface{})
+	v, err := p.exec(c, parameter, (*s)[:0])
+	stack := (*s)[:cap(*s)]
+	for i := range stack {
+		stack[i] = nil
+	}
+	p.stacks.Put(s)
+	return v, err
+}
+
+func (p *Program) exec(c context.Context, parameter interface{}, stack []interface{}) (interface{}, error) {
+	for pc := 0; pc < len(p.code); pc++ {
+		in := p.code[pc]
+		switch in.op {
+		case opConst:
+			stack = append(stack, p.constants[in.arg])
+		case opVar:
+			v, err := selectKeys(c, parameter, p.paths[in.arg], false)
+			if err != nil {
+				return nil, p.evalError(err, pc)
+			}
+			stack = append(stack, v)
+		case opEval:
+			v, err := p.evals[in.arg](c, parameter)
+			if err != nil {
+				return nil, p.evalError(err, pc)
+			}
+			stack = append(stack, v)
+		case opInfix:
+			top := len(stack) - 1
+			v, err := p.infixes[in.arg].f(stack[top-1], stack[top])
+			if err != nil {
+				return nil, p.evalError(err, pc)
+			}
+			stack[top-1] = v
+			stack = stack[:top]
+		case opShortCircuit:
+			top := len(stack) - 1
+			if r, ok := p.infixes[in.arg].shortCircuit(stack[top]); ok {
+				stack[top] = r
+				pc = in.jump - 1
+			}
+		}
+	}
+	return stack[0], nil
+}
+
+// evalError returns err as *EvalError of the node of the instruction at pc.
+func (p *Program) evalError(err error, pc int) error {
+	n := p.nodes[pc]
+	if n == nil {
+		return err
+	}
+	return newEvalError(err, n.source, n.start, n.end)
+}
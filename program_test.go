@@ -0,0 +1,154 @@
+package gval
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestProgram(t *testing.T) {
+	parameter := map[string]interface{}{
+		"a":   3.,
+		"b":   4.,
+		"s":   "text",
+		"t":   true,
+		"arr": []interface{}{1., 2., 3.},
+		"obj": map[string]interface{}{"x y": 5., "nested": map[string]interface{}{"z": "zz"}},
+	}
+	expressions := []string{
+		"1",
+		"a",
+		"a + b * 2",
+		"(a + b) * 2",
+		"a > b || s == \"text\"",
+		"t && a < b",
+		"!t || unknown.key == nil",
+		"false && unknown.key.deeper",
+		"obj[\"x y\"] + arr[1]",
+		"obj.nested.z + s",
+		"a in arr",
+		"t ? a : b",
+		"len(arr) ** 2",
+		"[a, b, arr[2]]",
+		"{\"sum\": a + b}",
+		"-a - -b",
+		"a ?? b",
+		"s =~ \"^te\" && 2 > 1",
+		"a == 3 ? \"three\" : \"other\"",
+	}
+	for _, expression := range expressions {
+		t.Run(expression, func(t *testing.T) {
+			eval, err := Full().NewEvaluable(expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, wantErr := eval(context.Background(), parameter)
+
+			program, err := Full().Compile(expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				got, err := program.Evaluate(context.Background(), parameter)
+				if (err != nil) != (wantErr != nil) {
+					t.Fatalf("Program.Evaluate() error = %v, want %v\n%s", err, wantErr, program)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("Program.Evaluate() = %v, want %v\n%s", got, want, program)
+				}
+			}
+		})
+	}
+}
+
+func TestProgram_errors(t *testing.T) {
+	if _, err := Full().Compile("a +"); err == nil {
+		t.Fatal("Compile() expected parsing error")
+	}
+	program, err := Full().Compile(`a - "x"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := program.Evaluate(context.Background(), map[string]interface{}{"a": 1.}); err == nil {
+		t.Fatal("Program.Evaluate() expected type error")
+	}
+}
+
+func TestProgram_language(t *testing.T) {
+	l := NewLanguage(Full(), CaseInsensitiveIdentifiers(), VariableSelector(func(path Evaluables) Evaluable {
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			keys, err := path.EvalStrings(c, v)
+			if err != nil {
+				return nil, err
+			}
+			return len(keys), nil
+		}
+	}))
+	program, err := l.Compile("a.b.c + x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := program.Evaluable().EvalInt(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 4 {
+		t.Fatalf("Program.Evaluate() = %v, want 4", got)
+	}
+}
+
+func TestProgram_modes(t *testing.T) {
+	for _, test := range []struct {
+		language    Language
+		expressions []string
+		parameter   map[string]interface{}
+	}{
+		{
+			language:    Full(Strict()),
+			expressions: []string{"s + 1", "s > 2", "b && 1", "n + 1", `s + "x"`, "b && !false", "false && s", "n > 2 && b"},
+			parameter:   map[string]interface{}{"s": "3", "n": 3., "b": true},
+		},
+		{
+			language:    Full(NilPropagation()),
+			expressions: []string{"n + 1", "(n + 1) ?? 0", "n > 1", "1 < n < 3", "m * n", "m * 2", "n == nil"},
+			parameter:   map[string]interface{}{"n": nil, "m": 2.},
+		},
+		{
+			language:    Full(Strict(), NilPropagation()),
+			expressions: []string{"n + 1", "s + 1", "m - n"},
+			parameter:   map[string]interface{}{"n": nil, "m": 2., "s": "1"},
+		},
+	} {
+		for _, expression := range test.expressions {
+			eval, err := test.language.NewEvaluable(expression)
+			if err != nil {
+				t.Fatalf("NewEvaluable(%s) error = %v", expression, err)
+			}
+			program, err := test.language.Compile(expression)
+			if err != nil {
+				t.Fatalf("Compile(%s) error = %v", expression, err)
+			}
+			want, wantErr := eval(context.Background(), test.parameter)
+			got, err := program.Evaluate(context.Background(), test.parameter)
+			if (err != nil) != (wantErr != nil) || !reflect.DeepEqual(got, want) {
+				t.Errorf("Compile(%s).Evaluate() = %v, %v, want %v, %v like NewEvaluable", expression, got, err, want, wantErr)
+			}
+		}
+	}
+}
+
+func TestProgram_String(t *testing.T) {
+	program, err := Full().Compile("a && b.c > 2 * 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "0\tvar\ta\n" +
+		"1\tshort\t0 -> 6\n" +
+		"2\tvar\tb.c\n" +
+		"3\tconst\t6\n" +
+		"4\tinfix\t1\n" +
+		"5\tinfix\t0\n"
+	if got := program.String(); got != want {
+		t.Fatalf("Program.String() =\n%s\nwant\n%s", got, want)
+	}
+}
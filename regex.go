@@ -0,0 +1,362 @@
+package gval
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Regex contains functions working on regular expressions.
+//
+//	matches(s, pattern)          true iff s contains a match of pattern
+//	replaceAll(s, pattern, repl) s with all matches of pattern replaced by repl,
+//	                             repl may reference groups with $1 or ${name}
+//	findAll(s, pattern)          []interface{} of all matches of pattern in s
+//	findAll(s, pattern, n)       []interface{} of at most n matches of pattern in s
+//
+// Compiled patterns are kept in a least recently used cache that is shared
+// across all evaluations, so a pattern is compiled only once.
+// The patterns and matches are restricted by WithRegexLimits like those of =~.
+func Regex() Language {
+	return regexLanguage
+}
+
+var regexLanguage = NewLanguage(
+	Function("matches", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 {
+			return nil, fmt.Errorf("matches() expects exactly two string arguments")
+		}
+		s, re, err := regexArguments(c, "matches", arguments[0], arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		return contextRegexLimits(c).matchString(c, re, s)
+	}),
+	Function("replaceAll", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 3 {
+			return nil, fmt.Errorf("replaceAll() expects exactly three string arguments")
+		}
+		s, re, err := regexArguments(c, "replaceAll", arguments[0], arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		repl, ok := arguments[2].(string)
+		if !ok {
+			return nil, fmt.Errorf("replaceAll() expects exactly three string arguments")
+		}
+		var replaced string
+		err = contextRegexLimits(c).run(c, func() { replaced = re.ReplaceAllString(s, repl) })
+		if err != nil {
+			return nil, err
+		}
+		return replaced, nil
+	}),
+	Function("findAll", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 && len(arguments) != 3 {
+			return nil, fmt.Errorf("findAll() expects two string arguments and an optional limit")
+		}
+		s, re, err := regexArguments(c, "findAll", arguments[0], arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		n := -1
+		if len(arguments) == 3 {
+			f, ok := convertToFloat(arguments[2])
+			if !ok {
+				return nil, fmt.Errorf("findAll() unexpected limit %v(%T) expected number", arguments[2], arguments[2])
+			}
+			n = int(f)
+		}
+		var found []string
+		if err := contextRegexLimits(c).run(c, func() { found = re.FindAllString(s, n) }); err != nil {
+			return nil, err
+		}
+		r := make([]interface{}, len(found))
+		for i, f := range found {
+			r[i] = f
+		}
+		return r, nil
+	}),
+)
+
+func regexArguments(c context.Context, name string, s, pattern interface{}) (string, *regexp.Regexp, error) {
+	str, ok := s.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("%s() unexpected %v(%T) expected string", name, s, s)
+	}
+	p, ok := pattern.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("%s() unexpected pattern %v(%T) expected string", name, pattern, pattern)
+	}
+	if err := contextRegexLimits(c).checkPattern(p); err != nil {
+		return "", nil, err
+	}
+	re, hit, err := regexps.lookup(p)
+	if m := contextMetrics(c); m != nil {
+		m.CacheLookup("regex", hit)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return str, re, nil
+}
+
+// RegexLimits restrict the regular expressions of the operators =~, !~ and mw
+// and of the functions of Regex, see WithRegexLimits.
+type RegexLimits struct {
+	// MaxPatternLen is the maximal length in bytes of patterns.
+	MaxPatternLen int
+	// MatchTimeout is the maximal duration of a match.
+	MatchTimeout time.Duration
+	// MaxConcurrentMatches is the maximal number of matches with MatchTimeout running at the same time,
+	// including timed out matches still finishing in the background. It defaults to 64.
+	MaxConcurrentMatches int
+
+	// running holds a token per running match with MatchTimeout
+	running chan struct{}
+}
+
+const defaultConcurrentMatches = 64
+
+type regexLimitsKey struct{}
+
+// WithRegexLimits returns a Language restricting the patterns of =~, !~, mw and the functions of Regex to limits.
+// Constant patterns of the operators exceeding MaxPatternLen fail to parse, other patterns fail to evaluate,
+// both with a *LimitExceededError.
+//
+// Go's regular expressions are RE2 without backtracking, so a match takes linear time in the length
+// of the pattern and the input. MatchTimeout guards against long inputs: matches taking longer fail
+// with an error matching context.DeadlineExceeded. A cancelled evaluation context stops waiting for the match as well.
+// Go can not stop a running match, so it finishes in the background. At most MaxConcurrentMatches matches run
+// at the same time, further matches wait for a free slot within their MatchTimeout,
+// which bounds the goroutines and CPU spent on timed out matches.
+//
+// Without WithRegexLimits constant patterns are compiled while parsing
+// and the patterns of variables are compiled once and kept like the patterns of Regex.
+func WithRegexLimits(limits RegexLimits) Language {
+	if limits.MatchTimeout > 0 {
+		if limits.MaxConcurrentMatches <= 0 {
+			limits.MaxConcurrentMatches = defaultConcurrentMatches
+		}
+		limits.running = make(chan struct{}, limits.MaxConcurrentMatches)
+	}
+	l := NewLanguage(
+		InfixEvalOperator("=~", limits.matchOperator(false)),
+		InfixEvalOperator("!~", limits.matchOperator(true)),
+		InfixContextOperator("mw", func(c context.Context, a, b interface{}) (interface{}, error) {
+			if a == nil || b == nil {
+				return nil, fmt.Errorf("invalid operation (%T) mw (%T)", a, b)
+			}
+			return limits.match(c, fmt.Sprintf("%v", b), fmt.Sprintf("%v", a))
+		}),
+	)
+	l.setOption(option{
+		name: "regexLimits",
+		wrap: func(eval Evaluable) Evaluable {
+			if eval.IsConst() {
+				return eval
+			}
+			return func(c context.Context, parameter interface{}) (interface{}, error) {
+				if c == nil {
+					c = context.Background()
+				}
+				return eval(context.WithValue(c, regexLimitsKey{}, limits), parameter)
+			}
+		},
+	})
+	return l
+}
+
+// contextRegexLimits returns the RegexLimits of the evaluation, no limits without WithRegexLimits.
+func contextRegexLimits(c context.Context) RegexLimits {
+	if c != nil {
+		if limits, ok := c.Value(regexLimitsKey{}).(RegexLimits); ok {
+			return limits
+		}
+	}
+	return RegexLimits{}
+}
+
+// matchOperator returns the builder of =~ or, if negate is true, !~.
+func (limits RegexLimits) matchOperator(negate bool) func(a, b Evaluable) (Evaluable, error) {
+	return func(a, b Evaluable) (Evaluable, error) {
+		if !b.IsConst() {
+			return func(c context.Context, v interface{}) (interface{}, error) {
+				s, err := a.EvalString(c, v)
+				if err != nil {
+					return nil, err
+				}
+				pattern, err := b.EvalString(c, v)
+				if err != nil {
+					return nil, err
+				}
+				matched, err := limits.match(c, pattern, s)
+				if err != nil {
+					return nil, err
+				}
+				return matched != negate, nil
+			}, nil
+		}
+		pattern, err := b.EvalString(context.TODO(), nil)
+		if err != nil {
+			return nil, err
+		}
+		re, err := limits.compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			s, err := a.EvalString(c, v)
+			if err != nil {
+				return nil, err
+			}
+			matched, err := limits.matchString(c, re, s)
+			if err != nil {
+				return nil, err
+			}
+			return matched != negate, nil
+		}, nil
+	}
+}
+
+// match reports if s contains a match of pattern.
+func (limits RegexLimits) match(c context.Context, pattern, s string) (bool, error) {
+	re, err := limits.compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	return limits.matchString(c, re, s)
+}
+
+// compile returns the compiled pattern kept in regexps.
+func (limits RegexLimits) compile(pattern string) (*regexp.Regexp, error) {
+	if err := limits.checkPattern(pattern); err != nil {
+		return nil, err
+	}
+	return regexps.compile(pattern)
+}
+
+// checkPattern fails for patterns longer than MaxPatternLen.
+func (limits RegexLimits) checkPattern(pattern string) error {
+	if limits.MaxPatternLen > 0 && len(pattern) > limits.MaxPatternLen {
+		return &LimitExceededError{Limit: "MaxPatternLen", Max: limits.MaxPatternLen}
+	}
+	return nil
+}
+
+// matchString reports if s contains a match of re within MatchTimeout.
+func (limits RegexLimits) matchString(c context.Context, re *regexp.Regexp, s string) (bool, error) {
+	var matched bool
+	err := limits.run(c, func() { matched = re.MatchString(s) })
+	return matched, err
+}
+
+// run runs the match f within MatchTimeout. A timed out f keeps its slot of MaxConcurrentMatches until it finishes.
+func (limits RegexLimits) run(c context.Context, f func()) error {
+	if limits.MatchTimeout <= 0 || limits.running == nil {
+		f()
+		return nil
+	}
+	if c == nil {
+		c = context.Background()
+	}
+	if err := c.Err(); err != nil {
+		return err
+	}
+	timer := time.NewTimer(limits.MatchTimeout)
+	defer timer.Stop()
+	select {
+	case limits.running <- struct{}{}:
+	default:
+		select {
+		case limits.running <- struct{}{}:
+		case <-timer.C:
+			return fmt.Errorf("regex match exceeded %s waiting for %d running matches: %w", limits.MatchTimeout, cap(limits.running), context.DeadlineExceeded)
+		case <-c.Done():
+			return c.Err()
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		defer func() { <-limits.running }()
+		f()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("regex match exceeded %s: %w", limits.MatchTimeout, context.DeadlineExceeded)
+	case <-c.Done():
+		return c.Err()
+	}
+}
+
+// regexps caches compiled patterns across all evaluations.
+var regexps = newRegexCache(256)
+
+// regexCache is a concurrency safe least recently used cache of compiled regular expressions.
+type regexCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front is most recently used
+}
+
+type regexCacheEntry struct {
+	pattern string
+	regex   *regexp.Regexp
+}
+
+func newRegexCache(capacity int) *regexCache {
+	return &regexCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// compile returns the cached compiled pattern or compiles and caches it.
+func (c *regexCache) compile(pattern string) (*regexp.Regexp, error) {
+	re, _, err := c.lookup(pattern)
+	return re, err
+}
+
+// lookup returns the compiled pattern like compile and if it was cached.
+func (c *regexCache) lookup(pattern string) (*regexp.Regexp, bool, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return e.Value.(*regexCacheEntry).regex, true, nil
+	}
+	c.mu.Unlock()
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*regexCacheEntry).regex, true, nil
+	}
+	c.entries[pattern] = c.order.PushFront(&regexCacheEntry{pattern, re})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*regexCacheEntry).pattern)
+	}
+	return re, false, nil
+}
+
+func (c *regexCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,197 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegex(t *testing.T) {
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "matches",
+				expression: `matches("premium-42", "^prem\\w+-\\d+$")`,
+				extension:  Regex(),
+				want:       true,
+			},
+			{
+				name:       "matches variable pattern",
+				expression: `matches(name, pattern)`,
+				extension:  Regex(),
+				parameter:  map[string]interface{}{"name": "basic", "pattern": "^prem"},
+				want:       false,
+			},
+			{
+				name:       "replaceAll",
+				expression: `replaceAll("a1b22c333", "[0-9]+", "#")`,
+				extension:  Regex(),
+				want:       "a#b#c#",
+			},
+			{
+				name:       "replaceAll groups",
+				expression: `replaceAll("john smith", "(\\w+) (\\w+)", "$2, $1")`,
+				extension:  Regex(),
+				want:       "smith, john",
+			},
+			{
+				name:       "findAll",
+				expression: `findAll("a1b22c333", "[0-9]+")`,
+				extension:  Regex(),
+				want:       []interface{}{"1", "22", "333"},
+			},
+			{
+				name:       "findAll limit",
+				expression: `findAll("a1b22c333", "[0-9]+", 2)`,
+				extension:  Regex(),
+				want:       []interface{}{"1", "22"},
+			},
+			{
+				name:       "findAll nothing",
+				expression: `findAll("abc", "[0-9]+")`,
+				extension:  Regex(),
+				want:       []interface{}{},
+			},
+			{
+				name:       "invalid pattern",
+				expression: `matches("abc", "[")`,
+				extension:  Regex(),
+				wantErr:    "error parsing regexp",
+			},
+			{
+				name:       "no string",
+				expression: `matches(1, "1")`,
+				extension:  Regex(),
+				wantErr:    "matches() unexpected 1(float64) expected string",
+			},
+		},
+		t,
+	)
+}
+
+func TestRegexCache(t *testing.T) {
+	c := newRegexCache(2)
+
+	a, err := c.compile("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := c.compile("a"); again != a {
+		t.Errorf("compile() did not return the cached pattern")
+	}
+	if _, err := c.compile("b"); err != nil {
+		t.Fatal(err)
+	}
+	// a is now the most recently used entry, so b gets evicted
+	c.compile("a")
+	if _, err := c.compile("c"); err != nil {
+		t.Fatal(err)
+	}
+	if c.len() != 2 {
+		t.Errorf("len() = %d, want 2", c.len())
+	}
+	if again, _ := c.compile("a"); again != a {
+		t.Errorf("compile() evicted the most recently used pattern")
+	}
+	if _, ok := c.entries["b"]; ok {
+		t.Errorf("compile() did not evict the least recently used pattern")
+	}
+	if _, err := c.compile("("); err == nil {
+		t.Errorf("compile() expected error for invalid pattern")
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.compile(fmt.Sprintf("p%d", i%3))
+		}(i)
+	}
+	wg.Wait()
+	if c.len() != 2 {
+		t.Errorf("len() = %d, want 2", c.len())
+	}
+}
+
+func TestWithRegexLimits(t *testing.T) {
+	l := NewLanguage(Full(), WithRegexLimits(RegexLimits{MaxPatternLen: 6}))
+	parameter := map[string]interface{}{"s": "abc", "short": "^a.c$", "long": "^a.*.*c$"}
+	for expression, want := range map[string]interface{}{
+		`s =~ short`:     true,
+		`s !~ short`:     false,
+		`s =~ "b"`:       true,
+		`s mw "^b"`:      false,
+		`"xyz" !~ short`: true,
+	} {
+		if got, err := l.Evaluate(expression, parameter); err != nil || got != want {
+			t.Errorf("Evaluate(%s) = %v, %v, want %v", expression, got, err, want)
+		}
+	}
+	for _, expression := range []string{`s =~ long`, `s !~ long`, `s mw long`} {
+		if _, err := l.Evaluate(expression, parameter); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("Evaluate(%s) error = %v, want ErrLimitExceeded", expression, err)
+		}
+	}
+	if _, err := l.NewEvaluable(`s =~ "^a.*.*c$"`); err == nil || !strings.Contains(err.Error(), "MaxPatternLen") {
+		t.Errorf("NewEvaluable() error = %v, want parsing error exceeding MaxPatternLen", err)
+	}
+
+	input := map[string]interface{}{"s": strings.Repeat("ab", 5<<20)}
+	timed := NewLanguage(Full(), WithRegexLimits(RegexLimits{MatchTimeout: time.Microsecond}))
+	if _, err := timed.Evaluate(`s =~ "(a|b)*c"`, input); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Evaluate() error = %v, want context.DeadlineExceeded", err)
+	}
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewLanguage(Full(), WithRegexLimits(RegexLimits{MatchTimeout: time.Hour})).EvaluateWithContext(c, `s =~ "(a|b)*c"`, input); !errors.Is(err, context.Canceled) {
+		t.Errorf("Evaluate() with cancelled context error = %v, want context.Canceled", err)
+	}
+	if got, err := NewLanguage(Full(), WithRegexLimits(RegexLimits{MatchTimeout: time.Second})).Evaluate(`"ab" =~ "b"`, nil); err != nil || got != true {
+		t.Errorf("Evaluate() = %v, %v, want true", got, err)
+	}
+	if _, err := NewLanguage(Full(), WithRegexLimits(RegexLimits{MatchTimeout: time.Hour})).EvaluateWithContext(c, `s mw "(a|b)*c"`, input); !errors.Is(err, context.Canceled) {
+		t.Errorf("Evaluate(mw) with cancelled context error = %v, want context.Canceled", err)
+	}
+
+	single := NewLanguage(Full(), WithRegexLimits(RegexLimits{MatchTimeout: time.Microsecond, MaxConcurrentMatches: 1}))
+	if _, err := single.Evaluate(`s =~ "(a|b)*c"`, input); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Evaluate() error = %v, want context.DeadlineExceeded", err)
+	}
+	if _, err := single.Evaluate(`s =~ "(a|b)*d"`, input); err == nil || !strings.Contains(err.Error(), "waiting for 1 running matches") {
+		t.Errorf("Evaluate() while a timed out match is running error = %v, want waiting for 1 running matches", err)
+	}
+}
+
+func TestRegexWithRegexLimits(t *testing.T) {
+	l := NewLanguage(Full(), Regex(), WithRegexLimits(RegexLimits{MaxPatternLen: 6, MatchTimeout: time.Second}))
+	parameter := map[string]interface{}{"s": "abc", "long": "^a.*.*c$"}
+	for expression, want := range map[string]interface{}{
+		`matches(s, "^a.c$")`:       true,
+		`replaceAll(s, "b", "x")`:   "axc",
+		`findAll(s, "[ac]")`:        []interface{}{"a", "c"},
+		`findAll(s, "[abc]", 1)[0]`: "a",
+	} {
+		if got, err := l.Evaluate(expression, parameter); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Evaluate(%s) = %v, %v, want %v", expression, got, err, want)
+		}
+	}
+	for _, expression := range []string{`matches(s, long)`, `replaceAll(s, long, "x")`, `findAll(s, long)`, `matches(s, "^a.*.*c$")`} {
+		if _, err := l.Evaluate(expression, parameter); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("Evaluate(%s) error = %v, want ErrLimitExceeded", expression, err)
+		}
+	}
+
+	input := map[string]interface{}{"s": strings.Repeat("ab", 1<<20)}
+	timed := NewLanguage(Full(), Regex(), WithRegexLimits(RegexLimits{MatchTimeout: time.Microsecond}))
+	for _, expression := range []string{`matches(s, "(a|b)*c")`, `replaceAll(s, "(a|b)*c", "")`, `findAll(s, "(a|b)*c")`} {
+		if _, err := timed.Evaluate(expression, input); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Evaluate(%s) error = %v, want context.DeadlineExceeded", expression, err)
+		}
+	}
+}
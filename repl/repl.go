@@ -0,0 +1,265 @@
+// Package repl provides an interactive read-eval-print loop for gval expressions.
+//
+// Each line is evaluated with the variables of the REPL as parameter. Input continues on the next line
+// while the expression is incomplete, e.g. after a trailing operator or an open bracket, or if the line ends with \.
+// An empty line ends an incomplete input.
+//
+//	let name = expression  binds the value of the expression to name for the following lines
+//	:type expression       prints the statically known type of the expression
+//	:ast expression        prints the syntax tree of the expression
+//	:trace expression      prints the evaluation of each node of the expression
+//	:vars                  lists the variables
+//	:history               lists the previous inputs
+//	:help                  lists the commands
+//	:quit                  ends the REPL
+package repl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Nandagopi/gval"
+)
+
+// REPL is a read-eval-print loop. Its fields may be set before Run.
+type REPL struct {
+	// Language evaluates the inputs.
+	Language gval.Language
+	// Variables are the parameter of the evaluations. let adds to them.
+	Variables map[string]interface{}
+	// History holds the inputs in the order they were entered.
+	History []string
+	// Prompt and Continuation are printed before the first and the following lines of an input.
+	Prompt, Continuation string
+}
+
+// New returns a REPL evaluating with given language and variables, which may be nil.
+func New(l gval.Language, variables map[string]interface{}) *REPL {
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+	return &REPL{Language: l, Variables: variables, Prompt: "> ", Continuation: "... "}
+}
+
+// errQuit ends Run.
+var errQuit = fmt.Errorf("quit")
+
+// Run reads inputs from in and prints their results and errors to out until in ends or :quit is entered.
+func (r *REPL) Run(c context.Context, in io.Reader, out io.Writer) error {
+	lines := bufio.NewScanner(in)
+	input := ""
+	for {
+		if input == "" {
+			fmt.Fprint(out, r.Prompt)
+		} else {
+			fmt.Fprint(out, r.Continuation)
+		}
+		if !lines.Scan() {
+			fmt.Fprintln(out)
+			return lines.Err()
+		}
+		line := lines.Text()
+		if strings.HasSuffix(line, `\`) {
+			input += strings.TrimSuffix(line, `\`) + "\n"
+			continue
+		}
+		input += line
+		// an empty line ends an incomplete input
+		if line != "" && r.incomplete(input) {
+			input += "\n"
+			continue
+		}
+		result, err := r.Eval(c, input)
+		input = ""
+		switch {
+		case err == errQuit:
+			return nil
+		case err != nil:
+			fmt.Fprintln(out, "error:", err)
+		case result != "":
+			fmt.Fprintln(out, result)
+		}
+	}
+}
+
+// incomplete returns true if input is an expression that continues on the next line.
+func (r *REPL) incomplete(input string) bool {
+	input = strings.TrimSpace(input)
+	if input == "" || strings.HasPrefix(input, ":") {
+		return false
+	}
+	if m := letPattern.FindStringSubmatch(input); m != nil {
+		input = m[2]
+	}
+	_, err := r.Language.NewEvaluable(input)
+	return err != nil && strings.Contains(err.Error(), "unexpected EOF")
+}
+
+// letPattern matches a binding like let x = 1 and captures the name and the expression.
+var letPattern = regexp.MustCompile(`(?s)^let\s+([A-Za-z_][A-Za-z0-9_]*)\s*=([^=~].*)$`)
+
+// Eval executes a complete input and returns what Run prints.
+func (r *REPL) Eval(c context.Context, input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", nil
+	}
+	r.History = append(r.History, input)
+
+	if strings.HasPrefix(input, ":") {
+		command, expression := input, ""
+		if i := strings.IndexAny(input, " \t\n"); i >= 0 {
+			command, expression = input[:i], strings.TrimSpace(input[i:])
+		}
+		return r.command(c, command, expression)
+	}
+
+	if m := letPattern.FindStringSubmatch(input); m != nil {
+		// let x = ...; y is an expression of the Let language, not a binding
+		if eval, err := r.Language.NewEvaluable(m[2]); err == nil {
+			v, err := eval(c, r.Variables)
+			if err != nil {
+				return "", err
+			}
+			r.Variables[m[1]] = v
+			return m[1] + " = " + format(v), nil
+		}
+	}
+
+	v, err := r.Language.EvaluateWithContext(c, input, r.Variables)
+	if err != nil {
+		return "", err
+	}
+	return format(v), nil
+}
+
+func (r *REPL) command(c context.Context, command, expression string) (string, error) {
+	if expression == "" && (command == ":type" || command == ":ast" || command == ":trace") {
+		return "", fmt.Errorf("%s expects an expression", command)
+	}
+	switch command {
+	case ":type":
+		t, err := r.Language.ResultType(expression, r.schema())
+		if err != nil {
+			return "", err
+		}
+		return t.String(), nil
+	case ":ast":
+		n, err := r.Language.ParseWithContext(c, expression)
+		if err != nil {
+			return "", err
+		}
+		b := &strings.Builder{}
+		writeNode(b, n, 0)
+		return strings.TrimSuffix(b.String(), "\n"), nil
+	case ":trace":
+		_, trace, err := r.Language.EvaluateWithTrace(c, expression, r.Variables)
+		if trace == nil {
+			return "", err
+		}
+		return strings.TrimSuffix(trace.String(), "\n"), nil
+	case ":vars":
+		names := make([]string, 0, len(r.Variables))
+		for name := range r.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		lines := make([]string, len(names))
+		for i, name := range names {
+			lines[i] = name + " = " + format(r.Variables[name])
+		}
+		return strings.Join(lines, "\n"), nil
+	case ":history":
+		lines := make([]string, len(r.History))
+		for i, input := range r.History {
+			lines[i] = fmt.Sprintf("%d  %s", i+1, strings.Replace(input, "\n", "\n   ", -1))
+		}
+		return strings.Join(lines, "\n"), nil
+	case ":help":
+		return help, nil
+	case ":quit", ":q":
+		return "", errQuit
+	}
+	return "", fmt.Errorf("unknown command %s, see :help", command)
+}
+
+const help = `let name = expression  bind the value of the expression to name
+:type expression       print the type of the expression
+:ast expression        print the syntax tree of the expression
+:trace expression      print the evaluation of each node of the expression
+:vars                  list the variables
+:history               list the previous inputs
+:quit                  end the REPL`
+
+// schema returns the types of the variables for :type.
+func (r *REPL) schema() map[string]gval.Type {
+	schema := make(map[string]gval.Type, len(r.Variables))
+	for name, v := range r.Variables {
+		schema[name] = typeOf(v)
+	}
+	return schema
+}
+
+func typeOf(v interface{}) gval.Type {
+	if v == nil {
+		return gval.AnyType
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return gval.NumberType
+	case reflect.String:
+		return gval.StringType
+	case reflect.Bool:
+		return gval.BoolType
+	case reflect.Slice, reflect.Array:
+		return gval.ArrayType
+	case reflect.Map, reflect.Struct:
+		return gval.ObjectType
+	}
+	return gval.AnyType
+}
+
+var kindNames = map[gval.NodeKind]string{
+	gval.OperandNode:  "operand",
+	gval.ConstantNode: "constant",
+	gval.VariableNode: "variable",
+	gval.InfixNode:    "infix",
+	gval.PostfixNode:  "postfix",
+}
+
+// writeNode writes the syntax tree n one node per line, the children indented below their parent.
+func writeNode(b *strings.Builder, n *gval.Node, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(kindNames[n.Kind])
+	switch n.Kind {
+	case gval.InfixNode, gval.PostfixNode:
+		fmt.Fprintf(b, " %s", n.Operator)
+	case gval.VariableNode:
+		fmt.Fprintf(b, " %s", strings.Join(n.Path, "."))
+	case gval.ConstantNode:
+		fmt.Fprintf(b, " %s", format(n.Value))
+	default:
+		fmt.Fprintf(b, " %s", n.Text())
+	}
+	b.WriteString("\n")
+	for _, child := range n.Children {
+		writeNode(b, child, depth+1)
+	}
+}
+
+// format returns v as JSON if possible, e.g. strings are quoted.
+func format(v interface{}) string {
+	if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprintf("%v", v)
+}
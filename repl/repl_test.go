@@ -0,0 +1,89 @@
+package repl
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Nandagopi/gval"
+)
+
+func TestREPL_Run(t *testing.T) {
+	input := strings.Join([]string{
+		`let price = 10`,
+		`price *`,
+		`  2`,
+		`"a" + \`,
+		`"b"`,
+		`let rate = price / 100`,
+		`:vars`,
+		`unknown(`,
+		``,
+		`:quit`,
+		`1`,
+	}, "\n")
+	r := New(gval.Full(), nil)
+	r.Prompt, r.Continuation = "", ""
+	var out bytes.Buffer
+	if err := r.Run(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		`price = 10`,
+		`20`,
+		`"ab"`,
+		`rate = 0.1`,
+		"price = 10\nrate = 0.1",
+		`error: parsing error: unknown(`,
+	}, "\n")
+	if got := out.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("Run() printed\n%s\nwant\n%s", got, want)
+	}
+	if len(r.History) != 7 || r.History[1] != "price *\n  2" {
+		t.Errorf("History = %q", r.History)
+	}
+}
+
+func TestREPL_Eval(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{name: "expression", input: `x.y + 1`, want: `3`},
+		{name: "string", input: `x.name`, want: `"ann"`},
+		{name: "let expression", input: `let a = 2; a * x.y`, want: `4`},
+		{name: "type", input: `:type x.y > 1 || flag`, want: `bool`},
+		{name: "type of variable", input: `:type flag`, want: `bool`},
+		{name: "ast", input: `:ast x.y > 1 && [1]`, want: "infix &&\n  infix >\n    variable x.y\n    constant 1\n  operand [1]\n    constant 1"},
+		{name: "trace", input: `:trace x.y > 1`, want: "x.y > 1 = true\n  x.y = 2\n  1 = 1"},
+		{name: "trace error", input: `:trace -x.name`, want: "-x.name failed: unexpected ann(string) expected number\n  x.name = \"ann\""},
+		{name: "missing expression", input: `:ast`, wantErr: ":ast expects an expression"},
+		{name: "unknown command", input: `:run`, wantErr: "unknown command :run"},
+		{name: "evaluation error", input: `x.name * 2`, wantErr: "invalid operation"},
+		{name: "help", input: `:help`, want: help},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(gval.Full(gval.Let()), map[string]interface{}{
+				"x":    map[string]interface{}{"y": 2., "name": "ann"},
+				"flag": true,
+			})
+			got, err := r.Eval(context.Background(), tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
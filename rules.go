@@ -0,0 +1,259 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rule is a named expression of a RuleSet.
+type Rule struct {
+	Name       string
+	Expression string
+	// Priority orders the rules of a RuleSet, higher priorities first.
+	// Rules with the same priority keep their order.
+	Priority int
+}
+
+// Result is the value of a Rule evaluated by RuleSet.EvaluateAll or the error of its evaluation.
+type Result struct {
+	Value interface{}
+	Err   error
+}
+
+// computedVariable is the variable holding the values of the rules a rule depends on.
+const computedVariable = "computed"
+
+// RuleSet is a set of Rules parsed once and evaluated against the same parameter.
+// It can be evaluated concurrently.
+//
+// A rule can use the value of another rule of the set as computed.<name>, e.g. total > 100 && computed.vip.
+// The rules it depends on are evaluated before it and dependency cycles fail NewRuleSet.
+type RuleSet struct {
+	rules []Rule
+	evals []Evaluable
+	// constants are the values of constant rules by index, evaluated by NewRuleSet
+	constants map[int]Result
+	// dependencies are the indices of the rules referenced as computed.<name> by index
+	dependencies [][]int
+	// order are the indices of the rules with their dependencies first
+	order    []int
+	computes bool
+}
+
+// NewRuleSet parses the rules in the Language. Their names must be unique.
+// Rules with the same expression share their Evaluable and constant rules like 1 < 2 are evaluated once.
+func (l Language) NewRuleSet(rules ...Rule) (*RuleSet, error) {
+	return l.NewRuleSetWithContext(context.Background(), rules...)
+}
+
+// NewRuleSetWithContext parses the rules in the Language using context.
+func (l Language) NewRuleSetWithContext(c context.Context, rules ...Rule) (*RuleSet, error) {
+	rs := &RuleSet{
+		rules:     append([]Rule{}, rules...),
+		constants: map[int]Result{},
+	}
+	sort.SliceStable(rs.rules, func(i, j int) bool { return rs.rules[i].Priority > rs.rules[j].Priority })
+
+	names := map[string]int{}
+	for i, rule := range rs.rules {
+		if _, ok := names[rule.Name]; ok {
+			return nil, fmt.Errorf("duplicate rule %s", rule.Name)
+		}
+		names[rule.Name] = i
+	}
+	parsed := map[string]Evaluable{}
+	rs.evals = make([]Evaluable, len(rs.rules))
+	rs.dependencies = make([][]int, len(rs.rules))
+	for i, rule := range rs.rules {
+		eval, ok := parsed[rule.Expression]
+		if !ok {
+			var err error
+			eval, err = l.NewEvaluableWithContext(c, rule.Expression)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+			}
+			parsed[rule.Expression] = eval
+		}
+		rs.evals[i] = eval
+		if strings.Contains(rule.Expression, computedVariable) {
+			node, err := l.ParseWithContext(c, rule.Expression)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+			}
+			rs.dependencies[i] = computedDependencies(node, names, nil)
+			rs.computes = rs.computes || len(rs.dependencies[i]) > 0
+		}
+		if eval.IsConst() {
+			v, err := eval(c, nil)
+			rs.constants[i] = Result{Value: v, Err: err}
+		}
+	}
+	if err := rs.sortDependencies(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// computedDependencies appends the indices of the rules n references as computed.<name>.
+func computedDependencies(n *Node, names map[string]int, dependencies []int) []int {
+	if n.Kind == VariableNode && len(n.Path) > 1 && n.Path[0] == computedVariable {
+		if i, ok := names[n.Path[1]]; ok && indexOfInt(dependencies, i) < 0 {
+			dependencies = append(dependencies, i)
+		}
+	}
+	for _, child := range n.Children {
+		dependencies = computedDependencies(child, names, dependencies)
+	}
+	return dependencies
+}
+
+func indexOfInt(s []int, i int) int {
+	for j, k := range s {
+		if k == i {
+			return j
+		}
+	}
+	return -1
+}
+
+// sortDependencies sets the order of the rules by a depth first search of their dependencies
+// and fails if the rules depend on each other.
+func (rs *RuleSet) sortDependencies() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(rs.rules))
+	var path []int
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := []string{}
+			for _, j := range path[indexOfInt(path, i):] {
+				cycle = append(cycle, rs.rules[j].Name)
+			}
+			return fmt.Errorf("rules depend on each other: %s -> %s", strings.Join(cycle, " -> "), rs.rules[i].Name)
+		}
+		state[i] = visiting
+		path = append(path, i)
+		for _, d := range rs.dependencies[i] {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		rs.order = append(rs.order, i)
+		return nil
+	}
+	for i := range rs.rules {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rules returns the rules ordered by priority.
+func (rs *RuleSet) Rules() []Rule {
+	return append([]Rule{}, rs.rules...)
+}
+
+// ruleEvaluation holds the results of the rules evaluated against a parameter.
+type ruleEvaluation struct {
+	rs        *RuleSet
+	parameter interface{}
+	results   map[int]Result
+	computed  map[string]interface{}
+}
+
+func (rs *RuleSet) newEvaluation(parameter interface{}) *ruleEvaluation {
+	e := &ruleEvaluation{rs: rs, parameter: parameter, results: make(map[int]Result, len(rs.rules))}
+	if rs.computes {
+		e.computed = map[string]interface{}{}
+		e.parameter = computedParameter{parameter: parameter, computed: e.computed}
+	}
+	return e
+}
+
+// result evaluates the rule with index i after the rules it depends on.
+func (e *ruleEvaluation) result(c context.Context, i int) Result {
+	if r, ok := e.results[i]; ok {
+		return r
+	}
+	r, ok := e.rs.constants[i]
+	if !ok {
+		r = e.evaluate(c, i)
+	}
+	e.results[i] = r
+	if e.computed != nil && r.Err == nil {
+		e.computed[e.rs.rules[i].Name] = r.Value
+	}
+	return r
+}
+
+func (e *ruleEvaluation) evaluate(c context.Context, i int) Result {
+	for _, d := range e.rs.dependencies[i] {
+		if r := e.result(c, d); r.Err != nil {
+			return Result{Err: fmt.Errorf("%s.%s: %w", computedVariable, e.rs.rules[d].Name, r.Err)}
+		}
+	}
+	v, err := e.rs.evals[i](c, e.parameter)
+	return Result{Value: v, Err: err}
+}
+
+// computedParameter adds the computed variable to the parameter of rules depending on other rules.
+type computedParameter struct {
+	parameter interface{}
+	computed  map[string]interface{}
+}
+
+func (p computedParameter) SelectGVal(c context.Context, key string) (interface{}, error) {
+	if key == computedVariable {
+		return p.computed, nil
+	}
+	return selectKeys(c, p.parameter, []string{key}, false)
+}
+
+// EvaluateAll evaluates all rules in the order of their dependencies and priority and returns their results by name.
+// Errors of rules are returned as their Result, the error of EvaluateAll is the error of a cancelled context.
+func (rs *RuleSet) EvaluateAll(c context.Context, parameter interface{}) (map[string]Result, error) {
+	if c == nil {
+		c = context.Background()
+	}
+	e := rs.newEvaluation(parameter)
+	results := make(map[string]Result, len(rs.rules))
+	for _, i := range rs.order {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		results[rs.rules[i].Name] = e.result(c, i)
+	}
+	return results, nil
+}
+
+// FirstMatch evaluates the rules in the order of their priority until one is true
+// and returns its name, "" if no rule matches.
+// It stops with the error of a failing rule.
+func (rs *RuleSet) FirstMatch(c context.Context, parameter interface{}) (string, error) {
+	if c == nil {
+		c = context.Background()
+	}
+	e := rs.newEvaluation(parameter)
+	for i, rule := range rs.rules {
+		r := e.result(c, i)
+		if r.Err != nil {
+			return "", fmt.Errorf("rule %s: %w", rule.Name, r.Err)
+		}
+		if r.Value == true {
+			return rule.Name, nil
+		}
+	}
+	return "", nil
+}
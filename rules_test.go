@@ -0,0 +1,118 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRuleSet(t *testing.T) {
+	calls := 0
+	l := Full(Function("score", func(x float64) float64 {
+		calls++
+		return x * 10
+	}))
+	rs, err := l.NewRuleSet(
+		Rule{Name: "small", Expression: "score(amount) < 100"},
+		Rule{Name: "vip", Expression: `customer.tier == "gold"`, Priority: 10},
+		Rule{Name: "large", Expression: "score(amount) >= 100", Priority: 5},
+		Rule{Name: "large again", Expression: "score(amount) >= 100", Priority: 5},
+		Rule{Name: "always", Expression: "1 < 2", Priority: -1},
+		Rule{Name: "broken", Expression: "customer.tier.x.y", Priority: -2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, rule := range rs.Rules() {
+		names = append(names, rule.Name)
+	}
+	if want := []string{"vip", "large", "large again", "small", "always", "broken"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Rules() = %v, want %v", names, want)
+	}
+
+	parameter := map[string]interface{}{"amount": 20., "customer": map[string]interface{}{"tier": "silver"}}
+	results, err := rs.EvaluateAll(context.Background(), parameter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]interface{}{"vip": false, "large": true, "large again": true, "small": false, "always": true} {
+		if r := results[name]; r.Err != nil || r.Value != want {
+			t.Errorf("EvaluateAll()[%s] = %v, want %v", name, r, want)
+		}
+	}
+	if results["broken"].Err == nil {
+		t.Errorf("EvaluateAll()[broken] = %v, want error", results["broken"])
+	}
+	if calls != 3 {
+		t.Errorf("score called %d times, want 3", calls)
+	}
+
+	if name, err := rs.FirstMatch(nil, parameter); err != nil || name != "large" {
+		t.Errorf("FirstMatch() = %s, %v, want large", name, err)
+	}
+	parameter["customer"] = map[string]interface{}{"tier": "gold"}
+	if name, err := rs.FirstMatch(nil, parameter); err != nil || name != "vip" {
+		t.Errorf("FirstMatch() = %s, %v, want vip", name, err)
+	}
+	only, _ := l.NewRuleSet(Rule{Name: "broken", Expression: "a.b.c"}, Rule{Name: "never", Expression: "false"})
+	if name, err := only.FirstMatch(nil, map[string]interface{}{"a": 1.}); err == nil || !strings.HasPrefix(err.Error(), "rule broken:") {
+		t.Errorf("FirstMatch() = %s, %v, want error of rule broken", name, err)
+	}
+	if name, err := only.FirstMatch(nil, map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{}}}); err != nil || name != "" {
+		t.Errorf("FirstMatch() = %s, %v, want no match", name, err)
+	}
+
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := rs.EvaluateAll(c, parameter); !errors.Is(err, context.Canceled) {
+		t.Errorf("EvaluateAll() with cancelled context error = %v", err)
+	}
+	if _, err := l.NewRuleSet(Rule{Name: "a", Expression: "1"}, Rule{Name: "a", Expression: "2"}); err == nil {
+		t.Error("NewRuleSet() with duplicate names expected error")
+	}
+	if _, err := l.NewRuleSet(Rule{Name: "bad", Expression: "1 +"}); err == nil || !strings.HasPrefix(err.Error(), "rule bad:") {
+		t.Errorf("NewRuleSet() error = %v, want parsing error of rule bad", err)
+	}
+}
+
+func TestRuleSetDependencies(t *testing.T) {
+	l := Full()
+	rs, err := l.NewRuleSet(
+		Rule{Name: "free shipping", Expression: `computed.total > 100 && !computed.blocked`, Priority: 10},
+		Rule{Name: "total", Expression: `order.amount - computed.discount`},
+		Rule{Name: "discount", Expression: `order.vip ? 10 : 0`},
+		Rule{Name: "blocked", Expression: `order.country == "xx"`},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := rs.EvaluateAll(nil, map[string]interface{}{"order": map[string]interface{}{"amount": 105., "vip": true, "country": "de"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]interface{}{"free shipping": false, "total": 95., "discount": 10., "blocked": false} {
+		if r := results[name]; r.Err != nil || r.Value != want {
+			t.Errorf("EvaluateAll()[%s] = %v, want %v", name, r, want)
+		}
+	}
+	if name, err := rs.FirstMatch(nil, map[string]interface{}{"order": map[string]interface{}{"amount": 200., "vip": false}}); err != nil || name != "free shipping" {
+		t.Errorf("FirstMatch() = %s, %v, want free shipping", name, err)
+	}
+
+	results, _ = rs.EvaluateAll(nil, map[string]interface{}{"order": map[string]interface{}{"amount": "x", "vip": false}})
+	if err := results["free shipping"].Err; err == nil || !strings.HasPrefix(err.Error(), "computed.total:") {
+		t.Errorf("EvaluateAll()[free shipping] error = %v, want error of computed.total", err)
+	}
+
+	for _, rules := range [][]Rule{
+		{{Name: "a", Expression: "computed.b"}, {Name: "b", Expression: "computed.c"}, {Name: "c", Expression: "computed.a"}},
+		{{Name: "a", Expression: "computed.a + 1"}},
+	} {
+		if _, err := l.NewRuleSet(rules...); err == nil || !strings.HasPrefix(err.Error(), "rules depend on each other: a -> ") {
+			t.Errorf("NewRuleSet(%v) error = %v, want cycle", rules, err)
+		}
+	}
+}
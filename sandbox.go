@@ -0,0 +1,39 @@
+package gval
+
+// sandboxLimits are the Limits of Sandbox.
+var sandboxLimits = Limits{
+	MaxNodes:     500,
+	MaxDepth:     32,
+	MaxStringLen: 64 << 10,
+	MaxArrayLen:  10000,
+}
+
+// Sandbox returns a Language for untrusted expressions. It is Full without
+//
+//	the regular expression operators =~, !~ and mw, whose patterns are given by the expression
+//	the power operator **, which creates huge numbers cheaply
+//	the operators cfa and cfm, which sort the arrays of the parameter in place
+//	method calls of parameters, see AllowMethods
+//
+// and with Limits of 500 nodes, a depth of 32, strings of 64 KiB and arrays of 10000 elements.
+// Unlike WithLimits alone it restricts the length of concatenated strings as well.
+// The limits can be changed by NewLanguage(Sandbox(), WithLimits(limits)), concatenations stay restricted to 64 KiB.
+// Use a context with timeout to restrict the duration of evaluations.
+func Sandbox(extensions ...Language) Language {
+	if len(extensions) == 0 {
+		return sandbox
+	}
+	return NewLanguage(append([]Language{sandbox}, extensions...)...)
+}
+
+var sandbox = NewLanguage(
+	full.Without("=~", "!~", "mw", "**", "cfa", "cfm"),
+	InfixTextOperator("+", func(a, b string) (interface{}, error) {
+		if len(a)+len(b) > sandboxLimits.MaxStringLen {
+			return nil, &LimitExceededError{Limit: "MaxStringLen", Max: sandboxLimits.MaxStringLen}
+		}
+		return a + b, nil
+	}),
+	AllowMethods(),
+	WithLimits(sandboxLimits),
+)
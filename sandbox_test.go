@@ -0,0 +1,58 @@
+package gval
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type sandboxUser struct{ Name string }
+
+func (u sandboxUser) Delete() string { return "deleted " + u.Name }
+
+func TestSandbox(t *testing.T) {
+	long := strings.Repeat("x", 40<<10)
+	parameter := map[string]interface{}{"name": "ada", "long": long, "user": sandboxUser{"ada"}, "tags": []interface{}{"c", "b", "a"}}
+	for _, tt := range []struct {
+		name       string
+		expression string
+		language   Language
+		want       interface{}
+		wantErr    string
+	}{
+		{name: "full language", expression: `name + "!" == "ada!" && 2 * 3 in [6] && user.Name == name ? 1 : 0`, want: 1.},
+		{name: "concatenation", expression: `len(long + "x")`, want: float64(len(long) + 1)},
+		{name: "unbounded concatenation", expression: `long + long`, wantErr: "limit exceeded: MaxStringLen is 65536"},
+		{name: "regex", expression: `name =~ "a+"`, wantErr: `unexpected "=" while scanning operator`},
+		{name: "power", expression: `9 ** 999`, wantErr: `unexpected "*"`},
+		{name: "contains all", expression: `tags cfa ["b"]`, wantErr: `unexpected Ident while scanning operator`},
+		{name: "contains many", expression: `tags cfm ["b"]`, wantErr: `unexpected Ident while scanning operator`},
+		{name: "methods", expression: `user.Delete()`, wantErr: "unknown parameter user.Delete"},
+		{name: "depth", expression: strings.Repeat("(", 40) + "1" + strings.Repeat(")", 40), wantErr: "limit exceeded: MaxDepth is 32"},
+		{name: "extension", expression: `round(1.5)`, language: Sandbox(Math()), want: 2.},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := Sandbox()
+			if tt.language.prefixes != nil {
+				l = tt.language
+			}
+			got, err := l.Evaluate(tt.expression, parameter)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Evaluate(%s) = %v, %v, want error %s", tt.expression, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Evaluate(%s) = %v, %v, want %v", tt.expression, got, err, tt.want)
+			}
+		})
+	}
+	if tags := parameter["tags"]; !reflect.DeepEqual(tags, []interface{}{"c", "b", "a"}) {
+		t.Errorf("Sandbox() modified the parameter tags to %v", tags)
+	}
+	if _, err := NewLanguage(Sandbox(), WithLimits(Limits{MaxNodes: 3})).Evaluate("1 + 2 + 3", nil); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Evaluate() with changed limits error = %v, want ErrLimitExceeded", err)
+	}
+}
@@ -0,0 +1,58 @@
+package gval
+
+import (
+	"context"
+	"errors"
+)
+
+// FallbackVariableSelector returns a Language which uses the given variable selectors in order.
+// If a selector fails with an error matching ErrUnknownParameter, the next selector is consulted.
+// The error of the last selector is returned if no selector knows the variable.
+//
+// This allows layered parameter sources, e.g. the parameter, then the environment, then defaults:
+//
+//	gval.FallbackVariableSelector(
+//		gval.ParameterSelector,
+//		gval.SourceSelector(environment),
+//		gval.SourceSelector(defaults),
+//	)
+func FallbackVariableSelector(selectors ...func(path Evaluables) Evaluable) Language {
+	return VariableSelector(func(path Evaluables) Evaluable {
+		evals := make([]Evaluable, len(selectors))
+		for i, s := range selectors {
+			evals[i] = s(path)
+		}
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			err := error(unknownParameterError{})
+			for _, e := range evals {
+				var r interface{}
+				r, err = e(c, v)
+				if err == nil || !errors.Is(err, ErrUnknownParameter) {
+					return r, err
+				}
+			}
+			return nil, err
+		}
+	})
+}
+
+// ParameterSelector is a variable selector that selects the path from the parameter.
+// In contrast to the default selector it reports missing map keys and array indices as ErrUnknownParameter
+// instead of selecting nil, so it can be used with FallbackVariableSelector.
+func ParameterSelector(path Evaluables) Evaluable {
+	return selectPath(path, true)
+}
+
+// SourceSelector returns a variable selector that selects the path from the given source
+// instead of the parameter. Missing keys are reported as ErrUnknownParameter.
+func SourceSelector(source interface{}) func(path Evaluables) Evaluable {
+	return func(path Evaluables) Evaluable {
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			keys, err := path.EvalStrings(c, v)
+			if err != nil {
+				return nil, err
+			}
+			return selectKeys(c, source, keys, true)
+		}
+	}
+}
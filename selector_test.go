@@ -0,0 +1,82 @@
+package gval
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFallbackVariableSelector(t *testing.T) {
+	env := map[string]interface{}{"region": "eu", "limits": map[string]interface{}{"max": 10.}}
+	defaults := map[string]interface{}{"region": "us", "timeout": 30., "limits": map[string]interface{}{"min": 1.}}
+	layered := NewLanguage(Full(), FallbackVariableSelector(
+		ParameterSelector,
+		SourceSelector(env),
+		SourceSelector(defaults),
+	))
+	request := map[string]interface{}{"user": "ann", "timeout": 5.}
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "from parameter",
+				expression: `user`,
+				extension:  layered,
+				parameter:  request,
+				want:       "ann",
+			},
+			{
+				name:       "parameter shadows defaults",
+				expression: `timeout`,
+				extension:  layered,
+				parameter:  request,
+				want:       5.,
+			},
+			{
+				name:       "from environment",
+				expression: `region`,
+				extension:  layered,
+				parameter:  request,
+				want:       "eu",
+			},
+			{
+				name:       "from defaults",
+				expression: `timeout`,
+				extension:  layered,
+				want:       30.,
+			},
+			{
+				name:       "nested fallback",
+				expression: `limits.min + limits.max`,
+				extension:  layered,
+				parameter:  request,
+				want:       11.,
+			},
+			{
+				name:       "dynamic key evaluated on parameter",
+				expression: `limits[key]`,
+				extension:  layered,
+				parameter:  map[string]interface{}{"key": "max"},
+				want:       10.,
+			},
+			{
+				name:       "unknown everywhere",
+				expression: `limits.avg`,
+				extension:  layered,
+				parameter:  request,
+				wantErr:    "unknown parameter limits.avg",
+			},
+		},
+		t,
+	)
+}
+
+func TestErrUnknownParameter(t *testing.T) {
+	_, err := Evaluate(`foo.NotExists`, fooFailureParameters)
+	if !errors.Is(err, ErrUnknownParameter) {
+		t.Fatalf("Evaluate() error = %v, want ErrUnknownParameter", err)
+	}
+	_, err = Evaluate(`missing`, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v, want missing map keys to select nil", err)
+	}
+}
@@ -0,0 +1,189 @@
+package gval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version like 1.2.3-beta.1+build, created by semver(s) of SemVer.
+type Version struct {
+	Major, Minor, Patch int64
+	// Prerelease are the dot separated identifiers following -, e.g. beta.1
+	Prerelease string
+	// Build is the build metadata following +, which is ignored by comparisons
+	Build string
+	// segments is the number of given numbers, e.g. 2 for 1.2
+	segments int
+}
+
+// ParseVersion parses a semantic version. The leading v, the minor and the patch version are optional,
+// so v1.2 is 1.2.0.
+func ParseVersion(s string) (Version, error) {
+	v := Version{}
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		rest, v.Build = rest[:i], rest[i+1:]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		rest, v.Prerelease = rest[:i], rest[i+1:]
+		if v.Prerelease == "" {
+			return Version{}, fmt.Errorf("invalid version %s", s)
+		}
+	}
+	numbers := strings.Split(rest, ".")
+	if len(numbers) > 3 {
+		return Version{}, fmt.Errorf("invalid version %s", s)
+	}
+	for i, n := range numbers {
+		x, err := strconv.ParseInt(n, 10, 64)
+		if err != nil || x < 0 {
+			return Version{}, fmt.Errorf("invalid version %s", s)
+		}
+		*[]*int64{&v.Major, &v.Minor, &v.Patch}[i] = x
+	}
+	v.segments = len(numbers)
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 if v is lower, equal or greater than w by the precedence of semantic versions:
+// releases are greater than their prereleases and build metadata is ignored.
+func (v Version) Compare(w Version) int {
+	for _, d := range []int64{v.Major - w.Major, v.Minor - w.Minor, v.Patch - w.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case v.Prerelease == w.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case w.Prerelease == "":
+		return -1
+	}
+	a, b := strings.Split(v.Prerelease, "."), strings.Split(w.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		x, errx := strconv.ParseInt(a[i], 10, 64)
+		y, erry := strconv.ParseInt(b[i], 10, 64)
+		switch {
+		case errx == nil && erry == nil:
+			return sign(x - y)
+		case errx == nil:
+			// numeric identifiers are lower than alphanumeric ones
+			return -1
+		case erry == nil:
+			return 1
+		}
+		return strings.Compare(a[i], b[i])
+	}
+	return sign(int64(len(a) - len(b)))
+}
+
+func sign(d int64) int {
+	switch {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+// Matches returns if v matches the pessimistic constraint ~> c: v >= c and v is lower than
+// the next version of the second last number given in c, e.g. ~> 1.2 allows 1.x from 1.2 and ~> 1.2.3 allows 1.2.x from 1.2.3.
+func (v Version) Matches(c Version) bool {
+	if v.Compare(c) < 0 {
+		return false
+	}
+	switch c.segments {
+	case 3:
+		return v.Major == c.Major && v.Minor == c.Minor
+	default:
+		return v.Major == c.Major
+	}
+}
+
+// SemVer returns a Language with semantic versions:
+//
+//	semver(s)                    Version of s like "1.2.3-beta.1"
+//	<, <=, >, >=, ==, !=         comparison of versions by their precedence, so 1.10 > 1.9
+//	v ~> c                       true iff version v matches the pessimistic constraint c, e.g. 1.4.2 ~> 1.2
+//
+// Strings are parsed as versions if the other operand is a Version, so semver(version) >= "1.2" compares versions.
+// Versions and strings are converted to versions by ~>.
+func SemVer() Language {
+	return semVer
+}
+
+var semVer = func() Language {
+	languages := []Language{
+		builtin(Function("semver", ParseVersion, Pure()), "semver"),
+		InfixOperator("~>", func(a, b interface{}) (interface{}, error) {
+			v, okv := toVersion(a)
+			c, okc := toVersion(b)
+			if !okv || !okc {
+				return nil, fmt.Errorf("invalid operation %v ~> %v expected versions", a, b)
+			}
+			return v.Matches(c), nil
+		}),
+		Precedence("~>", 40),
+	}
+	for name, f := range comparisons {
+		languages = append(languages, typedInfixOperator(name, versionComparison(f)))
+	}
+	return NewLanguage(languages...)
+}()
+
+// toVersion returns the version of a Version or a string.
+func toVersion(a interface{}) (Version, bool) {
+	switch a := a.(type) {
+	case Version:
+		return a, true
+	case string:
+		v, err := ParseVersion(a)
+		return v, err == nil
+	}
+	return Version{}, false
+}
+
+// versionComparison returns the comparison of operands if one is a Version and the other is a Version or a string.
+func versionComparison(f func(cmp int) bool) typedOpFunc {
+	return func(a, b interface{}) (interface{}, bool, error) {
+		_, va := a.(Version)
+		_, vb := b.(Version)
+		if !va && !vb {
+			return nil, false, nil
+		}
+		x, okx := toVersion(a)
+		y, oky := toVersion(b)
+		if !okx || !oky {
+			return nil, false, nil
+		}
+		return f(x.Compare(y)), true, nil
+	}
+}
+
+// comparisons are the comparison operators by the comparison result they accept.
+var comparisons = map[string]func(cmp int) bool{
+	"<":  func(cmp int) bool { return cmp < 0 },
+	"<=": func(cmp int) bool { return cmp <= 0 },
+	">":  func(cmp int) bool { return cmp > 0 },
+	">=": func(cmp int) bool { return cmp >= 0 },
+	"==": func(cmp int) bool { return cmp == 0 },
+	"!=": func(cmp int) bool { return cmp != 0 },
+}
@@ -0,0 +1,50 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestSemVer(t *testing.T) {
+	l := NewLanguage(Full(), SemVer())
+	parameter := map[string]interface{}{"version": "1.10.0", "semver": "variable"}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "comparison", expression: `semver("1.2.3") >= semver("1.2.0") && semver("v1.10") > "1.9.9"`, extension: l, want: true},
+			{name: "string comparison", expression: `version > "1.9"`, extension: l, parameter: parameter, want: false},
+			{name: "prerelease", expression: `semver("1.0.0-alpha") < "1.0.0-alpha.1" && semver("1.0.0-alpha.1") < "1.0.0-beta" && semver("1.0.0-beta.2") < "1.0.0-beta.11" && semver("1.0.0-rc.1") < "1.0.0"`, extension: l, want: true},
+			{name: "build", expression: `semver("1.0.0+1") == "1.0.0+2" && semver("1.0.0") != "1.0.1"`, extension: l, want: true},
+			{name: "pessimistic", expression: `[version ~> "1.2", version ~> "1.10.0", "1.11.0" ~> "1.10.0", "2.0" ~> "1.2", "1.1" ~> "1.2"]`, extension: l, parameter: parameter, want: []interface{}{true, true, false, false, false}},
+			{name: "pessimistic precedence", expression: `semver(version) ~> "1.2" == true`, extension: l, parameter: parameter, want: true},
+			{name: "other types", expression: `1 < 2 && "a" < "b" && semver("1.0") != 1`, extension: l, want: true},
+			{name: "variables named like functions", expression: `semver + "!"`, extension: l, parameter: parameter, want: "variable!"},
+			{name: "version", expression: `semver("v1.2-rc.1+build")`, extension: l, want: Version{Major: 1, Minor: 2, Prerelease: "rc.1", Build: "build", segments: 2}},
+			{name: "invalid version", expression: `semver("1.x")`, extension: l, wantErr: "invalid version 1.x"},
+			{name: "invalid constraint", expression: `"1.0" ~> 1`, extension: l, wantErr: "invalid operation 1.0 ~> 1 expected versions"},
+		},
+		t,
+	)
+}
+
+func TestSemVerWithNetwork(t *testing.T) {
+	for name, l := range map[string]Language{
+		"network first": Full(Network(), SemVer()),
+		"semver first":  Full(SemVer(), Network()),
+	} {
+		for _, test := range []struct {
+			expression string
+			want       interface{}
+		}{
+			{`ip("10.0.0.2") < ip("10.0.0.10")`, true},
+			{`ip("10.0.0.2") > "10.0.0.10"`, false},
+			{`semver("1.10.0") > semver("1.9.0")`, true},
+			{`"1.9" < semver("1.10")`, true},
+			{`ip("10.0.0.1") == "10.0.0.1" && semver("1.0") == "1.0.0"`, true},
+			{`"b" > "a" && 2 > 10 == false && semver("1.0") != ip("::1")`, true},
+		} {
+			got, err := l.Evaluate(test.expression, nil)
+			if err != nil || got != test.want {
+				t.Errorf("%s: Evaluate(%s) = %v, %v, want %v", name, test.expression, got, err, test.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Sets contains set algebra on arrays. Elements are compared like by the in operator,
+// numbers by value like 1 and int64(1), other elements with reflect.DeepEqual.
+// Results keep the order of the first occurrence of each element.
+//
+//	Operator union: a union b contains all distinct elements of a and b
+//	Operator intersect: a intersect b contains the distinct elements of a that are in b
+//	Operator except: a except b contains the distinct elements of a that are not in b
+//
+//	distinct(a)        the distinct elements of a
+//	containsAll(a, b)  true iff every element of b is in a
+//	containsAny(a, b)  true iff at least one element of b is in a
+//
+// Operands may be []interface{}, any other slice or array, an Iterator or a channel.
+func Sets() Language {
+	return sets
+}
+
+var sets = NewLanguage(
+	InfixContextOperator("union", func(c context.Context, a, b interface{}) (interface{}, error) {
+		x, y, err := setOperands(c, "union", a, b)
+		if err != nil {
+			return nil, err
+		}
+		return distinct(c, append(append([]interface{}{}, x...), y...))
+	}),
+	InfixContextOperator("intersect", func(c context.Context, a, b interface{}) (interface{}, error) {
+		return filterSet(c, "intersect", a, b, true)
+	}),
+	InfixContextOperator("except", func(c context.Context, a, b interface{}) (interface{}, error) {
+		return filterSet(c, "except", a, b, false)
+	}),
+
+	Function("distinct", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 1 {
+			return nil, fmt.Errorf("distinct() expects exactly one array argument")
+		}
+		x, err := setOperand(c, "distinct()", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return distinct(c, x)
+	}),
+	Function("containsAll", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 {
+			return nil, fmt.Errorf("containsAll() expects exactly two array arguments")
+		}
+		x, y, err := setOperands(c, "containsAll", arguments[0], arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		set, err := newElementSet(c, x)
+		if err != nil {
+			return nil, err
+		}
+		for i, e := range y {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
+			if !set.contains(e) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}),
+	Function("containsAny", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 {
+			return nil, fmt.Errorf("containsAny() expects exactly two array arguments")
+		}
+		x, y, err := setOperands(c, "containsAny", arguments[0], arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		set, err := newElementSet(c, x)
+		if err != nil {
+			return nil, err
+		}
+		for i, e := range y {
+			if err := CheckContext(c, i); err != nil {
+				return nil, err
+			}
+			if set.contains(e) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}),
+
+	Precedence("union", 120),
+	Precedence("except", 120),
+	Precedence("intersect", 150),
+)
+
+// filterSet returns the distinct elements of a that are in b if contained is true, otherwise the ones that are not in b.
+func filterSet(c context.Context, name string, a, b interface{}, contained bool) (interface{}, error) {
+	x, y, err := setOperands(c, name, a, b)
+	if err != nil {
+		return nil, err
+	}
+	x, err = distinct(c, x)
+	if err != nil {
+		return nil, err
+	}
+	set, err := newElementSet(c, y)
+	if err != nil {
+		return nil, err
+	}
+	r := []interface{}{}
+	for i, e := range x {
+		if err := CheckContext(c, i); err != nil {
+			return nil, err
+		}
+		if set.contains(e) == contained {
+			r = append(r, e)
+		}
+	}
+	return r, nil
+}
+
+func setOperands(c context.Context, name string, a, b interface{}) ([]interface{}, []interface{}, error) {
+	x, err := setOperand(c, name, a)
+	if err != nil {
+		return nil, nil, err
+	}
+	y, err := setOperand(c, name, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return x, y, nil
+}
+
+// setOperand converts an array, Iterator or channel to []interface{}.
+func setOperand(c context.Context, name string, o interface{}) ([]interface{}, error) {
+	o, err := collect(c, o)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := convertToSlice(o)
+	if !ok {
+		return nil, fmt.Errorf("%s unexpected %v(%T) expected array", name, o, o)
+	}
+	return s, nil
+}
+
+// convertToSlice converts any slice or array to []interface{}.
+func convertToSlice(o interface{}) ([]interface{}, bool) {
+	if s, ok := o.([]interface{}); ok {
+		return s, true
+	}
+	v := reflect.ValueOf(o)
+	for o != nil && v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = v.Index(i).Interface()
+		}
+		return s, true
+	}
+	return nil, false
+}
+
+func distinct(c context.Context, s []interface{}) ([]interface{}, error) {
+	r := make([]interface{}, 0, len(s))
+	set := &elementSet{hashed: make(map[interface{}]struct{}, len(s))}
+	for i, e := range s {
+		if err := CheckContext(c, i); err != nil {
+			return nil, err
+		}
+		if set.add(e) {
+			r = append(r, e)
+		}
+	}
+	return r, nil
+}
+
+// elementSet is a set of elements equal by inEqual.
+// Numbers, strings, bools and nil are kept in a map, other elements like arrays are compared one by one.
+type elementSet struct {
+	hashed map[interface{}]struct{}
+	others []interface{}
+}
+
+func newElementSet(c context.Context, s []interface{}) (*elementSet, error) {
+	set := &elementSet{hashed: make(map[interface{}]struct{}, len(s))}
+	for i, e := range s {
+		if err := CheckContext(c, i); err != nil {
+			return nil, err
+		}
+		set.add(e)
+	}
+	return set, nil
+}
+
+// add adds e and returns true if e was not in the set.
+func (s *elementSet) add(e interface{}) bool {
+	if s.contains(e) {
+		return false
+	}
+	if k, ok := elementKey(e); ok {
+		s.hashed[k] = struct{}{}
+	} else {
+		s.others = append(s.others, e)
+	}
+	return true
+}
+
+func (s *elementSet) contains(e interface{}) bool {
+	if k, ok := elementKey(e); ok {
+		_, found := s.hashed[k]
+		return found
+	}
+	for _, x := range s.others {
+		if inEqual(x, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// elementKey returns the map key of e if e is hashed by elementSet: numbers as float64 like inEqual compares them.
+func elementKey(e interface{}) (interface{}, bool) {
+	if f, ok := inNumber(e); ok {
+		return f, true
+	}
+	switch reflect.ValueOf(e).Kind() {
+	case reflect.Invalid, reflect.Bool, reflect.String:
+		return e, true
+	}
+	return nil, false
+}
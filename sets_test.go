@@ -0,0 +1,106 @@
+package gval
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSets(t *testing.T) {
+	roles := map[string]interface{}{
+		"roles":  []interface{}{"viewer", "editor", "viewer"},
+		"groups": []string{"staff", "admin"},
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "union",
+				expression: `roles union ["admin", "editor"]`,
+				extension:  Sets(),
+				parameter:  roles,
+				want:       []interface{}{"viewer", "editor", "admin"},
+			},
+			{
+				name:       "intersect",
+				expression: `roles intersect ["admin", "editor"]`,
+				extension:  Sets(),
+				parameter:  roles,
+				want:       []interface{}{"editor"},
+			},
+			{
+				name:       "except",
+				expression: `roles except ["editor"]`,
+				extension:  Sets(),
+				parameter:  roles,
+				want:       []interface{}{"viewer"},
+			},
+			{
+				name:       "precedence",
+				expression: `["a"] union ["b", "c"] intersect ["c"]`,
+				extension:  Sets(),
+				want:       []interface{}{"a", "c"},
+			},
+			{
+				name:       "typed slice",
+				expression: `groups intersect ["admin"]`,
+				extension:  Sets(),
+				parameter:  roles,
+				want:       []interface{}{"admin"},
+			},
+			{
+				name:       "deep equal",
+				expression: `[{"a": 1}, [1, 2]] intersect [[1, 2]]`,
+				extension:  Sets(),
+				want:       []interface{}{[]interface{}{1., 2.}},
+			},
+			{
+				name:       "distinct",
+				expression: `distinct(roles)`,
+				extension:  Sets(),
+				parameter:  roles,
+				want:       []interface{}{"viewer", "editor"},
+			},
+			{
+				name:       "containsAll",
+				expression: `[containsAll(roles, ["viewer", "editor"]), containsAll(roles, ["viewer", "admin"])]`,
+				extension:  Sets(),
+				parameter:  roles,
+				want:       []interface{}{true, false},
+			},
+			{
+				name:       "containsAny",
+				expression: `[containsAny(groups, ["admin", "root"]), containsAny(groups, ["root"]), containsAny(groups, [])]`,
+				extension:  Sets(),
+				parameter:  roles,
+				want:       []interface{}{true, false, false},
+			},
+			{
+				name:       "numbers by value",
+				expression: `distinct(numbers)`,
+				extension:  Sets(),
+				parameter:  map[string]interface{}{"numbers": []interface{}{1, 1., int64(2), json.Number("2"), "1", nil, nil}},
+				want:       []interface{}{1, int64(2), "1", nil},
+			},
+			{
+				name:       "typed numbers",
+				expression: `[ids intersect [1, 2], ids except [3], containsAll(ids, [3, 1]), containsAny([2], ids)]`,
+				extension:  Sets(),
+				parameter:  map[string]interface{}{"ids": []int{1, 3}},
+				want:       []interface{}{[]interface{}{1}, []interface{}{1}, true, false},
+			},
+			{
+				name:       "arrays by value",
+				expression: `distinct([[1, 2], [1, 2], ["1"]])`,
+				extension:  Sets(),
+				want:       []interface{}{[]interface{}{1., 2.}, []interface{}{"1"}},
+			},
+			{
+				name:       "no array",
+				expression: `roles union "admin"`,
+				extension:  Sets(),
+				parameter:  roles,
+				wantErr:    "union unexpected admin(string) expected array",
+			},
+		},
+		t,
+	)
+}
@@ -0,0 +1,89 @@
+package gval
+
+import (
+	"context"
+	"strings"
+	"text/scanner"
+)
+
+// Span is a tracing span started by a SpanStarter,
+// e.g. a small adapter of an OpenTelemetry span.
+type Span interface {
+	// SetAttribute sets an attribute of the span. Values are strings or ints.
+	SetAttribute(key string, value interface{})
+	// RecordError records the error of a failed evaluation.
+	RecordError(err error)
+	// End ends the span.
+	End()
+}
+
+// SpanStarter starts a span with given name as child of the span in c
+// and returns the context containing the started span.
+type SpanStarter func(c context.Context, name string) (context.Context, Span)
+
+// SpanOptions configure the spans of WithSpans.
+type SpanOptions struct {
+	// Name is the name of the spans, "gval.Evaluate" if empty.
+	Name string
+	// Redact returns the expression recorded as attribute gval.expression, e.g. RedactLiterals.
+	// The attribute is omitted if Redact returns an empty string.
+	// Without Redact the expression is recorded unchanged.
+	Redact func(expression string) string
+}
+
+// WithSpans returns a Language whose Evaluables evaluate within a span started by start.
+// The spans record the expression as attribute gval.expression,
+// its number of operands and operators as gval.nodes and the error of failed evaluations.
+//
+// Functions and selectors get the context containing the span,
+// so spans they start are children of the span of the evaluation.
+func WithSpans(start SpanStarter, options SpanOptions) Language {
+	name := options.Name
+	if name == "" {
+		name = "gval.Evaluate"
+	}
+	l := newLanguage()
+	l.setOption(option{name: "spans", wrapParsed: func(eval Evaluable, expression string, nodes int) Evaluable {
+		if options.Redact != nil {
+			expression = options.Redact(expression)
+		}
+		return func(c context.Context, parameter interface{}) (interface{}, error) {
+			if c == nil {
+				c = context.Background()
+			}
+			c, span := start(c, name)
+			if expression != "" {
+				span.SetAttribute("gval.expression", expression)
+			}
+			span.SetAttribute("gval.nodes", nodes)
+			v, err := eval(c, parameter)
+			if err != nil {
+				span.RecordError(err)
+			}
+			span.End()
+			return v, err
+		}
+	}})
+	return l
+}
+
+// RedactLiterals returns the expression with each string and number literal replaced by ?,
+// e.g. `password == "secret"` becomes `password == ?`.
+func RedactLiterals(expression string) string {
+	s := scanner.Scanner{}
+	s.Init(strings.NewReader(expression))
+	s.Error = func(*scanner.Scanner, string) {}
+	s.Mode = scanner.ScanIdents | scanner.ScanInts | scanner.ScanFloats | scanner.ScanStrings | scanner.ScanRawStrings | scanner.ScanChars
+	b := strings.Builder{}
+	last := 0
+	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
+		switch tok {
+		case scanner.Int, scanner.Float, scanner.String, scanner.RawString, scanner.Char:
+			b.WriteString(expression[last:s.Position.Offset])
+			b.WriteString("?")
+			last = s.Pos().Offset
+		}
+	}
+	b.WriteString(expression[last:])
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.err = err }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testSpanKey struct{}
+
+func TestWithSpans(t *testing.T) {
+	var spans []*testSpan
+	start := func(c context.Context, name string) (context.Context, Span) {
+		span := &testSpan{name: name, attributes: map[string]interface{}{}}
+		spans = append(spans, span)
+		return context.WithValue(c, testSpanKey{}, span), span
+	}
+	errFail := errors.New("fail")
+	lang := Full(
+		WithSpans(start, SpanOptions{Redact: RedactLiterals}),
+		Function("span", func(c context.Context) (interface{}, error) {
+			if c.Value(testSpanKey{}) == nil {
+				return nil, errFail
+			}
+			return true, nil
+		}),
+	)
+
+	eval, err := lang.NewEvaluable(`name == "secret" && span()`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := eval(nil, map[string]interface{}{"name": "secret"})
+	if err != nil || got != true {
+		t.Fatalf("Evaluate() = %v, %v, want true", got, err)
+	}
+	want := &testSpan{
+		name:       "gval.Evaluate",
+		attributes: map[string]interface{}{"gval.expression": `name == ? && span()`, "gval.nodes": 5},
+		ended:      true,
+	}
+	if len(spans) != 1 || !reflect.DeepEqual(spans[0], want) {
+		t.Errorf("spans = %#v, want %#v", spans, want)
+	}
+
+	spans = nil
+	program, err := Full(WithSpans(start, SpanOptions{Name: "rule"})).Compile(`a / b > 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := program.Evaluate(context.Background(), map[string]interface{}{"a": 1, "b": "x"}); err == nil {
+		t.Fatal("Evaluate() expected error")
+	}
+	if len(spans) != 1 || spans[0].name != "rule" || spans[0].err == nil || !spans[0].ended ||
+		spans[0].attributes["gval.expression"] != `a / b > 1` || spans[0].attributes["gval.nodes"] != 5 {
+		t.Errorf("spans = %#v", spans)
+	}
+}
+
+func TestRedactLiterals(t *testing.T) {
+	tests := map[string]string{
+		`password == "secret"`:                 `password == ?`,
+		`a > 1.5 && b in ["x", 'y', ` + "`z`]": `a > ? && b in [?, ?, ?]`,
+		`foo.bar`:                              `foo.bar`,
+	}
+	for expression, want := range tests {
+		if got := RedactLiterals(expression); got != want {
+			t.Errorf("RedactLiterals(%s) = %s, want %s", expression, got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package gval
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// SQL returns a Language selecting database values like sql.NullString or sql.NullInt64 as their value,
+// nil if they are NULL. It unwraps the variables of all types implementing driver.Valuer,
+// so name == "bob" or age > 18 work with fields of type sql.NullString and sql.NullInt64.
+//
+// The rows of a query are evaluated with Evaluable.EvalRows.
+func SQL() Language {
+	return VariableSelector(func(path Evaluables) Evaluable {
+		selection := variable(path)
+		return func(c context.Context, v interface{}) (interface{}, error) {
+			r, err := selection(c, v)
+			if err != nil {
+				return nil, err
+			}
+			return sqlValue(r)
+		}
+	})
+}
+
+// sqlValue returns the value of a driver.Valuer, otherwise v.
+func sqlValue(v interface{}) (interface{}, error) {
+	valuer, ok := v.(driver.Valuer)
+	if !ok {
+		return v, nil
+	}
+	r, err := valuer.Value()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %T: %w", v, err)
+	}
+	return r, nil
+}
+
+// EvalRows evaluates e for each row of rows with the columns as variables and calls f with the row and the result.
+// Bytes like the values of text columns are strings and NULL is nil. EvalRows stops at the first error of e or f.
+// It does not close rows.
+func (e Evaluable) EvalRows(c context.Context, rows *sql.Rows, f func(row map[string]interface{}, result interface{}) error) error {
+	if c == nil {
+		c = context.Background()
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for i := 0; rows.Next(); i++ {
+		if err := CheckContext(c, i); err != nil {
+			return err
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for j, column := range columns {
+			if b, ok := values[j].([]byte); ok {
+				row[column] = string(b)
+				continue
+			}
+			row[column] = values[j]
+		}
+		result, err := e(c, row)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+		if err := f(row, result); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
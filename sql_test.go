@@ -0,0 +1,133 @@
+package gval
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testDriver returns the rows of testTable for every query
+type testDriver struct{}
+
+var testTable = struct {
+	columns []string
+	rows    [][]driver.Value
+}{
+	columns: []string{"name", "age", "email"},
+	rows: [][]driver.Value{
+		{[]byte("ann"), int64(34), []byte("ann@example.com")},
+		{[]byte("bob"), int64(17), nil},
+		{[]byte("eve"), int64(52), nil},
+	},
+}
+
+func (testDriver) Open(name string) (driver.Conn, error) { return testConn{}, nil }
+
+type testConn struct{}
+
+func (testConn) Prepare(query string) (driver.Stmt, error) { return testStmt{}, nil }
+func (testConn) Close() error                              { return nil }
+func (testConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type testStmt struct{}
+
+func (testStmt) Close() error  { return nil }
+func (testStmt) NumInput() int { return -1 }
+func (testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (testStmt) Query(args []driver.Value) (driver.Rows, error) { return &testDriverRows{}, nil }
+
+type testDriverRows struct{ next int }
+
+func (r *testDriverRows) Columns() []string { return testTable.columns }
+func (r *testDriverRows) Close() error      { return nil }
+func (r *testDriverRows) Next(dest []driver.Value) error {
+	if r.next >= len(testTable.rows) {
+		return io.EOF
+	}
+	copy(dest, testTable.rows[r.next])
+	r.next++
+	return nil
+}
+
+func init() {
+	sql.Register("gvaltest", testDriver{})
+}
+
+func TestSQL(t *testing.T) {
+	type user struct {
+		Name  sql.NullString
+		Age   sql.NullInt64
+		Email sql.NullString
+	}
+	parameter := map[string]interface{}{
+		"user": user{
+			Name: sql.NullString{String: "bob", Valid: true},
+			Age:  sql.NullInt64{Int64: 17, Valid: true},
+		},
+		"score": sql.NullFloat64{Float64: 0.5, Valid: true},
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "string", expression: `user.Name == "bob"`, extension: SQL(), parameter: parameter, want: true},
+			{name: "int", expression: `user.Age + 1`, extension: SQL(), parameter: parameter, want: 18.},
+			{name: "null", expression: `user.Email ?? "none"`, extension: SQL(), parameter: parameter, want: "none"},
+			{name: "float", expression: `score * 2`, extension: SQL(), parameter: parameter, want: 1.},
+			{name: "valid", expression: `user.Email.Valid`, extension: SQL(), parameter: parameter, want: false},
+			{name: "without SQL", expression: `user.Name == "bob"`, parameter: parameter, want: false},
+		},
+		t,
+	)
+}
+
+func TestEvaluable_EvalRows(t *testing.T) {
+	db, err := sql.Open("gvaltest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	query := func() *sql.Rows {
+		rows, err := db.Query("SELECT name, age, email FROM users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rows
+	}
+
+	eval, err := Full().NewEvaluable(`age >= 18 && email == nil`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := query()
+	defer rows.Close()
+	var names []interface{}
+	err = eval.EvalRows(context.Background(), rows, func(row map[string]interface{}, result interface{}) error {
+		if result == true {
+			names = append(names, row["name"])
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"eve"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("EvalRows() selected %v, want %v", names, want)
+	}
+
+	eval, err = Full().NewEvaluable(`email.domain`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows = query()
+	defer rows.Close()
+	err = eval.EvalRows(context.Background(), rows, func(row map[string]interface{}, result interface{}) error { return nil })
+	if err == nil || !strings.HasPrefix(err.Error(), "row 0: ") || !errors.Is(err, ErrUnknownParameter) {
+		t.Errorf("EvalRows() error = %v, want unknown parameter in row 0", err)
+	}
+}
@@ -0,0 +1,140 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrTypeMismatch is matched by errors.Is for all TypeMismatchErrors.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// TypeMismatchError reports an operator of a Strict Language applied to operands of unexpected types.
+type TypeMismatchError struct {
+	// Operator is the name of the operator, e.g. > or !
+	Operator string
+	// Operands are the values the operator is applied to
+	Operands []interface{}
+}
+
+func (err *TypeMismatchError) Error() string {
+	if len(err.Operands) == 1 {
+		return fmt.Sprintf("type mismatch: invalid operation %s (%T)", err.Operator, err.Operands[0])
+	}
+	return fmt.Sprintf("type mismatch: invalid operation (%T) %s (%T)", err.Operands[0], err.Operator, err.Operands[1])
+}
+
+// Is returns true for ErrTypeMismatch.
+func (err *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+// Strict returns a Language applying operators without implicit conversions of their operands:
+// strings are not parsed as numbers or booleans, numbers are no booleans
+// and values are not formatted as strings, so "true" > 5, "1" + 1 and !0 fail with a TypeMismatchError.
+// Conditions of the ternary operators a ? b : c and a ?: b must be bool as well, so 0 ? b : c fails instead of being false.
+// Values of all Go number types are numbers. == and != compare values of different types as unequal.
+// Strict replaces the prefix operators ! and -, so it is given after the Languages defining them,
+// e.g. Full(Strict()).
+func Strict() Language {
+	l := NewLanguage(
+		PrefixOperator("!", func(c context.Context, v interface{}) (interface{}, error) {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, &TypeMismatchError{Operator: "!", Operands: []interface{}{v}}
+			}
+			return !b, nil
+		}),
+		PrefixOperator("-", func(c context.Context, v interface{}) (interface{}, error) {
+			if d, ok := v.(decimal.Decimal); ok {
+				return d.Neg(), nil
+			}
+			f, ok := strictFloat(v)
+			if !ok {
+				return nil, &TypeMismatchError{Operator: "-", Operands: []interface{}{v}}
+			}
+			return -f, nil
+		}),
+	)
+	l.setOption(option{name: "strict", value: true})
+	return l
+}
+
+// isStrict returns if the Language is Strict.
+func (l Language) isStrict() bool {
+	return l.optionValue("strict") == true
+}
+
+// modeOperator returns the infix operator op named name as it is applied by the Language,
+// i.e. Strict and propagating nil if the Language does so.
+func (l Language) modeOperator(name string, op *infix) *infix {
+	if l.isStrict() {
+		op = op.strict(name)
+	}
+	if l.propagatesNil(name) {
+		op = op.propagatingNil()
+	}
+	return op
+}
+
+// strict returns a copy of op applied to operands of matching types only.
+func (op infix) strict(name string) *infix {
+	f := func(a, b interface{}) (interface{}, error) {
+		return nil, &TypeMismatchError{Operator: name, Operands: []interface{}{a, b}}
+	}
+	if op.arbitrary != nil {
+		f = op.arbitrary
+	}
+	if len(op.typed) > 0 {
+		f = getTypedOpFunc(op.typed, f)
+	}
+	if op.text != nil {
+		f = getStringOpFunc(op.text, f, false)
+	}
+	if op.boolean != nil {
+		f = getBoolOpFunc(op.boolean, f, false)
+	}
+	if number := op.number; number != nil {
+		next := f
+		f = func(a, b interface{}) (interface{}, error) {
+			x, k := strictFloat(a)
+			y, l := strictFloat(b)
+			if k && l {
+				return number(x, y)
+			}
+			return next(a, b)
+		}
+	}
+	if dec := op.decimal; dec != nil {
+		next := f
+		f = func(a, b interface{}) (interface{}, error) {
+			if isString(a) || isString(b) {
+				return next(a, b)
+			}
+			x, k := convertToDecimal(a)
+			y, l := convertToDecimal(b)
+			if k && l {
+				return dec(x, y)
+			}
+			return next(a, b)
+		}
+	}
+	op.setF(f)
+	return &op
+}
+
+// strictFloat converts values of Go number types to float64 like convertToFloat, but no strings.
+func strictFloat(o interface{}) (float64, bool) {
+	if isString(o) {
+		return 0, false
+	}
+	return convertToFloat(o)
+}
+
+// isString returns if o is a string or a pointer to a string.
+func isString(o interface{}) bool {
+	return reflect.Indirect(reflect.ValueOf(o)).Kind() == reflect.String
+}
@@ -0,0 +1,64 @@
+package gval
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	parameter := map[string]interface{}{"n": 5, "s": "5", "b": true, "f": 1.5}
+	l := Full(Strict())
+	for expression, want := range map[string]interface{}{
+		"n > 4":            true,
+		"n + f":            6.5,
+		`s + "x"`:          "5x",
+		`s == "5"`:         true,
+		"s == n":           false,
+		"n != s":           true,
+		"b && !false":      true,
+		"false && s":       false,
+		"-n":               -5.,
+		`"a" < "b"`:        true,
+		"nil == nil":       true,
+		"[1, 2] == [1, 2]": true,
+		"f in [1, 1.5]":    true,
+		"(n > 4) == true":  true,
+		"b ? 1 : 2":        1.,
+		"false ?: n":       5,
+	} {
+		got, err := l.Evaluate(expression, parameter)
+		if err != nil {
+			t.Errorf("Evaluate(%s) error = %v", expression, err)
+		} else if got != want {
+			t.Errorf("Evaluate(%s) = %v, want %v", expression, got, want)
+		}
+	}
+	for _, expression := range []string{
+		`"true" > 5`,
+		"s > 4",
+		`"1" + 1`,
+		"n && true",
+		"!0",
+		"-s",
+		`b + "x"`,
+		`s ? 1 : 2`,
+		"n ?: 1",
+		"nil ? 1 : 2",
+	} {
+		_, err := l.Evaluate(expression, parameter)
+		if !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("Evaluate(%s) error = %v, want ErrTypeMismatch", expression, err)
+		}
+		if _, err := Full().Evaluate(expression, parameter); err != nil {
+			t.Errorf("Evaluate(%s) without Strict error = %v", expression, err)
+		}
+	}
+
+	d := NewLanguage(Full(), DecimalArithmetic(), Strict())
+	if got, err := d.Evaluate("-(n * 0.1) < 0", parameter); err != nil || got != true {
+		t.Errorf("Evaluate(-(n * 0.1) < 0) = %v, %v, want true", got, err)
+	}
+	if _, err := d.Evaluate(`s * 2`, parameter); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Evaluate(s * 2) error = %v, want ErrTypeMismatch", err)
+	}
+}
@@ -0,0 +1,51 @@
+package gval
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Targeting returns a Language for the targeting rules of feature flags. It is Full with Time and SemVer and
+//
+//	bucket(key, percentage)        true for the percentage of keys like user ids, e.g. bucket(user.id, 20)
+//	bucket(key, percentage, salt)  like bucket(key, percentage) but independent of rollouts with other salts
+//
+// so rules like the following are supported:
+//
+//	user.country in ["DE", "AT"] && bucket(user.id, 20, "checkout")
+//	semver(user.appVersion) >= "2.1" && now() between ["2024-01-01", "2024-02-01"]
+//
+// bucket hashes the key and the salt by FNV-1a into one of 10000 buckets, so a key stays in the rollout
+// when its percentage is increased and the same keys are selected by every process.
+// Percentages are numbers from 0 to 100 and may have two decimals like 0.25.
+func Targeting() Language {
+	return targeting
+}
+
+var targeting = NewLanguage(full, timeLanguage, semVer,
+	Function("bucket", func(arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 && len(arguments) != 3 {
+			return nil, fmt.Errorf("bucket() expects a key, a percentage and an optional salt")
+		}
+		percentage, ok := convertToFloat(arguments[1])
+		if !ok || percentage < 0 || percentage > 100 {
+			return nil, fmt.Errorf("bucket() unexpected %v(%T) expected percentage from 0 to 100", arguments[1], arguments[1])
+		}
+		salt := ""
+		if len(arguments) == 3 {
+			s, err := stringArgument("bucket", arguments[2])
+			if err != nil {
+				return nil, err
+			}
+			salt = s
+		}
+		return float64(bucketOf(arguments[0], salt)) < percentage*100, nil
+	}, Arity(2, 3), Pure()),
+)
+
+// bucketOf returns the bucket from 0 to 9999 of key for the salt.
+func bucketOf(key interface{}, salt string) uint32 {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%v", salt, key)
+	return h.Sum32() % 10000
+}
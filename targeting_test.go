@@ -0,0 +1,62 @@
+package gval
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTargeting(t *testing.T) {
+	l := Targeting()
+	user := map[string]interface{}{
+		"user": map[string]interface{}{"id": "u-42", "country": "DE", "appVersion": "2.10.0"},
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "country", expression: `user.country in ["DE", "AT"]`, extension: l, parameter: user, want: true},
+			{name: "semver", expression: `semver(user.appVersion) >= "2.9" && semver(user.appVersion) ~> "2.1"`, extension: l, parameter: user, want: true},
+			{name: "date window", expression: `date("2024-01-15") between ["2024-01-01", "2024-02-01"] && !(date("2024-02-15") between ["2024-01-01", "2024-02-01"])`, extension: l, want: true},
+			{name: "now", expression: `now() > date("2000-01-01")`, extension: l, want: true},
+			{name: "bucket bounds", expression: `[bucket(user.id, 0), bucket(user.id, 100), bucket(user.id, 0, "flag")]`, extension: l, parameter: user, want: []interface{}{false, true, false}},
+			{name: "invalid percentage", expression: `bucket(user.id, 101)`, extension: l, parameter: user, wantErr: "bucket() unexpected 101(float64) expected percentage from 0 to 100"},
+			{name: "invalid salt", expression: `bucket(user.id, 20, 1)`, extension: l, parameter: user, wantErr: "bucket() unexpected 1(float64) expected string"},
+			{name: "missing percentage", expression: `bucket(user.id)`, extension: l, parameter: user, wantErr: "bucket"},
+		},
+		t,
+	)
+}
+
+func TestTargetingBucket(t *testing.T) {
+	rollout, err := Targeting().NewEvaluable(`[bucket(id, 10), bucket(id, 20), bucket(id, 20, "other")]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ten, twenty, other, both int
+	for i := 0; i < 10000; i++ {
+		got, err := rollout(context.Background(), map[string]interface{}{"id": float64(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		in := got.([]interface{})
+		if in[0] == true {
+			ten++
+			if in[1] != true {
+				t.Fatalf("id %d is in the 10%% but not in the 20%% rollout", i)
+			}
+		}
+		if in[1] == true {
+			twenty++
+		}
+		if in[2] == true {
+			other++
+			if in[1] == true {
+				both++
+			}
+		}
+	}
+	if ten < 900 || ten > 1100 || twenty < 1850 || twenty > 2150 || other < 1850 || other > 2150 {
+		t.Errorf("rollouts of 10%%, 20%% and 20%% selected %d, %d and %d of 10000 ids", ten, twenty, other)
+	}
+	if both > 550 {
+		t.Errorf("%d of 10000 ids are in both 20%% rollouts, want about 400", both)
+	}
+}
@@ -0,0 +1,309 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Time contains functions for dates and durations.
+//
+//	now()                          current time
+//	date(s)                        parses string s. s must match RFC3339, ISO8601, ruby date, or unix date
+//	date(s, layout)                parses string s with the given Go time layout
+//	date(s, layout, zone)          parses string s with the given layout in the IANA time zone
+//	format(t, layout)              formats time t with the given Go time layout
+//	format(t, layout, zone)        formats time t in the IANA time zone
+//	addDuration(t, d)              adds duration d (e.g. "72h" or "-1h30m") to t
+//	addDate(t, years, months, days) adds the given years, months and days to t
+//	truncate(t, unit)              rounds t down to a multiple of unit. unit is a duration
+//	                               (e.g. "15m") or one of "day", "month" or "year"
+//	dayOfWeek(t)                   english name of the weekday of t (e.g. "Monday")
+//
+// All functions which expect a time accept time.Time and strings, which are parsed like date(s).
+// Without explicit zone, time is parsed in the location given by WithLocation or
+// ContextWithLocation, otherwise in time.Local. now() is returned in the same location.
+func Time() Language {
+	return timeLanguage
+}
+
+var timeLanguage = NewLanguage(
+	Function("now", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 0 {
+			return nil, fmt.Errorf("now() expects no arguments")
+		}
+		return time.Now().In(location(c)), nil
+	}, Arity(0, 0)),
+	Function("date", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) < 1 || len(arguments) > 3 {
+			return nil, fmt.Errorf("date() expects a string, an optional layout and an optional zone")
+		}
+		s, err := stringArgument("date", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		loc, err := zoneArgument("date", location(c), arguments, 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(arguments) == 1 {
+			return parseDate(s, loc)
+		}
+		layout, err := stringArgument("date", arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		return time.ParseInLocation(layout, s, loc)
+	}, Arity(1, 3), Validate(constantStrings), foldDate),
+	Function("format", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 && len(arguments) != 3 {
+			return nil, fmt.Errorf("format() expects a time, a layout and an optional zone")
+		}
+		t, err := timeArgument("format", location(c), arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		layout, err := stringArgument("format", arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		loc, err := zoneArgument("format", t.Location(), arguments, 2)
+		if err != nil {
+			return nil, err
+		}
+		return t.In(loc).Format(layout), nil
+	}, Arity(2, 3)),
+	Function("addDuration", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 {
+			return nil, fmt.Errorf("addDuration() expects a time and a duration")
+		}
+		t, err := timeArgument("addDuration", location(c), arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		d, err := durationArgument("addDuration", arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		return t.Add(d), nil
+	}, Arity(2, 2)),
+	Function("addDate", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 4 {
+			return nil, fmt.Errorf("addDate() expects a time, years, months and days")
+		}
+		t, err := timeArgument("addDate", location(c), arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		var ymd [3]int
+		for i, a := range arguments[1:] {
+			f, ok := convertToFloat(a)
+			if !ok {
+				return nil, fmt.Errorf("addDate() unexpected %v(%T) expected number", a, a)
+			}
+			ymd[i] = int(f)
+		}
+		return t.AddDate(ymd[0], ymd[1], ymd[2]), nil
+	}, Arity(4, 4)),
+	Function("truncate", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 {
+			return nil, fmt.Errorf("truncate() expects a time and a unit")
+		}
+		t, err := timeArgument("truncate", location(c), arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		unit, err := stringArgument("truncate", arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(unit) {
+		case "day":
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()), nil
+		case "month":
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), nil
+		case "year":
+			return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()), nil
+		}
+		d, err := durationArgument("truncate", unit)
+		if err != nil {
+			return nil, err
+		}
+		return t.Truncate(d), nil
+	}, Arity(2, 2)),
+	Function("dayOfWeek", func(c context.Context, arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 1 {
+			return nil, fmt.Errorf("dayOfWeek() expects exactly one time argument")
+		}
+		t, err := timeArgument("dayOfWeek", location(c), arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return t.Weekday().String(), nil
+	}, Arity(1, 1)),
+)
+
+type locationKey struct{}
+
+// WithLocation returns a Language that parses dates and returns now() in the given location
+// instead of time.Local. A location given by ContextWithLocation takes precedence.
+func WithLocation(loc *time.Location) Language {
+	l := newLanguage()
+	l.setOption(option{
+		name: "location",
+		wrap: func(eval Evaluable) Evaluable {
+			if eval.IsConst() {
+				return eval
+			}
+			return func(c context.Context, parameter interface{}) (interface{}, error) {
+				if c == nil {
+					c = context.Background()
+				}
+				if _, ok := c.Value(locationKey{}).(*time.Location); !ok {
+					c = ContextWithLocation(c, loc)
+				}
+				return eval(c, parameter)
+			}
+		},
+	})
+	return l
+}
+
+// ContextWithLocation returns a copy of c in which dates are parsed and now() is returned in the given location.
+func ContextWithLocation(c context.Context, loc *time.Location) context.Context {
+	return context.WithValue(c, locationKey{}, loc)
+}
+
+// location returns the location of c or time.Local
+func location(c context.Context) *time.Location {
+	if c != nil {
+		if loc, ok := c.Value(locationKey{}).(*time.Location); ok && loc != nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+var dateLayouts = [...]string{
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+	time.Kitchen,
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02",                         // RFC 3339
+	"2006-01-02 15:04",                   // RFC 3339 with minutes
+	"2006-01-02 15:04:05",                // RFC 3339 with seconds
+	"2006-01-02 15:04:05-07:00",          // RFC 3339 with seconds and timezone
+	"2006-01-02T15Z0700",                 // ISO8601 with hour
+	"2006-01-02T15:04Z0700",              // ISO8601 with minutes
+	"2006-01-02T15:04:05Z0700",           // ISO8601 with seconds
+	"2006-01-02T15:04:05.999999999Z0700", // ISO8601 with nanoseconds
+}
+
+// parseDate parses s with the first matching layout of dateLayouts
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		ret, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return ret, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date() could not parse %s", s)
+}
+
+// dateLayout returns the first layout of dateLayouts matching s.
+// Whether a layout matches does not depend on the location.
+func dateLayout(s string) (string, bool) {
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return layout, true
+		}
+	}
+	return "", false
+}
+
+// foldDate looks up the layout of constant dates like date("2024-01-01") while parsing,
+// so they are parsed with this layout only on every evaluation.
+var foldDate = folding(func(args []interface{}) function {
+	if len(args) != 1 {
+		return nil
+	}
+	s, ok := args[0].(string)
+	if !ok {
+		return nil
+	}
+	layout, ok := dateLayout(s)
+	if !ok {
+		return nil
+	}
+	return func(c context.Context, _ ...interface{}) (interface{}, error) {
+		return time.ParseInLocation(layout, s, location(c))
+	}
+})
+
+// constantStrings fails for constant arguments that are no strings, e.g. date(2024).
+func constantStrings(args []Evaluable) error {
+	for i, arg := range args {
+		if !arg.IsConst() {
+			continue
+		}
+		v, err := arg(context.Background(), nil)
+		if err != nil {
+			return err
+		}
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("unexpected %v(%T) as argument %d, expected string", v, v, i+1)
+		}
+	}
+	return nil
+}
+
+func stringArgument(name string, a interface{}) (string, error) {
+	s, ok := a.(string)
+	if !ok {
+		return "", fmt.Errorf("%s() unexpected %v(%T) expected string", name, a, a)
+	}
+	return s, nil
+}
+
+func timeArgument(name string, loc *time.Location, a interface{}) (time.Time, error) {
+	switch a := a.(type) {
+	case time.Time:
+		return a, nil
+	case string:
+		return parseDate(a, loc)
+	}
+	return time.Time{}, fmt.Errorf("%s() unexpected %v(%T) expected time", name, a, a)
+}
+
+func durationArgument(name string, a interface{}) (time.Duration, error) {
+	switch a := a.(type) {
+	case time.Duration:
+		return a, nil
+	case string:
+		d, err := time.ParseDuration(a)
+		if err != nil {
+			return 0, fmt.Errorf("%s() %w", name, err)
+		}
+		return d, nil
+	}
+	return 0, fmt.Errorf("%s() unexpected %v(%T) expected duration", name, a, a)
+}
+
+// zoneArgument returns the location named by arguments[i] or def if there are not enough arguments.
+func zoneArgument(name string, def *time.Location, arguments []interface{}, i int) (*time.Location, error) {
+	if len(arguments) <= i {
+		return def, nil
+	}
+	zone, err := stringArgument(name, arguments[i])
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("%s() %w", name, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,203 @@
+package gval
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := time.Date(2024, time.January, 1, 13, 45, 30, 0, time.UTC)
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "date",
+				expression: `date("2024-01-01 13:45:30", "2006-01-02 15:04:05", "UTC")`,
+				extension:  Time(),
+				want:       monday,
+			},
+			{
+				name:       "date default layouts",
+				expression: `date("2024-01-01T13:45:30Z") == t`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday},
+				want:       true,
+			},
+			{
+				name:       "date zone",
+				expression: `date("01.02.2024", "02.01.2006", "Europe/Berlin")`,
+				extension:  Time(),
+				want:       time.Date(2024, time.February, 1, 0, 0, 0, 0, berlin),
+			},
+			{
+				name:       "date invalid layout",
+				expression: `date("2024", "02.01.2006")`,
+				extension:  Time(),
+				wantErr:    "cannot parse",
+			},
+			{
+				name:       "date unknown zone",
+				expression: `date("2024-01-01", "2006-01-02", "Nowhere/Never")`,
+				extension:  Time(),
+				wantErr:    "unknown time zone",
+			},
+			{
+				name:       "format",
+				expression: `format(t, "Jan 2, 2006 15:04")`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday},
+				want:       "Jan 1, 2024 13:45",
+			},
+			{
+				name:       "format zone",
+				expression: `format(t, "15:04 MST", "Europe/Berlin")`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday},
+				want:       "14:45 CET",
+			},
+			{
+				name:       "addDuration",
+				expression: `addDuration(t, "72h")`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday},
+				want:       monday.Add(72 * time.Hour),
+			},
+			{
+				name:       "addDuration invalid",
+				expression: `addDuration(t, "3 days")`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday},
+				wantErr:    "addDuration() time: unknown unit",
+			},
+			{
+				name:       "addDate",
+				expression: `addDate(t, 0, 1, -1)`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday},
+				want:       monday.AddDate(0, 1, -1),
+			},
+			{
+				name:       "truncate duration",
+				expression: `truncate(t, "15m")`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday},
+				want:       time.Date(2024, time.January, 1, 13, 45, 0, 0, time.UTC),
+			},
+			{
+				name:       "truncate month",
+				expression: `truncate(t, "month")`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday.AddDate(0, 0, 14)},
+				want:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				name:       "dayOfWeek",
+				expression: `dayOfWeek(t) in ["Saturday", "Sunday"] ? "weekend" : dayOfWeek(t)`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": monday},
+				want:       "Monday",
+			},
+			{
+				name:       "dayOfWeek no time",
+				expression: `dayOfWeek(1)`,
+				extension:  Time(),
+				wantErr:    "dayOfWeek() unexpected 1(float64) expected time",
+			},
+			{
+				name:       "now",
+				expression: `now() > t`,
+				extension:  Time(),
+				parameter:  map[string]interface{}{"t": "2000-01-01"},
+				want:       true,
+			},
+		},
+		t,
+	)
+}
+
+func TestWithLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "full date",
+				expression: `date("2024-01-01 10:00")`,
+				extension:  WithLocation(tokyo),
+				want:       time.Date(2024, time.January, 1, 10, 0, 0, 0, tokyo),
+			},
+			{
+				name:       "time date",
+				expression: `date("01.01.2024 10:00", "02.01.2006 15:04")`,
+				extension:  NewLanguage(Time(), WithLocation(tokyo)),
+				want:       time.Date(2024, time.January, 1, 10, 0, 0, 0, tokyo),
+			},
+			{
+				name:       "explicit zone wins",
+				expression: `date("2024-01-01", "2006-01-02", "Europe/Berlin")`,
+				extension:  NewLanguage(Time(), WithLocation(tokyo)),
+				want:       time.Date(2024, time.January, 1, 0, 0, 0, 0, berlin),
+			},
+			{
+				name:       "last location wins",
+				expression: `date("2024-01-01")`,
+				extension:  NewLanguage(WithLocation(berlin), WithLocation(tokyo)),
+				want:       time.Date(2024, time.January, 1, 0, 0, 0, 0, tokyo),
+			},
+			{
+				name:       "now",
+				expression: `format(now(), "MST")`,
+				extension:  NewLanguage(Time(), WithLocation(tokyo)),
+				want:       "JST",
+			},
+		},
+		t,
+	)
+
+	t.Run("context overrides language", func(t *testing.T) {
+		eval, err := Full(Time(), WithLocation(tokyo)).NewEvaluable(`date("2024-01-01")`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := eval(ContextWithLocation(context.Background(), time.UTC), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC); got != want {
+			t.Errorf("eval() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("constant date in each location", func(t *testing.T) {
+		for _, l := range []Language{Full(), Full(Time())} {
+			eval, err := l.NewEvaluable(`date("2024-01-01 10:00")`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, loc := range []*time.Location{tokyo, berlin, time.UTC, tokyo} {
+				got, err := eval(ContextWithLocation(context.Background(), loc), nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := time.Date(2024, time.January, 1, 10, 0, 0, 0, loc); got != want {
+					t.Errorf("eval() in %s = %v, want %v", loc, got, want)
+				}
+			}
+		}
+		if _, err := Full(Time()).Evaluate(`date("2024-13-01")`, nil); err == nil {
+			t.Error("date() of an invalid constant expected error")
+		}
+	})
+}
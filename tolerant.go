@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
@@ -20,15 +19,67 @@ const (
 	NilOnMissingField
 )
 
+// MissingFieldHandler resolves variables whose path can not be selected from the parameter.
+// It is called with the complete path of the variable.
+// MissingFieldBehavior, DefaultOnMissingField and MissingFieldFunc implement it.
+type MissingFieldHandler interface {
+	HandleMissingField(c context.Context, path []string) (interface{}, error)
+}
+
+// HandleMissingField returns the value for a missing field according to the behavior.
+func (behavior MissingFieldBehavior) HandleMissingField(c context.Context, path []string) (interface{}, error) {
+	switch behavior {
+	case FalseOnMissingField:
+		return false, nil
+	case NilOnMissingField:
+		return nil, nil
+	default: // ErrorOnMissingField
+		return nil, unknownParameterError{path}
+	}
+}
+
+// DefaultOnMissingField resolves missing fields to the value configured for their dotted path, e.g. "order.discount".
+// Missing fields without a configured default are still reported as unknown parameter,
+// so typos in variable paths are not hidden.
+type DefaultOnMissingField map[string]interface{}
+
+// HandleMissingField returns the configured default for path.
+func (defaults DefaultOnMissingField) HandleMissingField(c context.Context, path []string) (interface{}, error) {
+	if value, ok := defaults[strings.Join(path, ".")]; ok {
+		return value, nil
+	}
+	return nil, unknownParameterError{path}
+}
+
+// MissingFieldFunc resolves missing fields by calling the function with the path of the variable.
+// It allows to lazily fetch, log or meter missing parameters.
+type MissingFieldFunc func(c context.Context, path []string) (interface{}, error)
+
+// HandleMissingField calls f.
+func (f MissingFieldFunc) HandleMissingField(c context.Context, path []string) (interface{}, error) {
+	return f(c, path)
+}
+
 // WithMissingFieldBehavior creates a language that handles missing fields according to the specified behavior
-func WithMissingFieldBehavior(behavior MissingFieldBehavior) Language {
+func WithMissingFieldBehavior(behavior MissingFieldHandler) Language {
 	return VariableSelector(func(path Evaluables) Evaluable {
 		return func(c context.Context, v interface{}) (interface{}, error) {
 			keys, err := path.EvalStrings(c, v)
 			if err != nil {
 				return nil, err
 			}
-			for i, k := range keys {
+			for _, k := range keys {
+				if v, err = decoded(v, k); err != nil {
+					return nil, err
+				}
+				k = identifier(c, v, k)
+				if r, ok, err := selectIndex(c, v, k); ok {
+					if err != nil {
+						return nil, err
+					}
+					v = r
+					continue
+				}
 				switch o := v.(type) {
 				case Selector:
 					v, err = o.SelectGVal(c, k)
@@ -37,30 +88,30 @@ func WithMissingFieldBehavior(behavior MissingFieldBehavior) Language {
 					}
 					continue
 				case map[interface{}]interface{}:
-					if val, exists := o[k]; exists {
+					if val, exists := interfaceMapValue(o, k); exists {
 						v = val
 					} else {
-						return handleMissingField(behavior, keys[:i+1])
+						return handleMissingField(c, behavior, keys)
 					}
 					continue
 				case map[string]interface{}:
 					if val, exists := o[k]; exists {
 						v = val
 					} else {
-						return handleMissingField(behavior, keys[:i+1])
+						return handleMissingField(c, behavior, keys)
 					}
 					continue
 				case []interface{}:
-					if idx, err := strconv.Atoi(k); err == nil && idx >= 0 && len(o) > idx {
+					if idx, ok := sliceIndex(k, len(o)); ok {
 						v = o[idx]
 						continue
 					}
-					return handleMissingField(behavior, keys[:i+1])
+					return handleMissingField(c, behavior, keys)
 				default:
 					var ok bool
-					v, ok = reflectSelect(k, o)
+					v, ok = reflectSelect(c, k, o)
 					if !ok {
-						return handleMissingField(behavior, keys[:i+1])
+						return handleMissingField(c, behavior, keys)
 					}
 				}
 			}
@@ -69,15 +120,12 @@ func WithMissingFieldBehavior(behavior MissingFieldBehavior) Language {
 	})
 }
 
-func handleMissingField(behavior MissingFieldBehavior, keyPath []string) (interface{}, error) {
-	switch behavior {
-	case FalseOnMissingField:
-		return false, nil
-	case NilOnMissingField:
-		return nil, nil
-	default: // ErrorOnMissingField
-		return nil, fmt.Errorf("unknown parameter %s", strings.Join(keyPath, "."))
+// handleMissingField resolves the missing path with the behavior unless c is the context of a presence check like has(a.b).
+func handleMissingField(c context.Context, behavior MissingFieldHandler, path []string) (interface{}, error) {
+	if checksPresence(c) {
+		return nil, unknownParameterError{path}
 	}
+	return behavior.HandleMissingField(c, path)
 }
 
 // TolerantFull creates a Full language that treats missing fields as false
@@ -88,7 +136,7 @@ func TolerantFull() Language {
 		arithmetic, bitmask, text, propositionalLogic, ljson,
 		
 		// Additional operators
-		InfixOperator("in", inArray),
+		InfixContextOperator("in", inArray),
 		InfixShortCircuit("??", func(a interface{}) (interface{}, bool) {
 			v := reflect.ValueOf(a)
 			return a, a != nil && !v.IsZero()
@@ -101,8 +149,8 @@ func TolerantFull() Language {
 		}),
 		
 		// Custom filter operators
-		InfixOperator("cfa", cfaOperator),
-		InfixOperator("cfm", cfmOperator),
+		InfixContextOperator("cfa", cfaOperator),
+		InfixContextOperator("cfm", cfmOperator),
 		
 		ternaryOperator,
 		Function("date", func(arguments ...interface{}) (interface{}, error) {
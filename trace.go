@@ -0,0 +1,121 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Trace is the evaluation of a node of the syntax tree.
+// The traces of an evaluation form a tree explaining how its result came about.
+type Trace struct {
+	// Node is the evaluated node.
+	Node *Node
+	// Value and Err are the result of the node.
+	Value interface{}
+	Err   error
+	// ShortCircuit is true if an infix operation returned its result without evaluating its right operand,
+	// e.g. false && x.
+	ShortCircuit bool
+	// Children are the traces of the operands evaluated by the node in the order of their evaluation.
+	// Constant operands of infix operations are included, other constants are not.
+	Children []*Trace
+}
+
+// String returns the trace one node per line, the children indented below their parent.
+func (t *Trace) String() string {
+	b := &strings.Builder{}
+	t.write(b, 0)
+	return b.String()
+}
+
+func (t *Trace) write(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(t.Node.Text())
+	if t.Err != nil {
+		fmt.Fprintf(b, " failed: %v", t.Err)
+	} else {
+		fmt.Fprintf(b, " = %#v", t.Value)
+	}
+	if t.ShortCircuit {
+		b.WriteString(" (short circuit)")
+	}
+	b.WriteString("\n")
+	for _, child := range t.Children {
+		child.write(b, depth+1)
+	}
+}
+
+// EvaluateWithTrace evaluates given parameter with given expression in gval full language
+// and returns the trace of the evaluation.
+func EvaluateWithTrace(expression string, parameter interface{}, opts ...Language) (interface{}, *Trace, error) {
+	l := full
+	if len(opts) > 0 {
+		l = NewLanguage(append([]Language{l}, opts...)...)
+	}
+	return l.EvaluateWithTrace(context.Background(), expression, parameter)
+}
+
+// EvaluateWithTrace evaluates given parameter with given expression using context
+// and returns the trace of the evaluation. The trace is returned for failed evaluations as well.
+//
+// Tracing is slower than evaluating an Evaluable, use it to explain single results.
+func (l Language) EvaluateWithTrace(c context.Context, expression string, parameter interface{}) (interface{}, *Trace, error) {
+	node, eval, err := l.parseObserved(c, expression)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := &Trace{}
+	v, err := eval(ContextWithObserver(c, &tracer{language: l, stack: []*Trace{root}}), parameter)
+	if len(root.Children) == 1 {
+		return v, root.Children[0], err
+	}
+	return v, &Trace{Node: node, Value: v, Err: err}, err
+}
+
+// tracer is the EvaluationObserver collecting the traces of an evaluation.
+// The last trace of stack is the node evaluated currently.
+type tracer struct {
+	language Language
+	stack    []*Trace
+}
+
+func (t *tracer) OnEnterNode(c context.Context, n *Node) {
+	t.stack = append(t.stack, &Trace{Node: n})
+}
+
+func (t *tracer) OnExitNode(c context.Context, n *Node, value interface{}, err error) {
+	trace := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	trace.Value, trace.Err = value, err
+	if n.Kind == InfixNode {
+		var shortCircuit func(a interface{}) (interface{}, bool)
+		if op, ok := t.language.operators[n.Operator].(*infix); ok {
+			shortCircuit = op.shortCircuit
+		}
+		trace.addConstantOperands(shortCircuit)
+	}
+	parent := t.stack[len(t.stack)-1]
+	parent.Children = append(parent.Children, trace)
+}
+
+func (t *tracer) OnFunctionCall(context.Context, string, []interface{}, interface{}, error) {}
+
+// addConstantOperands adds the constant operands of an infix operation and marks short circuits.
+func (t *Trace) addConstantOperands(shortCircuit func(a interface{}) (interface{}, bool)) {
+	a, b := t.Node.Children[0], t.Node.Children[1]
+	if a.constant {
+		t.Children = append([]*Trace{{Node: a, Value: a.Value}}, t.Children...)
+	}
+	if len(t.Children) == 0 || t.Children[0].Err != nil {
+		return
+	}
+	if shortCircuit != nil {
+		_, t.ShortCircuit = shortCircuit(t.Children[0].Value)
+	} else {
+		t.ShortCircuit = !b.constant && len(t.Children) == 1 && t.Err == nil
+	}
+	if b.constant && !t.ShortCircuit {
+		t.Children = append(t.Children, &Trace{Node: b, Value: b.Value})
+	}
+}
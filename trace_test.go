@@ -0,0 +1,88 @@
+package gval
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEvaluateWithTrace(t *testing.T) {
+	parameter := map[string]interface{}{
+		"age":     20.,
+		"country": "DE",
+		"vip":     false,
+		"name":    "gval",
+	}
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantTrace  string
+	}{
+		{
+			name:       "rule",
+			expression: `(age >= 18 && country in ["DE", "AT"]) || vip ? name : "none"`,
+			want:       "gval",
+			wantTrace: `(age >= 18 && country in ["DE", "AT"]) || vip ? name : "none" = "gval"
+  (age >= 18 && country in ["DE", "AT"]) || vip = true (short circuit)
+    (age >= 18 && country in ["DE", "AT"]) = true
+      age >= 18 = true
+        age = 20
+        18 = 18
+      country in ["DE", "AT"] = true
+        country = "DE"
+        ["DE", "AT"] = []interface {}{"DE", "AT"}
+  name = "gval"
+`,
+		},
+		{
+			name:       "constant short circuit",
+			expression: `vip && age > 18 || age * 2 > 30`,
+			want:       true,
+			wantTrace: `vip && age > 18 || age * 2 > 30 = true
+  vip && age > 18 = false (short circuit)
+    vip = false
+  age * 2 > 30 = true
+    age * 2 = 40
+      age = 20
+      2 = 2
+    30 = 30
+`,
+		},
+		{
+			name:       "constant",
+			expression: `1 + 2`,
+			want:       3.,
+			wantTrace: `1 + 2 = 3
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, trace, err := EvaluateWithTrace(tt.expression, parameter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateWithTrace() = %v, want %v", got, tt.want)
+			}
+			if trace.String() != tt.wantTrace {
+				t.Errorf("EvaluateWithTrace() trace =\n%s\nwant\n%s", trace, tt.wantTrace)
+			}
+		})
+	}
+}
+
+func TestEvaluateWithTraceError(t *testing.T) {
+	errFail := errors.New("fail")
+	lang := NewLanguage(Full(), Function("fail", func() (interface{}, error) {
+		return nil, errFail
+	}))
+	_, trace, err := lang.EvaluateWithTrace(context.Background(), `1 < 2 && fail()`, nil)
+	if !errors.Is(err, errFail) {
+		t.Fatalf("EvaluateWithTrace() error = %v, want %v", err, errFail)
+	}
+	if trace.Err == nil || trace.ShortCircuit || len(trace.Children) != 2 || !errors.Is(trace.Children[1].Err, errFail) {
+		t.Errorf("EvaluateWithTrace() trace =\n%s", trace)
+	}
+}
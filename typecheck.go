@@ -0,0 +1,372 @@
+package gval
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Type is the type of a parameter or value checked by TypeCheck.
+type Type int
+
+const (
+	// AnyType is a value of unknown type. Operations on it are not checked.
+	AnyType Type = iota
+	// NumberType is a number of any Go number type.
+	NumberType
+	// StringType is a string.
+	StringType
+	// BoolType is a bool.
+	BoolType
+	// ArrayType is a slice or array. Its elements are of AnyType.
+	ArrayType
+	// ObjectType is a map or struct. Its fields are of AnyType unless given by the schema.
+	ObjectType
+)
+
+var typeNames = [...]string{"any", "number", "string", "bool", "array", "object"}
+
+func (t Type) String() string {
+	if t < 0 || int(t) >= len(typeNames) {
+		return fmt.Sprintf("Type(%d)", int(t))
+	}
+	return typeNames[t]
+}
+
+// TypeError is a type mismatch found by TypeCheck.
+type TypeError struct {
+	// Pos and End are the byte offsets of the mismatching operand or operation in the expression,
+	// Line and Column the position of Pos starting at 1.
+	Pos, End     int
+	Line, Column int
+	// Message describes the mismatch, e.g. comparing string to number.
+	Message string
+}
+
+func (err *TypeError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+// TypeErrors are all type mismatches found by TypeCheck in the order of their operations.
+type TypeErrors []*TypeError
+
+func (errs TypeErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// TypeCheck parses the expression and reports type mismatches without evaluating it,
+// so expressions can be validated before they are deployed.
+// The schema maps the dotted paths of the parameters, e.g. "order.total", to their types.
+// Fields of objects and elements of arrays not given by the schema are of AnyType.
+//
+// TypeCheck returns the syntax error of an invalid expression or TypeErrors reporting
+// operands of operators and the ternary operator with unexpected types, e.g. "a" - 1 or !1,
+// comparisons of different types, e.g. comparing string to number,
+// variables missing in the schema and calls of unknown functions.
+// Calls of functions with the wrong number of arguments fail to parse.
+// Operators and prefixes unknown to TypeCheck, e.g. of custom Languages, evaluate to AnyType.
+func (l Language) TypeCheck(expression string, schema map[string]Type) error {
+	node, err := l.Parse(expression)
+	if err != nil {
+		return err
+	}
+	tc := &typeChecker{language: l, schema: schema, expression: expression}
+	tc.check(node)
+	if len(tc.errs) > 0 {
+		return tc.errs
+	}
+	return nil
+}
+
+// ResultType returns the type the expression evaluates to as far as it is known without evaluating it,
+// e.g. BoolType for a > 1 && b, NumberType for -x and AnyType for a variable of unknown type,
+// so callers can require rules to yield bool before they are deployed.
+// The schema gives the types of the parameters like for TypeCheck, it may be nil.
+// ResultType only fails for invalid expressions, type mismatches are reported by TypeCheck.
+func (l Language) ResultType(expression string, schema map[string]Type) (Type, error) {
+	node, err := l.Parse(expression)
+	if err != nil {
+		return AnyType, err
+	}
+	tc := &typeChecker{language: l, schema: schema, expression: expression}
+	return tc.check(node), nil
+}
+
+type typeChecker struct {
+	language Language
+	schema   map[string]Type
+	// closed are the paths of objects without fields missing in the schema
+	closed     map[string]bool
+	expression string
+	errs       TypeErrors
+}
+
+func (tc *typeChecker) errorf(n *Node, format string, args ...interface{}) {
+	line, column := position(tc.expression, n.Pos)
+	tc.errs = append(tc.errs, &TypeError{
+		Pos: n.Pos, End: n.End,
+		Line: line, Column: column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// expect reports n of type t unless t is one of the types or AnyType.
+func (tc *typeChecker) expect(n *Node, t Type, types ...Type) bool {
+	if t == AnyType {
+		return true
+	}
+	names := make([]string, len(types))
+	for i, want := range types {
+		if t == want {
+			return true
+		}
+		names[i] = want.String()
+	}
+	tc.errorf(n, "expected %s but got %s %s", strings.Join(names, " or "), t, n.Text())
+	return false
+}
+
+// check returns the type of n and reports the mismatches in it.
+func (tc *typeChecker) check(n *Node) Type {
+	if n.Kind == ConstantNode {
+		return typeOfValue(n.Value)
+	}
+	t := AnyType
+	switch n.Kind {
+	case VariableNode:
+		t = tc.variable(n)
+	case InfixNode:
+		t = tc.infix(n, tc.check(n.Children[0]), tc.check(n.Children[1]))
+	case PostfixNode:
+		t = tc.postfix(n)
+	case OperandNode:
+		t = tc.operand(n)
+	}
+	if n.constant {
+		return typeOfValue(n.Value)
+	}
+	return t
+}
+
+func (tc *typeChecker) variable(n *Node) Type {
+	for i := len(n.Path); i > 0; i-- {
+		path := strings.Join(n.Path[:i], ".")
+		t, ok := tc.schema[path]
+		switch {
+		case !ok:
+			continue
+		case i == len(n.Path):
+			return t
+		case t == NumberType || t == StringType || t == BoolType:
+			tc.errorf(n, "%s is a %s without field %s", path, t, n.Path[i])
+		case t == ObjectType && tc.closed[path]:
+			tc.errorf(n, "unknown field %s of %s", n.Path[i], path)
+		}
+		return AnyType
+	}
+	tc.errorf(n, "unknown variable %s", strings.Join(n.Path, "."))
+	return AnyType
+}
+
+func (tc *typeChecker) infix(n *Node, a, b Type) Type {
+	x, y := n.Children[0], n.Children[1]
+	switch n.Operator {
+	case "+":
+		if a == StringType || b == StringType {
+			return StringType
+		}
+		if tc.expect(x, a, NumberType) && tc.expect(y, b, NumberType) && a == b {
+			return NumberType
+		}
+	case "-", "*", "/", "%", "**", "&", "|", "^", "<<", ">>":
+		tc.expect(x, a, NumberType)
+		tc.expect(y, b, NumberType)
+		return NumberType
+	case "<", "<=", ">", ">=":
+		if tc.compare(n, a, b) {
+			tc.expect(x, a, NumberType, StringType, ObjectType)
+			tc.expect(y, b, NumberType, StringType, ObjectType)
+		}
+		return BoolType
+	case "==", "!=":
+		tc.compare(n, a, b)
+		return BoolType
+	case "&&", "||":
+		tc.expect(x, a, BoolType)
+		tc.expect(y, b, BoolType)
+		return BoolType
+	case "in":
+		tc.expect(y, b, ArrayType, ObjectType)
+		return BoolType
+	case "=~", "!~":
+		tc.expect(x, a, StringType)
+		tc.expect(y, b, StringType)
+		return BoolType
+	case "??":
+		if a == b {
+			return a
+		}
+	}
+	return AnyType
+}
+
+// compare reports the comparison n of different types a and b.
+func (tc *typeChecker) compare(n *Node, a, b Type) bool {
+	if a != AnyType && b != AnyType && a != b {
+		tc.errorf(n, "comparing %s to %s", a, b)
+		return false
+	}
+	return true
+}
+
+func (tc *typeChecker) postfix(n *Node) Type {
+	types := make([]Type, len(n.Children))
+	for i, child := range n.Children {
+		types[i] = tc.check(child)
+	}
+	if n.Operator != "?" {
+		return AnyType
+	}
+	tc.expect(n.Children[0], types[0], BoolType)
+	if len(types) == 3 && types[1] == types[2] {
+		return types[1]
+	}
+	return AnyType
+}
+
+func (tc *typeChecker) operand(n *Node) Type {
+	types := make([]Type, len(n.Children))
+	for i, child := range n.Children {
+		types[i] = tc.check(child)
+	}
+	text := strings.TrimSpace(n.source[n.start:n.end])
+	if name, ok := callee(text); ok {
+		t := tc.call(n, name)
+		if callEnd(text) < len(text) {
+			// fields selected from the result like lookup(id).status
+			return AnyType
+		}
+		return t
+	}
+	switch {
+	case strings.HasPrefix(text, "-") && len(types) == 1:
+		tc.expect(n.Children[0], types[0], NumberType)
+		return NumberType
+	case strings.HasPrefix(text, "!") && len(types) == 1:
+		tc.expect(n.Children[0], types[0], BoolType)
+		return BoolType
+	// literals followed by selectors like [1, 2][0] are of any type
+	case strings.HasPrefix(text, "[") && closingEnd(text, '[', ']') == len(text):
+		return ArrayType
+	case strings.HasPrefix(text, "{") && closingEnd(text, '{', '}') == len(text):
+		return ObjectType
+	}
+	return AnyType
+}
+
+// call returns the result type of the function name.
+func (tc *typeChecker) call(n *Node, name string) Type {
+	t, ok := tc.language.functions[name]
+	if !ok {
+		if _, ok := tc.language.prefixes[name]; ok || strings.Contains(name, ".") {
+			// other prefix extensions like has(a.b) and methods are not checked
+			return AnyType
+		}
+		if _, ok := tc.schema[name]; !ok {
+			tc.errorf(n, "unknown function %s", name)
+		}
+		return AnyType
+	}
+	if t == nil || t.Kind() != reflect.Func {
+		return AnyType
+	}
+	if t.NumOut() == 0 {
+		return AnyType
+	}
+	return typeOf(t.Out(0))
+}
+
+// callee returns the name of the function called by text like f(x) or ns.f(x).
+// A dotted name may also be a method of a variable.
+func callee(text string) (string, bool) {
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.'
+	})
+	if end <= 0 || unicode.IsDigit(rune(text[0])) || text[0] == '.' {
+		return "", false
+	}
+	if !strings.HasPrefix(strings.TrimSpace(text[end:]), "(") {
+		return "", false
+	}
+	return text[:end], true
+}
+
+// callEnd returns the offset after the arguments of the call starting text.
+func callEnd(text string) int {
+	return closingEnd(text, '(', ')')
+}
+
+// closingEnd returns the offset after the close matching the first open in text.
+func closingEnd(text string, open, close byte) int {
+	depth := 0
+	for i := strings.IndexByte(text, open); i >= 0 && i < len(text); i++ {
+		switch text[i] {
+		case '"', '\'', '`':
+			i = closingQuote(text, i) - 1
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(text)
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// arity returns the minimal and maximal number of arguments of the function type t,
+// -1 as maximum of variadic functions.
+func arity(t reflect.Type) (min, max int) {
+	n := t.NumIn()
+	if n > 0 && t.In(0) == contextType {
+		n--
+	}
+	if t.IsVariadic() {
+		return n - 1, -1
+	}
+	return n, n
+}
+
+func typeOfValue(v interface{}) Type {
+	if v == nil {
+		return AnyType
+	}
+	return typeOf(reflect.TypeOf(v))
+}
+
+func typeOf(t reflect.Type) Type {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return NumberType
+	case reflect.String:
+		return StringType
+	case reflect.Bool:
+		return BoolType
+	case reflect.Slice, reflect.Array:
+		return ArrayType
+	case reflect.Map:
+		return ObjectType
+	}
+	return AnyType
+}
@@ -0,0 +1,115 @@
+package gval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLanguage_TypeCheck(t *testing.T) {
+	l := Full(Math(), Let(), Function("plus", func(a, b float64) float64 { return a + b }), Function("split", strings.Split))
+	schema := map[string]Type{
+		"name":         StringType,
+		"age":          NumberType,
+		"active":       BoolType,
+		"tags":         ArrayType,
+		"order":        ObjectType,
+		"order.total":  NumberType,
+		"order.status": StringType,
+		"callback":     AnyType,
+	}
+	for _, expression := range []string{
+		`name == "x" && age >= 18`,
+		`order.total * 1.19 > 100 || order.status == "open"`,
+		"order.items[0].price > 1",
+		`"a" in tags`,
+		`name + age`,
+		`active ? age : 0`,
+		"-age < abs(age - 10)",
+		"plus(age, 1) > 2",
+		"callback(1)",
+		"(age ?? 0) + 1",
+		`name =~ "^a"`,
+		"!active",
+		"date(name) > date(order.status)",
+		"let x = age * 2; x + 1",
+		`split(name, " ")[0] == name`,
+		"[1, 2][0] > 0",
+		`{"a": 1}.a > 0`,
+		"[1][0] * 2",
+		"[age, 2][0] > 0",
+		`{"a": age}["a"] * 2`,
+		`["]"][0] == name`,
+	} {
+		if err := l.TypeCheck(expression, schema); err != nil {
+			t.Errorf("TypeCheck(%s) = %v", expression, err)
+		}
+	}
+	for expression, want := range map[string]string{
+		`name > 5`:              "1:1: comparing string to number",
+		`"1" == 1`:              "1:1: comparing string to number",
+		`age == "18"`:           "1:1: comparing number to string",
+		`name - 1`:              "1:1: expected number but got string name",
+		`active && age`:         "1:11: expected bool but got number age",
+		`age ? 1 : 2`:           "1:1: expected bool but got number age",
+		`!age`:                  "1:2: expected bool but got number age",
+		`unknown > 1`:           "1:1: unknown variable unknown",
+		`name.first == "a"`:     "1:1: name is a string without field first",
+		`foo(1)`:                "1:1: unknown function foo",
+		`1 in age`:              "1:6: expected array or object but got number age",
+		`age > 1 && name > age`: "1:12: comparing string to number",
+		`(name + 1) * 2 > "a"`:  "1:1: expected number but got string (name + 1); 1:1: comparing number to string",
+	} {
+		err := l.TypeCheck(expression, schema)
+		var errs TypeErrors
+		if !errors.As(err, &errs) {
+			t.Errorf("TypeCheck(%s) = %v, want TypeErrors", expression, err)
+			continue
+		}
+		if got := err.Error(); got != want {
+			t.Errorf("TypeCheck(%s) = %s, want %s", expression, got, want)
+		}
+	}
+	for _, expression := range []string{"1 +", "plus(1)"} {
+		if err := l.TypeCheck(expression, schema); err == nil || strings.HasPrefix(err.Error(), "1:") {
+			t.Errorf("TypeCheck(%s) = %v, want syntax error", expression, err)
+		}
+	}
+}
+
+func TestLanguage_ResultType(t *testing.T) {
+	l := Full(Function("score", func(x float64) float64 { return x }))
+	schema := map[string]Type{"order.total": NumberType, "name": StringType, "vip": BoolType}
+	for expression, want := range map[string]Type{
+		`order.total > 100 && vip`:     BoolType,
+		`!vip`:                         BoolType,
+		`score(order.total) * 2`:       NumberType,
+		`-order.total`:                 NumberType,
+		`name + "!"`:                   StringType,
+		`"a" in [name]`:                BoolType,
+		`[1, 2]`:                       ArrayType,
+		`{"a": 1}`:                     ObjectType,
+		`vip ? 1 : 2`:                  NumberType,
+		`unknown`:                      AnyType,
+		`order.items`:                  AnyType,
+		`vip ? name : order.total`:     AnyType,
+		`order.total ?? 0`:             NumberType,
+		`1 + 2`:                        NumberType,
+		`order.total > 1 ? true : nil`: AnyType,
+		`[1][0]`:                       AnyType,
+		`[order.total][0]`:             AnyType,
+		`{"a": name}.a`:                AnyType,
+		`["]", "["]`:                   ArrayType,
+	} {
+		got, err := l.ResultType(expression, schema)
+		if err != nil || got != want {
+			t.Errorf("ResultType(%s) = %s, %v, want %s", expression, got, err, want)
+		}
+	}
+	if got, err := l.ResultType(`a > 1`, nil); err != nil || got != BoolType {
+		t.Errorf("ResultType() without schema = %s, %v, want bool", got, err)
+	}
+	if _, err := l.ResultType(`a >`, schema); err == nil {
+		t.Error("ResultType() of an invalid expression expected error")
+	}
+}
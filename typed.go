@@ -0,0 +1,98 @@
+package gval
+
+import (
+	"context"
+)
+
+// floatEvaluable evaluates to a float64 without boxing it into an interface{}.
+// If the value is no float64, ok is false and the value is returned as v.
+type floatEvaluable func(c context.Context, parameter interface{}) (f float64, v interface{}, ok bool, err error)
+
+// infixFloatOperator returns a Language with a number operator yielding float64.
+// Unlike InfixNumberOperator its results are passed unboxed to following number operators,
+// so chained arithmetic like (a * b / 100) >= 90 only boxes the final result into an interface{}.
+func infixFloatOperator(name string, f func(a, b float64) float64) Language {
+	return newLanguageOperator(name, &infix{
+		number: func(a, b float64) (interface{}, error) { return f(a, b), nil },
+		float:  f,
+	})
+}
+
+// buildFloat builds the infix operation of a on b passing float64 operands unboxed.
+// It returns false if the operation has no typed fast path.
+// Operations of number operators like + yield a floatEvaluable as well.
+func (a stage) buildFloat(b stage) (Evaluable, floatEvaluable, bool) {
+	op := a.op
+	if op == nil || op.number == nil || op.shortCircuit != nil || (op.float == nil && a.float == nil && b.float == nil) {
+		return nil, nil, false
+	}
+	x, y := a.floatEvaluable(), b.floatEvaluable()
+	if op.float == nil {
+		return func(c context.Context, parameter interface{}) (interface{}, error) {
+			fx, vx, okx, err := x(c, parameter)
+			if err != nil {
+				return nil, err
+			}
+			fy, vy, oky, err := y(c, parameter)
+			if err != nil {
+				return nil, err
+			}
+			if okx && oky {
+				return op.number(fx, fy)
+			}
+			return op.f(boxFloat(fx, vx, okx), boxFloat(fy, vy, oky))
+		}, nil, true
+	}
+	float := func(c context.Context, parameter interface{}) (float64, interface{}, bool, error) {
+		fx, vx, okx, err := x(c, parameter)
+		if err != nil {
+			return 0, nil, false, err
+		}
+		fy, vy, oky, err := y(c, parameter)
+		if err != nil {
+			return 0, nil, false, err
+		}
+		if okx && oky {
+			return op.float(fx, fy), nil, true, nil
+		}
+		v, err := op.f(boxFloat(fx, vx, okx), boxFloat(fy, vy, oky))
+		if f, ok := v.(float64); ok {
+			return f, nil, true, err
+		}
+		return 0, v, false, err
+	}
+	return func(c context.Context, parameter interface{}) (interface{}, error) {
+		f, v, ok, err := float(c, parameter)
+		if err != nil {
+			return nil, err
+		}
+		return boxFloat(f, v, ok), nil
+	}, float, true
+}
+
+// floatEvaluable returns the typed form of the stage.
+func (s stage) floatEvaluable() floatEvaluable {
+	if s.float != nil {
+		return s.float
+	}
+	eval := s.Evaluable
+	if eval.IsConst() {
+		v, _ := eval(nil, nil)
+		f, ok := v.(float64)
+		return func(context.Context, interface{}) (float64, interface{}, bool, error) {
+			return f, v, ok, nil
+		}
+	}
+	return func(c context.Context, parameter interface{}) (float64, interface{}, bool, error) {
+		v, err := eval(c, parameter)
+		f, ok := v.(float64)
+		return f, v, ok, err
+	}
+}
+
+func boxFloat(f float64, v interface{}, ok bool) interface{} {
+	if ok {
+		return f
+	}
+	return v
+}
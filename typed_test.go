@@ -0,0 +1,83 @@
+package gval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTypedNumberStages(t *testing.T) {
+	tests := []evaluationTest{
+		{
+			name:       "chained arithmetic",
+			expression: "(a * b / 10 + 2 ** 3) * 1.5 - a",
+			parameter:  map[string]interface{}{"a": 99., "b": 90.},
+			want:       1249.5,
+		},
+		{
+			name:       "comparison of typed stage",
+			expression: "(a * b / 100) >= 90",
+			parameter:  map[string]interface{}{"a": 99., "b": 90.},
+			want:       false,
+		},
+		{
+			name:       "int parameter",
+			expression: "a * b + 1",
+			parameter:  map[string]interface{}{"a": 3, "b": int64(4)},
+			want:       13.,
+		},
+		{
+			name:       "text operand",
+			expression: `(a + 1) + "px"`,
+			parameter:  map[string]interface{}{"a": 1.},
+			want:       "2px",
+		},
+		{
+			name:       "text result",
+			expression: `a + b + 1`,
+			parameter:  map[string]interface{}{"a": "x", "b": "y"},
+			want:       "xy1",
+		},
+		{
+			name:       "decimal operand",
+			expression: `(a * 2) - 1`,
+			extension:  NewLanguage(Full(), DecimalArithmetic()),
+			parameter:  map[string]interface{}{"a": decimal.NewFromFloat(1.5)},
+			want:       decimal.NewFromFloat(2),
+			equalityFunc: func(x, y interface{}) bool {
+				return x.(decimal.Decimal).Equal(y.(decimal.Decimal))
+			},
+		},
+		{
+			name:       "bitmask",
+			expression: `(a | 4) & 6`,
+			parameter:  map[string]interface{}{"a": 3.},
+			want:       6.,
+		},
+		{
+			name:       "invalid operand",
+			expression: `(a * 2) - true`,
+			parameter:  map[string]interface{}{"a": 1.},
+			wantErr:    "invalid operation (float64) - (bool)",
+		},
+	}
+	testEvaluate(tests, t)
+}
+
+func TestTypedNumberStages_allocations(t *testing.T) {
+	eval, err := Full().NewEvaluable("(requests_made * requests_succeeded / 100) >= 90")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parameter := map[string]interface{}{
+		"requests_made":      99.0,
+		"requests_succeeded": 90.0,
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		eval(context.Background(), parameter)
+	})
+	if allocs != 0 {
+		t.Errorf("evaluation allocates %v times, want 0", allocs)
+	}
+}
@@ -0,0 +1,147 @@
+package gval
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Unit is a unit of measurement of quantities written like "1.5GiB" with the Units language.
+type Unit struct {
+	Name string
+	// Dimension is the measured property like bytes, quantities of different dimensions can not be combined.
+	Dimension string
+	// Factor converts a quantity in the unit to the base unit of its dimension, e.g. 1024 for KiB in bytes.
+	Factor float64
+}
+
+// ByteUnits are decimal and binary units of bytes in the base unit B.
+var ByteUnits = []Unit{
+	{"B", "bytes", 1},
+	{"KB", "bytes", 1e3}, {"MB", "bytes", 1e6}, {"GB", "bytes", 1e9}, {"TB", "bytes", 1e12}, {"PB", "bytes", 1e15},
+	{"KiB", "bytes", 1 << 10}, {"MiB", "bytes", 1 << 20}, {"GiB", "bytes", 1 << 30}, {"TiB", "bytes", 1 << 40}, {"PiB", "bytes", 1 << 50},
+}
+
+// DurationUnits are units of durations in the base unit ns like time.Duration.
+var DurationUnits = []Unit{
+	{"ns", "duration", 1}, {"us", "duration", 1e3}, {"µs", "duration", 1e3}, {"ms", "duration", 1e6},
+	{"s", "duration", 1e9}, {"min", "duration", 60e9}, {"h", "duration", 3600e9}, {"d", "duration", 86400e9},
+}
+
+// PercentUnits is the unit % of fractions, e.g. "50%" is 0.5.
+var PercentUnits = []Unit{
+	{"%", "percent", 0.01},
+}
+
+// Units returns a Language computing with quantities of the given units or, without units,
+// of ByteUnits, DurationUnits and PercentUnits.
+//
+// Units with an identifier of more than one letter as name are constants of their factor, so 5 * MB > 4096 * KB.
+// Strings like "1.5GiB" or "90 s" are quantities for +, -, <, <=, > and >=, which evaluate them in the base unit,
+// e.g. "1.5GiB" + "512MiB" is 2147483648 and "90s" > 1 * min is true.
+// Combining quantity strings of different dimensions like "1GiB" + "1s" fails.
+// Other strings are added and compared like in Full.
+func Units(units ...Unit) Language {
+	if len(units) == 0 {
+		units = append(append(append([]Unit{}, ByteUnits...), DurationUnits...), PercentUnits...)
+	}
+	table := make(unitTable, len(units))
+	var languages []Language
+	for _, u := range units {
+		table[u.Name] = u
+		if len([]rune(u.Name)) > 1 && isIdentifier(u.Name) {
+			languages = append(languages, Constant(u.Name, u.Factor))
+		}
+	}
+	for _, op := range quantityOperators {
+		languages = append(languages,
+			InfixTextOperator(op.name, table.textOperator(op)),
+			InfixOperator(op.name, table.operator(op)),
+		)
+	}
+	return NewLanguage(languages...)
+}
+
+// unitTable holds units by name.
+type unitTable map[string]Unit
+
+// quantityOperator is an operator on quantities in their base unit.
+// text is the operation on other strings, nil if it has none.
+type quantityOperator struct {
+	name   string
+	number func(a, b float64) interface{}
+	text   func(a, b string) interface{}
+}
+
+var quantityOperators = []quantityOperator{
+	{"+", func(a, b float64) interface{} { return a + b }, func(a, b string) interface{} { return a + b }},
+	{"-", func(a, b float64) interface{} { return a - b }, nil},
+	{"<", func(a, b float64) interface{} { return a < b }, func(a, b string) interface{} { return a < b }},
+	{"<=", func(a, b float64) interface{} { return a <= b }, func(a, b string) interface{} { return a <= b }},
+	{">", func(a, b float64) interface{} { return a > b }, func(a, b string) interface{} { return a > b }},
+	{">=", func(a, b float64) interface{} { return a >= b }, func(a, b string) interface{} { return a >= b }},
+}
+
+var quantityPattern = regexp.MustCompile(`^\s*([-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?)\s*(\S+)\s*$`)
+
+// quantity returns the value in the base unit and the dimension of a quantity like "1.5GiB".
+func (t unitTable) quantity(s string) (float64, string, bool) {
+	m := quantityPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, "", false
+	}
+	u, ok := t[m[2]]
+	if !ok {
+		return 0, "", false
+	}
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return f * u.Factor, u.Dimension, true
+}
+
+// textOperator returns the operation on two strings.
+func (t unitTable) textOperator(op quantityOperator) func(a, b string) (interface{}, error) {
+	return func(a, b string) (interface{}, error) {
+		x, dx, okx := t.quantity(a)
+		y, dy, oky := t.quantity(b)
+		switch {
+		case okx && oky && dx != dy:
+			return nil, fmt.Errorf("invalid operation %s %s %s of %s and %s", a, op.name, b, dx, dy)
+		case okx && oky:
+			return op.number(x, y), nil
+		case op.text != nil:
+			return op.text(a, b), nil
+		}
+		x, okx = convertToFloat(a)
+		y, oky = convertToFloat(b)
+		if !okx || !oky {
+			return nil, fmt.Errorf("invalid operation (string) %s (string)", op.name)
+		}
+		return op.number(x, y), nil
+	}
+}
+
+// operator returns the operation on a quantity string and a number or, like Full, on other operands converted to strings.
+func (t unitTable) operator(op quantityOperator) func(a, b interface{}) (interface{}, error) {
+	operand := func(o interface{}) (float64, bool, bool) {
+		if s, ok := o.(string); ok {
+			f, _, ok := t.quantity(s)
+			return f, ok, ok
+		}
+		f, ok := convertToFloat(o)
+		return f, false, ok
+	}
+	return func(a, b interface{}) (interface{}, error) {
+		x, qx, okx := operand(a)
+		y, qy, oky := operand(b)
+		if okx && oky && (qx || qy) {
+			return op.number(x, y), nil
+		}
+		if op.text != nil && a != nil && b != nil {
+			return op.text(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b)), nil
+		}
+		return nil, fmt.Errorf("invalid operation (%T) %s (%T)", a, op.name, b)
+	}
+}
@@ -0,0 +1,28 @@
+package gval
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnits(t *testing.T) {
+	l := NewLanguage(Full(), Units())
+	parameter := map[string]interface{}{"disk": 2e9, "timeout": 90 * time.Second, "s": "text"}
+	testEvaluate(
+		[]evaluationTest{
+			{name: "constants", expression: `5 * MB > 4096 * KB`, extension: l, want: true},
+			{name: "binary constants", expression: `2 * KiB`, extension: l, want: 2048.},
+			{name: "quantity strings", expression: `"1.5GiB" + "512MiB"`, extension: l, want: float64(2 << 30)},
+			{name: "subtraction", expression: `"1 h" - "30min"`, extension: l, want: float64(30 * time.Minute)},
+			{name: "comparison", expression: `"1GB" < "1GiB" && "90s" >= "1.5min"`, extension: l, want: true},
+			{name: "mixed", expression: `disk > "1.5GB" && timeout > "1min" && "50%" < 0.6`, extension: l, parameter: parameter, want: true},
+			{name: "dimensions", expression: `"1GiB" + "1s"`, extension: l, wantErr: "invalid operation 1GiB + 1s of bytes and duration"},
+			{name: "text", expression: `"a" + "b" + 1 + s`, extension: l, parameter: parameter, want: "ab1text"},
+			{name: "numeric text", expression: `"3" - "1"`, extension: l, want: 2.},
+			{name: "text comparison", expression: `"10" < "9" && "b" > 1`, extension: l, want: true},
+			{name: "single letter units are no constants", expression: `s`, extension: l, parameter: parameter, want: "text"},
+			{name: "custom table", expression: `"3 racks" > "100 servers" ? 2 * racks : 0`, extension: NewLanguage(Full(), Units(Unit{"racks", "servers", 42}, Unit{"servers", "servers", 1})), want: 84.},
+		},
+		t,
+	)
+}
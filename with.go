@@ -0,0 +1,56 @@
+package gval
+
+import (
+	"context"
+)
+
+// With returns a Language with blocks evaluating their expression against a part of the parameter:
+//
+//	with(order.customer) { vip && balance > 0 }
+//
+// Variables inside the braces are selected from the value in parentheses instead of the parameter,
+// let bindings remain visible. Blocks can be nested. A with that is not called is a variable.
+func With() Language {
+	return with
+}
+
+var with = func() Language {
+	l := newLanguage()
+	l.prefixes[l.makePrefixKey("with")] = parseWith
+	return l
+}()
+
+func parseWith(c context.Context, p *Parser) (Evaluable, error) {
+	_, variable, _ := parseIdent(c, p)
+	if p.Scan() != '(' {
+		p.Camouflage("variable", '(')
+		return variable()
+	}
+	root, err := p.ParseExpression(c)
+	if err != nil {
+		return nil, err
+	}
+	if p.Scan() != ')' {
+		return nil, p.Expected("with", ')')
+	}
+	if p.Scan() != '{' {
+		return nil, p.Expected("with", '{')
+	}
+	body, err := p.ParseExpression(c)
+	if err != nil {
+		return nil, err
+	}
+	if p.Scan() != '}' {
+		return nil, p.Expected("with", '}')
+	}
+	if body.IsConst() {
+		return body, nil
+	}
+	return func(c context.Context, v interface{}) (interface{}, error) {
+		r, err := root(c, v)
+		if err != nil {
+			return nil, err
+		}
+		return body(c, r)
+	}, nil
+}
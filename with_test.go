@@ -0,0 +1,36 @@
+package gval
+
+import (
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	parameter := map[string]interface{}{
+		"order": map[string]interface{}{
+			"customer": map[string]interface{}{
+				"vip":     true,
+				"balance": 10.,
+				"address": map[string]interface{}{"country": "de"},
+			},
+			"items": []interface{}{map[string]interface{}{"price": 3.}},
+		},
+		"with":  "variable",
+		"limit": 5.,
+	}
+	l := NewLanguage(Full(), With())
+	testEvaluate(
+		[]evaluationTest{
+			{name: "block", expression: `with(order.customer) { vip && balance > 0 }`, extension: l, parameter: parameter, want: true},
+			{name: "nested", expression: `with(order) { with(customer.address) { country } + "/" + items[0].price }`, extension: l, parameter: parameter, want: "de/3"},
+			{name: "operand", expression: `with(order.customer) { balance } * 2`, extension: l, parameter: parameter, want: 20.},
+			{name: "let binding", expression: `let limit = limit; with(order.customer) { balance > limit }`, extension: NewLanguage(l, Let()), parameter: parameter, want: true},
+			{name: "re-rooted", expression: `with(order.customer) { limit }`, extension: l, parameter: parameter, want: nil},
+			{name: "constant", expression: `with(order) { 1 + 2 }`, extension: l, parameter: parameter, want: 3.},
+			{name: "variable", expression: `with + "!"`, extension: l, parameter: parameter, want: "variable!"},
+			{name: "failing root", expression: `with(order.customer.vip.x) { a }`, extension: l, parameter: parameter, wantErr: "unknown parameter"},
+			{name: "missing brace", expression: `with(order) vip`, extension: l, wantErr: "unexpected Ident while scanning with expected \"{\""},
+			{name: "unclosed block", expression: `with(order) { vip`, extension: l, wantErr: "unexpected EOF while scanning with expected \"}\""},
+		},
+		t,
+	)
+}
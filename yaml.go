@@ -0,0 +1,67 @@
+package gval
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// NodeDecoder is a document node that is selected like its decoded value, e.g. a *yaml.Node of gopkg.in/yaml.v3.
+// Keys are selected from the result of Decode into an interface{}, so YAML anchors and aliases are resolved
+// and scalars are typed by their tags like !!int or !!bool.
+//
+// Decoded YAML mappings are map[string]interface{} or map[interface{}]interface{}.
+// Keys of the latter that are not strings, like 1 or true, are selected by their text, e.g. ports[8080] or flags.true.
+type NodeDecoder interface {
+	Decode(v interface{}) error
+}
+
+// decoded returns the decoded value of a json.RawMessage or NodeDecoder v, otherwise v.
+// k is the key that is selected from v.
+func decoded(v interface{}, k string) (interface{}, error) {
+	node := v
+	var err error
+	switch o := v.(type) {
+	case json.RawMessage:
+		v, err = decodeRawMessage(o)
+	case NodeDecoder:
+		v, err = decodeNode(o)
+	default:
+		// nodes like yaml.Node implement Decode with a pointer receiver
+		vv := reflect.ValueOf(v)
+		if v == nil || vv.Kind() == reflect.Ptr || !reflect.PtrTo(vv.Type()).Implements(nodeDecoderType) {
+			return v, nil
+		}
+		p := reflect.New(vv.Type())
+		p.Elem().Set(vv)
+		v, err = decodeNode(p.Interface().(NodeDecoder))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to select '%s' on %T: %w", k, node, err)
+	}
+	return v, nil
+}
+
+var nodeDecoderType = reflect.TypeOf((*NodeDecoder)(nil)).Elem()
+
+func decodeNode(n NodeDecoder) (interface{}, error) {
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// interfaceMapValue selects key k from a map with interface{} keys like a decoded YAML mapping.
+// Keys that are not strings are matched by their text.
+func interfaceMapValue(m map[interface{}]interface{}, k string) (interface{}, bool) {
+	if v, ok := m[k]; ok {
+		return v, true
+	}
+	for key, v := range m {
+		if _, isString := key.(string); !isString && fmt.Sprint(key) == k {
+			return v, true
+		}
+	}
+	return nil, false
+}
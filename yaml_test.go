@@ -0,0 +1,79 @@
+package gval
+
+import (
+	"errors"
+	"testing"
+)
+
+// testNode decodes to its value like a yaml.Node
+type testNode struct {
+	value interface{}
+	err   error
+}
+
+func (n *testNode) Decode(v interface{}) error {
+	if n.err != nil {
+		return n.err
+	}
+	*v.(*interface{}) = n.value
+	return nil
+}
+
+func TestNodeDecoder(t *testing.T) {
+	config := map[interface{}]interface{}{
+		"service": map[interface{}]interface{}{
+			"replicas": 3,
+			"ports":    map[interface{}]interface{}{8080: "http", 8443: "https"},
+			"flags":    map[interface{}]interface{}{true: "on"},
+		},
+		"regions": []interface{}{"eu", "us"},
+	}
+	testEvaluate(
+		[]evaluationTest{
+			{
+				name:       "node",
+				expression: `config.service.replicas > 2`,
+				parameter:  map[string]interface{}{"config": &testNode{value: config}},
+				want:       true,
+			},
+			{
+				name:       "node value",
+				expression: `config.regions[1]`,
+				parameter:  map[string]interface{}{"config": testNode{value: config}},
+				want:       "us",
+			},
+			{
+				name:       "integer key",
+				expression: `config.service.ports[8443]`,
+				parameter:  map[string]interface{}{"config": config},
+				want:       "https",
+			},
+			{
+				name:       "bool key",
+				expression: `config.service.flags["true"]`,
+				parameter:  map[string]interface{}{"config": config},
+				want:       "on",
+			},
+			{
+				name:       "presence",
+				expression: `exists(config.service.ports[80])`,
+				parameter:  map[string]interface{}{"config": &testNode{value: config}},
+				want:       false,
+			},
+			{
+				name:       "missing field as nil",
+				expression: `config.service.ports[8080]`,
+				extension:  MissingFieldAsNil(),
+				parameter:  map[string]interface{}{"config": &testNode{value: config}},
+				want:       "http",
+			},
+			{
+				name:       "decode error",
+				expression: `config.service`,
+				parameter:  map[string]interface{}{"config": &testNode{err: errors.New("invalid alias")}},
+				wantErr:    "failed to select 'service' on *gval.testNode: invalid alias",
+			},
+		},
+		t,
+	)
+}